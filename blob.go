@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ironcoreimage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/ironcore-dev/ironcore-image/oci/lease"
+	"github.com/ironcore-dev/ironcore-image/oci/local"
+	"github.com/ironcore-dev/ironcore-image/oci/store"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// LayerBlob is the verified on-disk blob of an image layer.
+// The blob is pinned by a lease until Release is called.
+type LayerBlob struct {
+	// Path is the location of the blob on the local file system. The file must not be modified.
+	// For compressed layers, the file holds the compressed content.
+	Path string
+	// Compression is the compression of the blob content.
+	Compression Compression
+	// Descriptor is the descriptor of the layer.
+	Descriptor ocispec.Descriptor
+	// LeaseID is the ID of the lease pinning the blob.
+	LeaseID string
+
+	leases    *lease.Manager
+	ownsLease bool
+}
+
+// Release releases the pin on the blob. If the lease was created by OpenLayerBlob, it is deleted.
+// The Path must not be used after calling Release.
+func (b *LayerBlob) Release(ctx context.Context) error {
+	if !b.ownsLease {
+		return nil
+	}
+	if err := b.leases.Delete(ctx, b.LeaseID); err != nil && !errors.Is(err, lease.ErrNotFound) {
+		return fmt.Errorf("error deleting lease %s: %w", b.LeaseID, err)
+	}
+	return nil
+}
+
+// LayerBlobOptions are options for OpenLayerBlob.
+type LayerBlobOptions struct {
+	// Arch is the architecture to select if the ref points to an index.
+	// Defaults to runtime.GOARCH.
+	Arch string
+	// LeaseID is the ID of an existing lease to add the blob to.
+	// If empty, a new lease is created that is deleted on LayerBlob.Release.
+	LeaseID string
+	// VerifyDigest additionally re-hashes the blob content and compares it to the layer digest.
+	VerifyDigest bool
+}
+
+func (o *LayerBlobOptions) SetDefaults() {
+	if o.Arch == "" {
+		o.Arch = runtime.GOARCH
+	}
+}
+
+// OpenLayerBlob resolves the ref in the store and returns the on-disk blob of the layer with the given type.
+//
+// The image manifest and layer are pinned by a lease before the blob is verified, so the blob cannot be
+// removed while it is in use. Callers have to call LayerBlob.Release once they no longer use the blob.
+func OpenLayerBlob(ctx context.Context, s *store.Store, ref string, layerType LayerType, o LayerBlobOptions) (*LayerBlob, error) {
+	o.SetDefaults()
+
+	localStore := s.Layout().Store()
+	if localStore == nil {
+		return nil, fmt.Errorf("store is not backed by the local file system")
+	}
+
+	ociImg, err := s.ResolveArch(ctx, ref, o.Arch)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving ref %s: %w", ref, err)
+	}
+
+	img, err := ResolveImage(ctx, ociImg)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving ironcore image: %w", err)
+	}
+
+	layer := img.Layer(layerType)
+	if layer == nil {
+		return nil, fmt.Errorf("image has no %s layer", layerType)
+	}
+	desc := layer.Descriptor()
+
+	_, compression := SplitMediaType(desc.MediaType)
+	blob := &LayerBlob{
+		Descriptor:  desc,
+		Compression: compression,
+		LeaseID:     o.LeaseID,
+		leases:      s.Leases(),
+	}
+	resources := []digest.Digest{ociImg.Descriptor().Digest, desc.Digest}
+	if blob.LeaseID == "" {
+		l, err := s.Leases().Create(ctx, lease.WithResources(resources...))
+		if err != nil {
+			return nil, fmt.Errorf("error creating lease: %w", err)
+		}
+		blob.LeaseID = l.ID
+		blob.ownsLease = true
+	} else if err := s.Leases().AddResources(ctx, blob.LeaseID, resources...); err != nil {
+		return nil, fmt.Errorf("error adding resources to lease %s: %w", blob.LeaseID, err)
+	}
+
+	path, err := verifyBlob(ctx, localStore, desc, o.VerifyDigest)
+	if err != nil {
+		_ = blob.Release(ctx)
+		return nil, err
+	}
+	blob.Path = path
+	return blob, nil
+}
+
+func verifyBlob(ctx context.Context, s *local.Store, desc ocispec.Descriptor, verifyDigest bool) (string, error) {
+	info, err := s.Info(ctx, desc.Digest)
+	if err != nil {
+		return "", fmt.Errorf("error getting blob info for %s: %w", desc.Digest, err)
+	}
+	if info.Size != desc.Size {
+		return "", fmt.Errorf("blob %s has size %d, expected %d", desc.Digest, info.Size, desc.Size)
+	}
+
+	path, err := s.BlobPath(desc.Digest)
+	if err != nil {
+		return "", fmt.Errorf("error getting blob path for %s: %w", desc.Digest, err)
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("error statting blob %s: %w", desc.Digest, err)
+	}
+	if !stat.Mode().IsRegular() || stat.Size() != desc.Size {
+		return "", fmt.Errorf("blob file %s does not match descriptor %s", path, desc.Digest)
+	}
+
+	if verifyDigest {
+		f, err := os.Open(path)
+		if err != nil {
+			return "", fmt.Errorf("error opening blob %s: %w", desc.Digest, err)
+		}
+		defer func() { _ = f.Close() }()
+
+		actual, err := desc.Digest.Algorithm().FromReader(f)
+		if err != nil {
+			return "", fmt.Errorf("error hashing blob %s: %w", desc.Digest, err)
+		}
+		if actual != desc.Digest {
+			return "", fmt.Errorf("blob %s has digest %s", desc.Digest, actual)
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ironcoreimage_test
+
+import (
+	"context"
+	"os"
+
+	. "github.com/ironcore-dev/ironcore-image"
+	"github.com/ironcore-dev/ironcore-image/oci/imageutil"
+	"github.com/ironcore-dev/ironcore-image/oci/lease"
+	"github.com/ironcore-dev/ironcore-image/oci/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OpenLayerBlob", func() {
+	var (
+		ctx context.Context
+		s   *store.Store
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		var err error
+		s, err = store.New(GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+
+		img, err := imageutil.NewJSONConfigBuilder(Config{}, imageutil.WithMediaType(ConfigMediaType)).
+			BytesLayer([]byte("kernel"), imageutil.WithMediaType(KernelLayerMediaType)).
+			Complete()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Push(ctx, "example.org/foo:bar", img)).To(Succeed())
+	})
+
+	It("should return the pinned blob path of a layer", func() {
+		By("opening the kernel blob")
+		blob, err := OpenLayerBlob(ctx, s, "example.org/foo:bar", KernelLayerType, LayerBlobOptions{VerifyDigest: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.ReadFile(blob.Path)).To(Equal([]byte("kernel")))
+
+		By("inspecting the lease")
+		l, err := s.Leases().Get(ctx, blob.LeaseID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(l.Resources).To(ContainElement(blob.Descriptor.Digest))
+
+		By("releasing the blob")
+		Expect(blob.Release(ctx)).To(Succeed())
+		_, err = s.Leases().Get(ctx, blob.LeaseID)
+		Expect(err).To(MatchError(lease.ErrNotFound))
+	})
+
+	It("should add the blob to an existing lease", func() {
+		l, err := s.Leases().Create(ctx, lease.WithID("machine-1"))
+		Expect(err).NotTo(HaveOccurred())
+
+		blob, err := OpenLayerBlob(ctx, s, "example.org/foo:bar", KernelLayerType, LayerBlobOptions{LeaseID: l.ID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(blob.Release(ctx)).To(Succeed())
+
+		l, err = s.Leases().Get(ctx, "machine-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(l.Resources).To(ContainElement(blob.Descriptor.Digest))
+	})
+
+	It("should error for a missing layer and not leave a lease behind", func() {
+		_, err := OpenLayerBlob(ctx, s, "example.org/foo:bar", InitRAMFSLayerType, LayerBlobOptions{})
+		Expect(err).To(HaveOccurred())
+		Expect(s.Leases().List(ctx)).To(BeEmpty())
+	})
+})
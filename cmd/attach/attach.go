@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package attach
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/ironcore-dev/ironcore-image/oci/imageutil"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+)
+
+// Options are options for attaching an artifact.
+type Options struct {
+	// ArtifactType is the artifact type of the attached artifact.
+	ArtifactType string
+	// MediaType is the media type of the attached files.
+	MediaType string
+	// Annotations are the annotations of the artifact manifest.
+	Annotations map[string]string
+	// Arch selects the manifest of an index to attach to. If empty, the artifact is attached to the ref itself.
+	Arch string
+}
+
+func Command(storeFactory common.StoreFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	var opts Options
+
+	cmd := &cobra.Command{
+		Use:   "attach image[:tag] file...",
+		Short: "Attach files as an artifact referring to a local image, e.g. an SBOM or release notes.",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			ref := args[0]
+			files := args[1:]
+			return Run(ctx, storeFactory, outputOptions, ref, files, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ArtifactType, "artifact-type", "", "Artifact type of the attached artifact, e.g. 'application/spdx+json'.")
+	cmd.Flags().StringVar(&opts.MediaType, "media-type", "application/octet-stream", "Media type of the attached files.")
+	cmd.Flags().StringToStringVar(&opts.Annotations, "annotation", nil, "Annotations of the artifact in the format 'key=value'.")
+	cmd.Flags().StringVar(&opts.Arch, common.RecommendedArchFlagName, "", "Architecture of the manifest to attach to if the image is an index. Leave empty to attach to the index itself.")
+	_ = cmd.MarkFlagRequired("artifact-type")
+	outputOptions.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Result is the result of attaching an artifact.
+type Result struct {
+	// Subject is the digest of the manifest the artifact was attached to.
+	Subject digest.Digest `json:"subject"`
+	// Digest is the digest of the artifact manifest.
+	Digest digest.Digest `json:"digest"`
+	// ArtifactType is the artifact type of the artifact.
+	ArtifactType string `json:"artifactType"`
+}
+
+func Run(ctx context.Context, storeFactory common.StoreFactory, outputOptions *common.OutputOptions, ref string, files []string, opts Options) error {
+	s, err := storeFactory()
+	if err != nil {
+		return fmt.Errorf("could not create store: %w", err)
+	}
+
+	ref, err = common.FuzzyResolveRef(ctx, s, ref)
+	if err != nil {
+		return fmt.Errorf("error resolving source: %w", err)
+	}
+
+	subjectImg, err := s.Resolve(ctx, ref)
+	if opts.Arch != "" {
+		subjectImg, err = s.ResolveArch(ctx, ref, opts.Arch)
+	}
+	if err != nil {
+		return fmt.Errorf("error resolving ref %s: %w", ref, err)
+	}
+	subject := subjectImg.Descriptor()
+
+	annotations := map[string]string{
+		ocispec.AnnotationCreated: time.Now().UTC().Format(time.RFC3339),
+	}
+	for k, v := range opts.Annotations {
+		annotations[k] = v
+	}
+
+	builder := imageutil.NewArtifactBuilder(opts.ArtifactType).
+		Subject(subject).
+		Annotations(annotations)
+	for _, file := range files {
+		builder = builder.FileLayer(file,
+			imageutil.WithMediaType(opts.MediaType),
+			imageutil.WithAnnotations(map[string]string{ocispec.AnnotationTitle: filepath.Base(file)}),
+		)
+	}
+
+	artifact, err := builder.Complete()
+	if err != nil {
+		return fmt.Errorf("error building artifact: %w", err)
+	}
+
+	manifest, err := artifact.Manifest(ctx)
+	if err != nil {
+		return fmt.Errorf("error reading artifact manifest: %w", err)
+	}
+
+	if err := s.PushReferrer(image.WithManifestKeyPrefixes(ctx, manifest), ref, artifact); err != nil {
+		return fmt.Errorf("error storing artifact: %w", err)
+	}
+
+	res := Result{
+		Subject:      subject.Digest,
+		Digest:       artifact.Descriptor().Digest,
+		ArtifactType: opts.ArtifactType,
+	}
+	return outputOptions.Print(res, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Successfully attached %s to %s: %s\n", res.ArtifactType, res.Subject, res.Digest)
+		return err
+	})
+}
@@ -6,15 +6,25 @@ package build
 import (
 	"context"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/opencontainers/go-digest"
 	"github.com/opencontainers/image-spec/specs-go"
 
 	ironcoreimage "github.com/ironcore-dev/ironcore-image"
 	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	"github.com/ironcore-dev/ironcore-image/fs/squashfs"
 	"github.com/ironcore-dev/ironcore-image/oci/image"
 	"github.com/ironcore-dev/ironcore-image/oci/imageutil"
+	"github.com/ironcore-dev/ironcore-image/oci/remote"
+	"github.com/ironcore-dev/ironcore-image/provenance"
+	"github.com/ironcore-dev/ironcore-image/sbom"
+	"github.com/ironcore-dev/ironcore-image/seekable"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 )
@@ -28,6 +38,17 @@ type ArchConfig struct {
 	UKI       *string
 	ISO       *string
 	CMDLine   *string
+	// RootFSDir is a directory to pack into a squashfs image used as rootfs.
+	RootFSDir *string
+	// SquashFSDir is a directory to pack into a squashfs image used as squashfs.
+	SquashFSDir *string
+	// UKIStub is an EFI stub to assemble a UKI from the kernel, initramfs, cmdline and os-release with.
+	UKIStub *string
+	// OSRelease is the os-release file to embed into an assembled UKI.
+	OSRelease *string
+
+	// container is the container image layers were taken from, if any.
+	container *containerSource
 }
 
 type archConfigs []ArchConfig
@@ -63,10 +84,30 @@ func (ac *archConfigs) Set(value string) error {
 			config.ISO = &val
 		case "cmdline":
 			config.CMDLine = &val
+		case "rootfs-dir":
+			config.RootFSDir = &val
+		case "squashfs-dir":
+			config.SquashFSDir = &val
+		case "uki-stub":
+			config.UKIStub = &val
+		case "os-release":
+			config.OSRelease = &val
 		default:
 			return fmt.Errorf("unknown field %q in --config", key)
 		}
 	}
+	if config.RootFS != nil && config.RootFSDir != nil {
+		return fmt.Errorf("rootfs and rootfs-dir are mutually exclusive in --config")
+	}
+	if config.SquashFS != nil && config.SquashFSDir != nil {
+		return fmt.Errorf("squashfs and squashfs-dir are mutually exclusive in --config")
+	}
+	if config.UKI != nil && config.UKIStub != nil {
+		return fmt.Errorf("uki and uki-stub are mutually exclusive in --config")
+	}
+	if config.OSRelease != nil && config.UKIStub == nil {
+		return fmt.Errorf("os-release requires uki-stub in --config")
+	}
 	*ac = append(*ac, config)
 	return nil
 }
@@ -75,12 +116,48 @@ func (ac *archConfigs) Type() string {
 	return "archConfig"
 }
 
-func Command(storeFactory common.StoreFactory) *cobra.Command {
-	var (
-		tagName string
+// Options are options for building an image.
+type Options struct {
+	// Tag is the local reference to tag the index with.
+	Tag string
+	// SubManifestTagTemplate is the template for tagging the per-architecture manifests.
+	SubManifestTagTemplate string
+	// Annotations are the annotations of the index.
+	Annotations map[string]string
+	// SBOMFormat is the format of the SBOMs to generate for the root file systems. Empty disables SBOM generation.
+	SBOMFormat string
+	// Provenance enables attaching a provenance statement to the index.
+	Provenance bool
+	// BuilderID is the builder id recorded in the provenance statement.
+	BuilderID string
+	// Compression is the compression of the layers listed in CompressLayers. Empty stores all layers uncompressed.
+	Compression string
+	// CompressLayers are the types of the layers to compress.
+	CompressLayers []string
+	// Seekable stores zstd compressed layers in the zstd seekable format, allowing random access.
+	Seekable bool
+	// UIDMaps map the user ids of the files of packed directories, in the format 'id:hostID:size'.
+	UIDMaps []string
+	// GIDMaps map the group ids of the files of packed directories, in the format 'id:hostID:size'.
+	GIDMaps []string
+	// SourceDateEpoch is the modification time in seconds since the epoch to set on the files of packed directories.
+	// Empty keeps their modification times.
+	SourceDateEpoch string
+	// FromContainer is the reference of a container image whose root file system to use as rootfs.
+	FromContainer string
+	// ContainerKernel is the path of the kernel in the container image. Empty looks for it at conventional locations.
+	ContainerKernel string
+	// ContainerInitRAMFS is the path of the initramfs in the container image. Empty looks for it at conventional locations.
+	ContainerInitRAMFS string
+	// FormatCheck is how to handle layer files whose detected format does not match their layer type:
+	// "error" fails the build, "warn" reports a warning and "none" skips the check.
+	FormatCheck string
+}
 
+func Command(storeFactory common.StoreFactory, registryFactory common.RemoteRegistryFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	var (
+		opts        Options
 		archConfigs archConfigs
-		annotations map[string]string
 	)
 
 	cmd := &cobra.Command{
@@ -88,45 +165,201 @@ func Command(storeFactory common.StoreFactory) *cobra.Command {
 		Short: "Build an image and store it to the local store with an optional tag.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
-			return Run(ctx, storeFactory, tagName, archConfigs, annotations)
+			return Run(ctx, storeFactory, registryFactory, outputOptions, archConfigs, opts)
 		},
 	}
 
-	cmd.Flags().StringVar(&tagName, "tag", "", "Optional tag of image.")
+	cmd.Flags().StringVar(&opts.Tag, "tag", "", "Optional tag of image.")
 	cmd.Flags().Var(&archConfigs, "config", "Architecture-specific configuration in the format 'arch=amd64,rootfs=path,initramfs=path'. Can be specified multiple times.")
-	cmd.Flags().StringToStringVar(&annotations, "annotations", nil, "Annotations for the IndexManifest in the format 'key=value'. Can specify multiple key-value pairs.")
+	common.AddSubManifestTagFlag(cmd.Flags(), &opts.SubManifestTagTemplate)
+	cmd.Flags().StringToStringVar(&opts.Annotations, "annotations", nil, "Annotations for the IndexManifest in the format 'key=value'. Can specify multiple key-value pairs.")
+	cmd.Flags().StringVar(&opts.SBOMFormat, "sbom", "", fmt.Sprintf("Generate an SBOM of the packages installed in the rootfs / squashfs of each architecture and attach it to its manifest. One of %v.", sbom.Formats))
+	cmd.Flags().StringVar(&opts.Compression, "compression", "", fmt.Sprintf("Compression of the layers selected by --compress-layers. One of %v.", ironcoreimage.Compressions))
+	cmd.Flags().StringSliceVar(&opts.CompressLayers, "compress-layers", []string{string(ironcoreimage.RootFSLayerType), string(ironcoreimage.ISOLayerType)}, "Types of the layers to compress if --compression is set.")
+	cmd.Flags().BoolVar(&opts.Seekable, "seekable", false, "Store zstd compressed layers in the zstd seekable format, so they can be read lazily via HTTP range requests. Requires --compression zstd.")
+	cmd.Flags().BoolVar(&opts.Provenance, "provenance", false, "Attach an in-toto SLSA provenance statement of the build to the index.")
+	cmd.Flags().StringVar(&opts.BuilderID, "builder-id", provenance.DefaultBuilderID, "Builder id to record in the provenance statement.")
+	cmd.Flags().StringSliceVar(&opts.UIDMaps, "dir-uid-map", nil, "Map the user ids of the files of rootfs-dir / squashfs-dir in the format 'id:hostID:size'. Can be specified multiple times.")
+	cmd.Flags().StringSliceVar(&opts.GIDMaps, "dir-gid-map", nil, "Map the group ids of the files of rootfs-dir / squashfs-dir in the format 'id:hostID:size'. Can be specified multiple times.")
+	cmd.Flags().StringVar(&opts.SourceDateEpoch, "source-date-epoch", os.Getenv("SOURCE_DATE_EPOCH"), "Modification time (seconds since the epoch) to set on the files of rootfs-dir / squashfs-dir / --from-container and to record as SBOM creation time. Defaults to $SOURCE_DATE_EPOCH.")
+	cmd.Flags().StringVar(&opts.FromContainer, "from-container", "", "Reference of a container image to flatten into the rootfs of each architecture, e.g. docker.io/library/debian:12. Kernel and initramfs are taken from the container unless configured.")
+	cmd.Flags().StringVar(&opts.ContainerKernel, "container-kernel", "", "Path of the kernel in the --from-container image. Defaults to conventional locations such as /boot/vmlinuz.")
+	cmd.Flags().StringVar(&opts.FormatCheck, "format-check", formatCheckError, fmt.Sprintf("How to handle layer files whose detected format does not match their layer type. One of %v.", formatChecks))
+	cmd.Flags().StringVar(&opts.ContainerInitRAMFS, "container-initramfs", "", "Path of the initramfs in the --from-container image. Defaults to conventional locations such as /boot/initrd.img.")
+	outputOptions.AddFlags(cmd.Flags())
 
 	return cmd
 }
 
+// Manifest is a built per-architecture image manifest.
+type Manifest struct {
+	// Arch is the architecture of the manifest.
+	Arch string `json:"arch"`
+	// Ref is the local reference the manifest is tagged with. Empty if the manifest is not tagged.
+	Ref string `json:"ref,omitempty"`
+	// Digest is the digest of the manifest.
+	Digest digest.Digest `json:"digest"`
+	// SBOM is the digest of the SBOM artifact attached to the manifest, if any.
+	SBOM digest.Digest `json:"sbom,omitempty"`
+}
+
+// Result is the result of building an image.
+type Result struct {
+	// Tag is the local reference the index is tagged with.
+	Tag string `json:"tag"`
+	// Digest is the digest of the index.
+	Digest digest.Digest `json:"digest"`
+	// Manifests are the built per-architecture manifests.
+	Manifests []Manifest `json:"manifests"`
+	// Provenance is the digest of the provenance artifact attached to the index, if any.
+	Provenance digest.Digest `json:"provenance,omitempty"`
+}
+
 func Run(
 	ctx context.Context,
 	storeFactory common.StoreFactory,
-	tagName string,
+	registryFactory common.RemoteRegistryFactory,
+	outputOptions *common.OutputOptions,
 	archConfigs archConfigs,
-	annotations map[string]string,
+	opts Options,
 ) error {
+	startedOn := time.Now()
+
+	var sbomFormat sbom.Format
+	if opts.SBOMFormat != "" {
+		var err error
+		if sbomFormat, err = sbom.ParseFormat(opts.SBOMFormat); err != nil {
+			return err
+		}
+	}
+
+	lc, err := parseLayerCompression(opts)
+	if err != nil {
+		return err
+	}
+	writeOpts, err := parseWriteOptions(opts)
+	if err != nil {
+		return err
+	}
+	if err := parseFormatCheck(opts.FormatCheck); err != nil {
+		return err
+	}
+	var registry *remote.Registry
+	if opts.FromContainer != "" {
+		if archConfigs, err = containerArchConfigs(archConfigs); err != nil {
+			return err
+		}
+		if registry, err = registryFactory(); err != nil {
+			return fmt.Errorf("could not create remote registry: %w", err)
+		}
+	} else if opts.ContainerKernel != "" || opts.ContainerInitRAMFS != "" {
+		return fmt.Errorf("--container-kernel and --container-initramfs require --from-container")
+	}
+	if lc.compression != ironcoreimage.CompressionNone || opts.FromContainer != "" ||
+		slices.ContainsFunc(archConfigs, hasDirectories) || slices.ContainsFunc(archConfigs, hasUKIStub) {
+		if lc.dir, err = os.MkdirTemp("", "ironcore-image-build-"); err != nil {
+			return fmt.Errorf("error creating temporary directory: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(lc.dir) }()
+	}
+
+	platforms := make([]ocispec.Platform, 0, len(archConfigs))
+	for _, config := range archConfigs {
+		platforms = append(platforms, *withPlatform(ocispec.Descriptor{}, *config.Arch, "linux").Platform)
+	}
+	tags, err := common.SubManifestRefs(opts.Tag, opts.SubManifestTagTemplate, platforms)
+	if err != nil {
+		return fmt.Errorf("error determining tags of the per-platform manifests: %w", err)
+	}
+
 	s, err := storeFactory()
 	manifests := make([]ocispec.Descriptor, 0, len(archConfigs))
 	if err != nil {
 		return fmt.Errorf("could not create store: %w", err)
 	}
 
-	for _, config := range archConfigs {
-		img, err := buildImage(ctx, config.RootFS, config.SquashFS, config.InitRAMFS, config.Kernel, config.UKI, config.ISO, config.CMDLine)
+	res := Result{
+		Tag:       opts.Tag,
+		Manifests: make([]Manifest, 0, len(archConfigs)),
+	}
+	var dependencies []provenance.ResourceDescriptor
+
+	for i, config := range archConfigs {
+		if opts.FromContainer != "" {
+			unpacked, err := unpackContainer(ctx, registry, config, opts, lc.dir, squashfs.WriteOptions{ModTime: writeOpts.ModTime}, outputOptions)
+			if err != nil {
+				return fmt.Errorf("error unpacking container image for arch %s: %w", *config.Arch, err)
+			}
+			config = unpacked
+			outputOptions.Progressf("Flattened container image %s (%s) for arch %s\n", opts.FromContainer, config.container.digest, *config.Arch)
+		}
+		if hasDirectories(config) {
+			packed, err := packDirectories(config, lc.dir, writeOpts)
+			if err != nil {
+				return fmt.Errorf("error packing directories for arch %s: %w", *config.Arch, err)
+			}
+			config = packed
+			outputOptions.Progressf("Packed directories into squashfs images for arch %s\n", *config.Arch)
+		}
+		if hasUKIStub(config) {
+			assembled, err := assembleUKI(config, lc.dir)
+			if err != nil {
+				return fmt.Errorf("error assembling uki for arch %s: %w", *config.Arch, err)
+			}
+			config = assembled
+			outputOptions.Progressf("Assembled uki for arch %s\n", *config.Arch)
+		}
+
+		formats, err := detectFormats(config, opts.FormatCheck, outputOptions)
+		if err != nil {
+			return fmt.Errorf("error checking formats for arch %s: %w", *config.Arch, err)
+		}
+
+		img, err := buildImage(config, lc, formats)
 		if err != nil {
 			return fmt.Errorf("error building image for arch %s: %w", *config.Arch, err)
 		}
 
-		tag := fmt.Sprintf("%s-%s", tagName, *config.Arch)
-		if err := s.Push(ctx, tag, img); err != nil {
+		desc := withPlatform(img.Descriptor(), *config.Arch, "linux")
+		tag := tags[i]
+		if tag != "" {
+			err = s.Push(ctx, tag, img)
+		} else {
+			err = s.Put(ctx, img)
+		}
+		if err != nil {
 			return fmt.Errorf("error pushing image for arch %s: %w", *config.Arch, err)
 		}
 
-		fmt.Printf("Successfully built and pushed image for arch %s\n", *config.Arch)
+		outputOptions.Progressf("Successfully built and pushed image for arch %s\n", *config.Arch)
+		manifest := Manifest{
+			Arch:   *config.Arch,
+			Ref:    tag,
+			Digest: img.Descriptor().Digest,
+		}
+
+		if sbomFormat != "" {
+			name := tag
+			if name == "" {
+				name = fmt.Sprintf("%s (%s)", opts.Tag, *config.Arch)
+			}
+			if manifest.SBOM, err = attachSBOM(ctx, s, img.Descriptor(), sbomFormat, name, writeOpts.ModTime, config.RootFS, config.SquashFS); err != nil {
+				return fmt.Errorf("error generating sbom for arch %s: %w", *config.Arch, err)
+			}
+			outputOptions.Progressf("Attached %s sbom %s for arch %s\n", sbomFormat, manifest.SBOM, *config.Arch)
+		}
+		res.Manifests = append(res.Manifests, manifest)
+
+		if opts.Provenance {
+			deps, err := resolvedDependencies(ctx, config, img)
+			if err != nil {
+				return fmt.Errorf("error determining inputs for arch %s: %w", *config.Arch, err)
+			}
+			dependencies = append(dependencies, deps...)
+		}
 
 		// Add the descriptor with platform information to the manifests
-		manifests = append(manifests, withPlatform(img.Descriptor(), *config.Arch, "linux"))
+		manifests = append(manifests, desc)
 	}
 
 	// Build index manifest
@@ -136,7 +369,7 @@ func Run(
 		},
 		MediaType:   ocispec.MediaTypeImageIndex,
 		Manifests:   manifests,
-		Annotations: annotations,
+		Annotations: opts.Annotations,
 	}
 
 	indexImage, err := imageutil.NewIndexImage(index)
@@ -144,13 +377,23 @@ func Run(
 		return fmt.Errorf("error creating index image: %w", err)
 	}
 
-	if err := s.PushIndexManifest(ctx, indexImage, &index, tagName); err != nil {
+	if err := s.PushIndexManifest(ctx, indexImage, &index, opts.Tag); err != nil {
 		return fmt.Errorf("error pushing index manifest: %w", err)
 	}
 
-	fmt.Println("Successfully built multi-arch index:", tagName)
-	return nil
+	res.Digest = indexImage.Descriptor().Digest
 
+	if opts.Provenance {
+		if res.Provenance, err = attachProvenance(ctx, s, indexImage.Descriptor(), &res, opts, archConfigs, dependencies, startedOn); err != nil {
+			return fmt.Errorf("error attaching provenance: %w", err)
+		}
+		outputOptions.Progressf("Attached provenance %s\n", res.Provenance)
+	}
+
+	return outputOptions.Print(res, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, "Successfully built multi-arch index:", res.Tag)
+		return err
+	})
 }
 
 func withPlatform(desc ocispec.Descriptor, arch, os string) ocispec.Descriptor {
@@ -162,13 +405,68 @@ func withPlatform(desc ocispec.Descriptor, arch, os string) ocispec.Descriptor {
 	return desc
 }
 
-func buildImage(
-	_ context.Context,
-	rootFSPath, squashFSPath, initRAMFSPath, kernelPath, ukiPath, isoPath, cmdLinePath *string,
-) (image.Image, error) {
+func parseLayerCompression(opts Options) (layerCompression, error) {
+	compression, err := ironcoreimage.ParseCompression(opts.Compression)
+	if err != nil {
+		return layerCompression{}, err
+	}
+
+	if opts.Seekable && compression != ironcoreimage.CompressionZstd {
+		return layerCompression{}, fmt.Errorf("--seekable requires --compression %s", ironcoreimage.CompressionZstd)
+	}
+
+	lc := layerCompression{compression: compression, seekable: opts.Seekable}
+	for _, name := range opts.CompressLayers {
+		layerType := ironcoreimage.LayerType(name)
+		if !slices.Contains(ironcoreimage.LayerTypes, layerType) {
+			return layerCompression{}, fmt.Errorf("unknown layer type %q, must be one of %v", name, ironcoreimage.LayerTypes)
+		}
+		lc.layerTypes = append(lc.layerTypes, layerType)
+	}
+	return lc, nil
+}
+
+// layerInputs returns the layer files of the architecture configuration by layer type, in layer order.
+func layerInputs(config ArchConfig) []layerInput {
+	var inputs []layerInput
+	for _, input := range []layerInput{
+		{ironcoreimage.RootFSLayerType, ironcoreimage.RootFSLayerMediaType, config.RootFS, config.RootFSDir},
+		{ironcoreimage.InitRAMFSLayerType, ironcoreimage.InitRAMFSLayerMediaType, config.InitRAMFS, nil},
+		{ironcoreimage.KernelLayerType, ironcoreimage.KernelLayerMediaType, config.Kernel, nil},
+		{ironcoreimage.SquashFSLayerType, ironcoreimage.SquashFSLayerMediaType, config.SquashFS, config.SquashFSDir},
+		{ironcoreimage.UKILayerType, ironcoreimage.UKILayerMediaType, config.UKI, nil},
+		{ironcoreimage.ISOLayerType, ironcoreimage.ISOLayerMediaType, config.ISO, nil},
+	} {
+		if input.path != nil {
+			inputs = append(inputs, input)
+		}
+	}
+	return inputs
+}
+
+// layerInput is a file to build a layer of.
+type layerInput struct {
+	layerType ironcoreimage.LayerType
+	mediaType string
+	path      *string
+	// dir is the directory the file was packed from, if any.
+	dir *string
+}
+
+// layerCompression configures which layers to compress.
+type layerCompression struct {
+	compression ironcoreimage.Compression
+	layerTypes  []ironcoreimage.LayerType
+	// seekable selects the zstd seekable format.
+	seekable bool
+	// dir is the directory to write packed and compressed layer files to.
+	dir string
+}
+
+func buildImage(config ArchConfig, lc layerCompression, formats map[ironcoreimage.LayerType]ironcoreimage.Format) (image.Image, error) {
 	var cmdLineContent string
-	if cmdLinePath != nil {
-		content, err := os.ReadFile(*cmdLinePath)
+	if config.CMDLine != nil {
+		content, err := os.ReadFile(*config.CMDLine)
 		if err != nil {
 			return nil, fmt.Errorf("error reading cmdline file: %w", err)
 		}
@@ -179,25 +477,61 @@ func buildImage(
 		&ironcoreimage.Config{CommandLine: cmdLineContent},
 		imageutil.WithMediaType(ironcoreimage.ConfigMediaType),
 	)
+	if config.container != nil {
+		builder = builder.Annotations(map[string]string{
+			ocispec.AnnotationBaseImageName:   config.container.ref,
+			ocispec.AnnotationBaseImageDigest: config.container.digest.String(),
+		})
+	}
 
-	if rootFSPath != nil {
-		builder = builder.FileLayer(*rootFSPath, imageutil.WithMediaType(ironcoreimage.RootFSLayerMediaType))
+	for _, input := range layerInputs(config) {
+		annotations := formatAnnotations(formats[input.layerType])
+		if lc.compression == ironcoreimage.CompressionNone || !slices.Contains(lc.layerTypes, input.layerType) {
+			builder = builder.FileLayer(*input.path, imageutil.WithMediaType(input.mediaType), imageutil.WithAnnotations(annotations))
+			continue
+		}
+
+		layer, err := compressedFileLayer(*input.path, input.mediaType, lc, annotations)
+		if err != nil {
+			return nil, fmt.Errorf("error compressing %s: %w", input.layerType, err)
+		}
+		builder = builder.Layers(layer)
 	}
-	if initRAMFSPath != nil {
-		builder = builder.FileLayer(*initRAMFSPath, imageutil.WithMediaType(ironcoreimage.InitRAMFSLayerMediaType))
+
+	return builder.Complete()
+}
+
+// compressedFileLayer compresses the file at path into the directory of lc and returns a layer of the compressed file
+// with the given annotations in addition to the compression annotations.
+func compressedFileLayer(path, mediaType string, lc layerCompression, annotations map[string]string) (image.Layer, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return nil, err
 	}
-	if kernelPath != nil {
-		builder = builder.FileLayer(*kernelPath, imageutil.WithMediaType(ironcoreimage.KernelLayerMediaType))
+	defer func() { _ = src.Close() }()
+
+	dst, err := os.CreateTemp(lc.dir, "layer-*")
+	if err != nil {
+		return nil, fmt.Errorf("error creating compressed file: %w", err)
 	}
-	if squashFSPath != nil {
-		builder = builder.FileLayer(*squashFSPath, imageutil.WithMediaType(ironcoreimage.SquashFSLayerMediaType))
+
+	var compressionAnnotations map[string]string
+	if lc.seekable {
+		compressionAnnotations, err = ironcoreimage.CompressSeekable(dst, src, seekable.DefaultChunkSize)
+	} else {
+		compressionAnnotations, err = ironcoreimage.Compress(dst, src, lc.compression)
 	}
-	if ukiPath != nil {
-		builder = builder.FileLayer(*ukiPath, imageutil.WithMediaType(ironcoreimage.UKILayerMediaType))
+	if err != nil {
+		_ = dst.Close()
+		return nil, err
 	}
-	if isoPath != nil {
-		builder = builder.FileLayer(*isoPath, imageutil.WithMediaType(ironcoreimage.ISOLayerMediaType))
+	maps.Copy(compressionAnnotations, annotations)
+	if err := dst.Close(); err != nil {
+		return nil, fmt.Errorf("error closing compressed file: %w", err)
 	}
 
-	return builder.Complete()
+	return imageutil.FileLayer(dst.Name(),
+		imageutil.WithMediaType(ironcoreimage.CompressedMediaType(mediaType, lc.compression)),
+		imageutil.WithAnnotations(compressionAnnotations),
+	)
 }
@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+
+	ironcoreimage "github.com/ironcore-dev/ironcore-image"
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	"github.com/ironcore-dev/ironcore-image/container"
+	"github.com/ironcore-dev/ironcore-image/fs/squashfs"
+	"github.com/ironcore-dev/ironcore-image/oci/remote"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+var (
+	// kernelPaths are the conventional locations of the kernel in a container image, in order of preference.
+	kernelPaths = []string{
+		"boot/vmlinuz",
+		"vmlinuz",
+		"boot/vmlinuz-*",
+		"usr/lib/modules/*/vmlinuz",
+		"lib/modules/*/vmlinuz",
+	}
+	// initRAMFSPaths are the conventional locations of the initramfs in a container image, in order of preference.
+	initRAMFSPaths = []string{
+		"boot/initrd.img",
+		"initrd.img",
+		"boot/initramfs.img",
+		"boot/initrd.img-*",
+		"boot/initramfs-*.img",
+		"usr/lib/modules/*/initramfs.img",
+		"lib/modules/*/initramfs.img",
+	}
+)
+
+// containerSource is the container image the layers of an architecture configuration were taken from.
+type containerSource struct {
+	ref    string
+	digest digest.Digest
+	// layerTypes are the types of the layers taken from the container image.
+	layerTypes []ironcoreimage.LayerType
+}
+
+// containerArchConfigs validates the architecture configurations for building from a container image.
+// Without configurations, a single one for the host architecture is returned.
+func containerArchConfigs(configs archConfigs) (archConfigs, error) {
+	if len(configs) == 0 {
+		arch := runtime.GOARCH
+		return archConfigs{{Arch: &arch}}, nil
+	}
+	for _, config := range configs {
+		if config.Arch == nil {
+			return nil, fmt.Errorf("--from-container requires arch in each --config")
+		}
+		if config.RootFS != nil || config.RootFSDir != nil {
+			return nil, fmt.Errorf("rootfs and rootfs-dir can't be combined with --from-container")
+		}
+	}
+	return configs, nil
+}
+
+// unpackContainer flattens the container image of opts for the architecture of config into dir and returns
+// the configuration with the root file system of the container as rootfs. Kernel and initramfs are taken
+// from the container unless configured, at the paths of opts or at their conventional locations.
+func unpackContainer(
+	ctx context.Context,
+	registry *remote.Registry,
+	config ArchConfig,
+	opts Options,
+	dir string,
+	writeOpts squashfs.WriteOptions,
+	outputOptions *common.OutputOptions,
+) (ArchConfig, error) {
+	img, err := registry.WithPlatform(&ocispec.Platform{OS: "linux", Architecture: *config.Arch}).Resolve(ctx, opts.FromContainer)
+	if err != nil {
+		return ArchConfig{}, fmt.Errorf("error resolving container image %s: %w", opts.FromContainer, err)
+	}
+
+	// Registries return the single manifest of non-index images regardless of the requested platform.
+	platform, err := container.Platform(ctx, img)
+	if err != nil {
+		return ArchConfig{}, fmt.Errorf("error reading platform of container image %s: %w", opts.FromContainer, err)
+	}
+	if platform.OS != "linux" || platform.Architecture != *config.Arch {
+		return ArchConfig{}, fmt.Errorf("container image %s is for platform %s/%s, not linux/%s", opts.FromContainer, platform.OS, platform.Architecture, *config.Arch)
+	}
+
+	filesDir, err := os.MkdirTemp(dir, "container-*")
+	if err != nil {
+		return ArchConfig{}, fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	rootFS, err := container.Flatten(ctx, img, filesDir)
+	if err != nil {
+		return ArchConfig{}, fmt.Errorf("error flattening container image %s: %w", opts.FromContainer, err)
+	}
+
+	rootFSPath, err := writeSquashFS(dir, func(w io.WriteSeeker) error {
+		return squashfs.WriteFS(w, rootFS, writeOpts)
+	})
+	if err != nil {
+		return ArchConfig{}, fmt.Errorf("error packing container root file system: %w", err)
+	}
+	config.RootFS = &rootFSPath
+	source := &containerSource{
+		ref:        opts.FromContainer,
+		digest:     img.Descriptor().Digest,
+		layerTypes: []ironcoreimage.LayerType{ironcoreimage.RootFSLayerType},
+	}
+
+	for _, input := range []struct {
+		layerType ironcoreimage.LayerType
+		dst       **string
+		name      string
+		flag      string
+		paths     []string
+	}{
+		{ironcoreimage.KernelLayerType, &config.Kernel, opts.ContainerKernel, "container-kernel", kernelPaths},
+		{ironcoreimage.InitRAMFSLayerType, &config.InitRAMFS, opts.ContainerInitRAMFS, "container-initramfs", initRAMFSPaths},
+	} {
+		if *input.dst != nil {
+			continue
+		}
+
+		path, err := findBootFile(rootFS, input.name, input.flag, input.paths)
+		if err != nil {
+			return ArchConfig{}, fmt.Errorf("error finding %s in container image: %w", input.layerType, err)
+		}
+		if path == "" {
+			outputOptions.Progressf("No %s found in container image %s for arch %s\n", input.layerType, opts.FromContainer, *config.Arch)
+			continue
+		}
+		*input.dst = &path
+		source.layerTypes = append(source.layerTypes, input.layerType)
+	}
+
+	config.container = source
+	return config, nil
+}
+
+// findBootFile returns the local path of the file name of rootFS or, if name is empty, of the single file matching
+// the first of patterns with matches. It returns an empty path if no file matches. flag is the flag to select
+// one of multiple matches with.
+func findBootFile(rootFS *container.RootFS, name, flag string, patterns []string) (string, error) {
+	if name != "" {
+		return rootFS.Path(strings.TrimPrefix(path.Clean("/"+name), "/"))
+	}
+
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(rootFS, pattern)
+		if err != nil {
+			return "", err
+		}
+
+		var files []string
+		for _, match := range matches {
+			if info, err := fs.Stat(rootFS, match); err == nil && info.Mode().IsRegular() {
+				files = append(files, match)
+			}
+		}
+		switch len(files) {
+		case 0:
+			continue
+		case 1:
+			return rootFS.Path(files[0])
+		default:
+			return "", fmt.Errorf("found multiple candidates %v, select one with --%s", files, flag)
+		}
+	}
+	return "", nil
+}
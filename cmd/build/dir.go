@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ironcore-dev/ironcore-image/fs/squashfs"
+)
+
+// hasDirectories reports whether the architecture configuration contains directories to pack.
+func hasDirectories(config ArchConfig) bool {
+	return config.RootFSDir != nil || config.SquashFSDir != nil
+}
+
+// packDirectories packs the directories of the architecture configuration into squashfs images in dir
+// and returns the configuration with the images as rootfs / squashfs.
+func packDirectories(config ArchConfig, dir string, opts squashfs.WriteOptions) (ArchConfig, error) {
+	for _, input := range []struct {
+		name string
+		src  *string
+		dst  **string
+	}{
+		{"rootfs", config.RootFSDir, &config.RootFS},
+		{"squashfs", config.SquashFSDir, &config.SquashFS},
+	} {
+		if input.src == nil {
+			continue
+		}
+
+		path, err := packDirectory(*input.src, dir, opts)
+		if err != nil {
+			return ArchConfig{}, fmt.Errorf("error packing %s directory %s: %w", input.name, *input.src, err)
+		}
+		*input.dst = &path
+	}
+	return config, nil
+}
+
+func packDirectory(src, dir string, opts squashfs.WriteOptions) (string, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", src)
+	}
+
+	return writeSquashFS(dir, func(w io.WriteSeeker) error {
+		return squashfs.WriteDir(w, src, opts)
+	})
+}
+
+// writeSquashFS creates a squashfs image in dir using write and returns its path.
+func writeSquashFS(dir string, write func(w io.WriteSeeker) error) (string, error) {
+	f, err := os.CreateTemp(dir, "squashfs-*")
+	if err != nil {
+		return "", fmt.Errorf("error creating squashfs file: %w", err)
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("error closing squashfs file: %w", err)
+	}
+	return f.Name(), nil
+}
+
+func parseWriteOptions(opts Options) (squashfs.WriteOptions, error) {
+	var (
+		res squashfs.WriteOptions
+		err error
+	)
+	if res.UIDMap, err = parseIDMaps(opts.UIDMaps); err != nil {
+		return squashfs.WriteOptions{}, fmt.Errorf("invalid --dir-uid-map: %w", err)
+	}
+	if res.GIDMap, err = parseIDMaps(opts.GIDMaps); err != nil {
+		return squashfs.WriteOptions{}, fmt.Errorf("invalid --dir-gid-map: %w", err)
+	}
+	if opts.SourceDateEpoch != "" {
+		sec, err := strconv.ParseInt(opts.SourceDateEpoch, 10, 64)
+		if err != nil {
+			return squashfs.WriteOptions{}, fmt.Errorf("invalid --source-date-epoch %q: %w", opts.SourceDateEpoch, err)
+		}
+		modTime := time.Unix(sec, 0)
+		res.ModTime = &modTime
+	}
+	return res, nil
+}
+
+// parseIDMaps parses id maps in the format 'id:hostID:size'.
+func parseIDMaps(values []string) ([]squashfs.IDMap, error) {
+	var res []squashfs.IDMap
+	for _, value := range values {
+		parts := strings.Split(value, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("%q is not in the format 'id:hostID:size'", value)
+		}
+
+		var ids [3]uint32
+		for i, part := range parts {
+			id, err := strconv.ParseUint(part, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not in the format 'id:hostID:size': %w", value, err)
+			}
+			ids[i] = uint32(id)
+		}
+		res = append(res, squashfs.IDMap{ID: ids[0], HostID: ids[1], Size: ids[2]})
+	}
+	return res, nil
+}
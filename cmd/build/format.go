@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	ironcoreimage "github.com/ironcore-dev/ironcore-image"
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+)
+
+// Modes of --format-check.
+const (
+	formatCheckError = "error"
+	formatCheckWarn  = "warn"
+	formatCheckNone  = "none"
+)
+
+// formatChecks are all modes of --format-check.
+var formatChecks = []string{formatCheckError, formatCheckWarn, formatCheckNone}
+
+// detectFormats detects the formats of the layer files of the architecture configuration and checks them
+// against their layer types. Depending on mode, a mismatch fails the build or is reported as warning.
+// Unknown formats are only reported.
+func detectFormats(
+	config ArchConfig,
+	mode string,
+	outputOptions *common.OutputOptions,
+) (map[ironcoreimage.LayerType]ironcoreimage.Format, error) {
+	formats := make(map[ironcoreimage.LayerType]ironcoreimage.Format)
+	for _, input := range layerInputs(config) {
+		format, err := detectFormat(*input.path)
+		if err != nil {
+			return nil, fmt.Errorf("error detecting format of %s: %w", input.layerType, err)
+		}
+		formats[input.layerType] = format
+
+		if mode == formatCheckNone {
+			continue
+		}
+		if err := ironcoreimage.CheckFormat(input.layerType, format); err != nil {
+			if mode == formatCheckError && errors.Is(err, ironcoreimage.ErrFormatMismatch) {
+				return nil, err
+			}
+			outputOptions.Progressf("Warning: %v (arch %s)\n", err, *config.Arch)
+		}
+	}
+	return formats, nil
+}
+
+func detectFormat(path string) (ironcoreimage.Format, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ironcoreimage.Format{}, err
+	}
+	defer func() { _ = f.Close() }()
+	return ironcoreimage.DetectFormat(f)
+}
+
+func parseFormatCheck(mode string) error {
+	if !slices.Contains(formatChecks, mode) {
+		return fmt.Errorf("unsupported --format-check %q, must be one of %v", mode, formatChecks)
+	}
+	return nil
+}
+
+// formatAnnotations returns the layer annotations recording the detected format, if any.
+func formatAnnotations(format ironcoreimage.Format) map[string]string {
+	if format.String() == "" {
+		return nil
+	}
+	return map[string]string{ironcoreimage.FormatAnnotation: format.String()}
+}
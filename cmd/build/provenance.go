@@ -0,0 +1,221 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	ironcoreimage "github.com/ironcore-dev/ironcore-image"
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/ironcore-dev/ironcore-image/oci/store"
+	"github.com/ironcore-dev/ironcore-image/provenance"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// configParameters returns the build parameters of the architecture configuration.
+func configParameters(config ArchConfig) map[string]string {
+	params := make(map[string]string)
+	for key, value := range map[string]*string{
+		"arch":         config.Arch,
+		"rootfs":       config.RootFS,
+		"initramfs":    config.InitRAMFS,
+		"kernel":       config.Kernel,
+		"squashfs":     config.SquashFS,
+		"uki":          config.UKI,
+		"iso":          config.ISO,
+		"cmdline":      config.CMDLine,
+		"rootfs-dir":   config.RootFSDir,
+		"squashfs-dir": config.SquashFSDir,
+		"uki-stub":     config.UKIStub,
+		"os-release":   config.OSRelease,
+	} {
+		if value != nil {
+			params[key] = *value
+		}
+	}
+	return params
+}
+
+// resolvedDependencies returns the input artifacts of the image built from the architecture configuration.
+func resolvedDependencies(ctx context.Context, config ArchConfig, img image.Image) ([]provenance.ResourceDescriptor, error) {
+	layers, err := img.Layers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting layers: %w", err)
+	}
+
+	var res []provenance.ResourceDescriptor
+	if config.container != nil {
+		res = append(res, provenance.ResourceDescriptor{
+			Name:   fmt.Sprintf("container (%s)", *config.Arch),
+			URI:    config.container.ref,
+			Digest: provenance.DigestSet(config.container.digest),
+		})
+	}
+	for _, input := range layerInputs(config) {
+		if config.container != nil && slices.Contains(config.container.layerTypes, input.layerType) {
+			continue
+		}
+		// An assembled UKI is recorded by its EFI stub, its other parts are inputs themselves.
+		if input.layerType == ironcoreimage.UKILayerType && config.UKIStub != nil {
+			continue
+		}
+		for _, layer := range layers {
+			desc := layer.Descriptor()
+			if layerType, ok := ironcoreimage.LayerTypeForMediaType(desc.MediaType); ok && layerType == input.layerType {
+				path := input.path
+				if input.dir != nil {
+					path = input.dir
+				}
+				res = append(res, inputDescriptor(*config.Arch, string(input.layerType), *path, ironcoreimage.UncompressedDigest(desc)))
+				break
+			}
+		}
+	}
+
+	for _, input := range []struct {
+		name string
+		path *string
+	}{
+		{"cmdline", config.CMDLine},
+		{"uki-stub", config.UKIStub},
+		{"os-release", config.OSRelease},
+	} {
+		if input.path == nil {
+			continue
+		}
+		data, err := os.ReadFile(*input.path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s file: %w", input.name, err)
+		}
+		res = append(res, inputDescriptor(*config.Arch, input.name, *input.path, digest.FromBytes(data)))
+	}
+	return res, nil
+}
+
+func inputDescriptor(arch, name, path string, dgst digest.Digest) provenance.ResourceDescriptor {
+	uri := path
+	if abs, err := filepath.Abs(path); err == nil {
+		uri = "file://" + filepath.ToSlash(abs)
+	}
+	return provenance.ResourceDescriptor{
+		Name:   fmt.Sprintf("%s (%s)", name, arch),
+		URI:    uri,
+		Digest: provenance.DigestSet(dgst),
+	}
+}
+
+// attachProvenance stores a provenance statement of the built index and manifests as referrer of the index.
+func attachProvenance(
+	ctx context.Context,
+	s *store.Store,
+	index ocispec.Descriptor,
+	res *Result,
+	opts Options,
+	archConfigs archConfigs,
+	dependencies []provenance.ResourceDescriptor,
+	startedOn time.Time,
+) (digest.Digest, error) {
+	indexName := res.Tag
+	if indexName == "" {
+		indexName = "index"
+	}
+	subjects := []provenance.ResourceDescriptor{{
+		Name:        indexName,
+		Digest:      provenance.DigestSet(index.Digest),
+		Annotations: map[string]string{"mediaType": index.MediaType},
+	}}
+	for _, manifest := range res.Manifests {
+		name := manifest.Ref
+		if name == "" {
+			name = fmt.Sprintf("%s (%s)", indexName, manifest.Arch)
+		}
+		subjects = append(subjects, provenance.ResourceDescriptor{
+			Name:        name,
+			Digest:      provenance.DigestSet(manifest.Digest),
+			Annotations: map[string]string{"mediaType": ocispec.MediaTypeImageManifest, "arch": manifest.Arch},
+		})
+	}
+
+	configs := make([]map[string]string, 0, len(archConfigs))
+	for _, config := range archConfigs {
+		configs = append(configs, configParameters(config))
+	}
+	params := map[string]any{"configs": configs}
+	if opts.Tag != "" {
+		params["tag"] = opts.Tag
+	}
+	if opts.SubManifestTagTemplate != "" {
+		params["subManifestTag"] = opts.SubManifestTagTemplate
+	}
+	if len(opts.Annotations) > 0 {
+		params["annotations"] = opts.Annotations
+	}
+	if opts.SBOMFormat != "" {
+		params["sbom"] = opts.SBOMFormat
+	}
+	if opts.Compression != "" {
+		params["compression"] = opts.Compression
+		params["compressLayers"] = opts.CompressLayers
+		params["seekable"] = opts.Seekable
+	}
+	if len(opts.UIDMaps) > 0 {
+		params["dirUIDMaps"] = opts.UIDMaps
+	}
+	if len(opts.GIDMaps) > 0 {
+		params["dirGIDMaps"] = opts.GIDMaps
+	}
+	if opts.SourceDateEpoch != "" {
+		params["sourceDateEpoch"] = opts.SourceDateEpoch
+	}
+	if opts.FormatCheck != formatCheckError {
+		params["formatCheck"] = opts.FormatCheck
+	}
+	if opts.FromContainer != "" {
+		params["fromContainer"] = opts.FromContainer
+	}
+	if opts.ContainerKernel != "" {
+		params["containerKernel"] = opts.ContainerKernel
+	}
+	if opts.ContainerInitRAMFS != "" {
+		params["containerInitramfs"] = opts.ContainerInitRAMFS
+	}
+
+	statement := provenance.NewStatement(subjects, provenance.Provenance{
+		BuildDefinition: provenance.BuildDefinition{
+			BuildType:            provenance.BuildType,
+			ExternalParameters:   params,
+			ResolvedDependencies: dependencies,
+		},
+		RunDetails: provenance.RunDetails{
+			Builder: provenance.Builder{
+				ID:      opts.BuilderID,
+				Version: map[string]string{"ironcore-image": provenance.Version()},
+			},
+			Metadata: provenance.BuildMetadata{
+				StartedOn:  startedOn.UTC(),
+				FinishedOn: time.Now().UTC(),
+			},
+		},
+	})
+
+	artifact, err := provenance.Artifact(index, statement)
+	if err != nil {
+		return "", fmt.Errorf("error building provenance artifact: %w", err)
+	}
+
+	manifest, err := artifact.Manifest(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error reading provenance artifact manifest: %w", err)
+	}
+	if err := s.PushReferrer(image.WithManifestKeyPrefixes(ctx, manifest), "", artifact); err != nil {
+		return "", fmt.Errorf("error storing provenance artifact: %w", err)
+	}
+	return artifact.Descriptor().Digest, nil
+}
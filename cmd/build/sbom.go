@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/ironcore-dev/ironcore-image/oci/imageutil"
+	"github.com/ironcore-dev/ironcore-image/oci/store"
+	"github.com/ironcore-dev/ironcore-image/sbom"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// scanFileSystem scans the packages installed in the file system image at path.
+func scanFileSystem(path string) (*sbom.Inventory, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	fsys, err := sbom.OpenFS(f)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s: %w", path, err)
+	}
+
+	inv, err := sbom.Scan(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("error scanning %s: %w", path, err)
+	}
+	return inv, nil
+}
+
+// attachSBOM generates an SBOM of the given root file system images and stores it as referrer of subject.
+// If sourceDate is set, it is recorded as creation time instead of the current time, so the SBOM is reproducible.
+func attachSBOM(
+	ctx context.Context,
+	s *store.Store,
+	subject ocispec.Descriptor,
+	format sbom.Format,
+	name string,
+	sourceDate *time.Time,
+	paths ...*string,
+) (digest.Digest, error) {
+	inv := &sbom.Inventory{}
+	var scanned bool
+	for _, path := range paths {
+		if path == nil {
+			continue
+		}
+
+		pathInv, err := scanFileSystem(*path)
+		if err != nil {
+			return "", err
+		}
+		inv.Merge(pathInv)
+		scanned = true
+	}
+	if !scanned {
+		return "", fmt.Errorf("no rootfs or squashfs to scan")
+	}
+
+	created := time.Now().UTC()
+	if sourceDate != nil {
+		created = sourceDate.UTC()
+	}
+	var buf bytes.Buffer
+	if err := inv.Encode(&buf, format, sbom.DocumentOptions{Name: name, Created: created}); err != nil {
+		return "", fmt.Errorf("error encoding sbom: %w", err)
+	}
+
+	artifact, err := imageutil.NewArtifactBuilder(format.MediaType()).
+		Subject(subject).
+		Annotations(map[string]string{ocispec.AnnotationCreated: created.Format(time.RFC3339)}).
+		BytesLayer(buf.Bytes(),
+			imageutil.WithMediaType(format.MediaType()),
+			imageutil.WithAnnotations(map[string]string{ocispec.AnnotationTitle: fmt.Sprintf("sbom.%s.json", format)}),
+		).
+		Complete()
+	if err != nil {
+		return "", fmt.Errorf("error building sbom artifact: %w", err)
+	}
+
+	manifest, err := artifact.Manifest(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error reading sbom artifact manifest: %w", err)
+	}
+	if err := s.PushReferrer(image.WithManifestKeyPrefixes(ctx, manifest), "", artifact); err != nil {
+		return "", fmt.Errorf("error storing sbom artifact: %w", err)
+	}
+	return artifact.Descriptor().Digest, nil
+}
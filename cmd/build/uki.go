@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/ironcore-dev/ironcore-image/sbom"
+	"github.com/ironcore-dev/ironcore-image/uki"
+)
+
+// osReleasePaths are the locations of the os-release file in a root file system, in order of preference.
+var osReleasePaths = []string{"etc/os-release", "usr/lib/os-release"}
+
+// hasUKIStub reports whether a UKI is to be assembled for the architecture configuration.
+func hasUKIStub(config ArchConfig) bool {
+	return config.UKIStub != nil
+}
+
+// assembleUKI assembles a UKI from the EFI stub, kernel, initramfs, cmdline and os-release of the
+// architecture configuration into dir and returns the configuration with the UKI set.
+// Without an os-release file, the one of the rootfs / squashfs image is used, if any.
+func assembleUKI(config ArchConfig, dir string) (ArchConfig, error) {
+	if config.Kernel == nil {
+		return ArchConfig{}, fmt.Errorf("uki-stub requires a kernel")
+	}
+
+	stub, err := os.ReadFile(*config.UKIStub)
+	if err != nil {
+		return ArchConfig{}, fmt.Errorf("error reading EFI stub: %w", err)
+	}
+	if err := uki.CheckArch(stub, *config.Arch); err != nil {
+		return ArchConfig{}, err
+	}
+
+	var sections []uki.Section
+	osRelease, err := readOSRelease(config)
+	if err != nil {
+		return ArchConfig{}, err
+	}
+	if osRelease != nil {
+		sections = append(sections, uki.Section{Name: uki.SectionOSRel, Content: bytes.NewReader(osRelease), Size: int64(len(osRelease))})
+	}
+	for _, input := range []struct {
+		name string
+		path *string
+	}{
+		{uki.SectionCMDLine, config.CMDLine},
+		{uki.SectionInitRD, config.InitRAMFS},
+		{uki.SectionLinux, config.Kernel},
+	} {
+		if input.path == nil {
+			continue
+		}
+
+		f, err := os.Open(*input.path)
+		if err != nil {
+			return ArchConfig{}, err
+		}
+		defer func() { _ = f.Close() }()
+
+		info, err := f.Stat()
+		if err != nil {
+			return ArchConfig{}, err
+		}
+		sections = append(sections, uki.Section{Name: input.name, Content: f, Size: info.Size()})
+	}
+
+	out, err := os.CreateTemp(dir, "uki-*")
+	if err != nil {
+		return ArchConfig{}, fmt.Errorf("error creating UKI file: %w", err)
+	}
+	if err := uki.Assemble(out, stub, sections...); err != nil {
+		_ = out.Close()
+		return ArchConfig{}, err
+	}
+	if err := out.Close(); err != nil {
+		return ArchConfig{}, fmt.Errorf("error closing UKI file: %w", err)
+	}
+
+	path := out.Name()
+	config.UKI = &path
+	return config, nil
+}
+
+// readOSRelease returns the configured os-release file or the one of the rootfs / squashfs image.
+// It returns nil if there is none.
+func readOSRelease(config ArchConfig) ([]byte, error) {
+	if config.OSRelease != nil {
+		data, err := os.ReadFile(*config.OSRelease)
+		if err != nil {
+			return nil, fmt.Errorf("error reading os-release file: %w", err)
+		}
+		return data, nil
+	}
+
+	for _, path := range []*string{config.RootFS, config.SquashFS} {
+		if path == nil {
+			continue
+		}
+		data, err := readImageOSRelease(*path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading os-release of %s: %w", *path, err)
+		}
+		if data != nil {
+			return data, nil
+		}
+	}
+	return nil, nil
+}
+
+// readImageOSRelease reads the os-release file of the file system image at path.
+// It returns nil if the image has no os-release file or is of an unknown file system.
+func readImageOSRelease(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	fsys, err := sbom.OpenFS(f)
+	if err != nil {
+		if errors.Is(err, sbom.ErrUnknownFileSystem) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, name := range osReleasePaths {
+		data, err := fs.ReadFile(fsys, name)
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	"github.com/ironcore-dev/ironcore-image/docker"
+	"github.com/spf13/cobra"
+)
+
+func Command(requestResolverFactory common.RequestResolverFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	var pageSize int
+
+	cmd := &cobra.Command{
+		Use:   "catalog registry",
+		Short: "List the repositories of a remote registry, if the registry supports it.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			registry := args[0]
+			return Run(ctx, requestResolverFactory, outputOptions, registry, pageSize)
+		},
+	}
+
+	cmd.Flags().IntVar(&pageSize, "page-size", 0, "Number of repositories to request per page. Leave zero for the registry default.")
+	outputOptions.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Result is the result of listing the repositories of a registry.
+type Result struct {
+	// Registry is the listed registry host.
+	Registry string `json:"registry"`
+	// Repositories are the repositories of the registry.
+	Repositories []string `json:"repositories"`
+}
+
+func Run(ctx context.Context, requestResolverFactory common.RequestResolverFactory, outputOptions *common.OutputOptions, registry string, pageSize int) error {
+	resolver, err := requestResolverFactory()
+	if err != nil {
+		return fmt.Errorf("error creating request resolver: %w", err)
+	}
+
+	repositories, err := resolver.Catalog(ctx, registry, docker.ListOptions{PageSize: pageSize})
+	if err != nil {
+		return fmt.Errorf("error listing repositories of %s: %w", registry, err)
+	}
+
+	res := Result{Registry: registry, Repositories: repositories}
+	if res.Repositories == nil {
+		res.Repositories = []string{}
+	}
+	return outputOptions.Print(res, func(w io.Writer) error {
+		for _, repository := range res.Repositories {
+			if _, err := fmt.Fprintln(w, repository); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"time"
 
 	"github.com/distribution/reference"
 	"github.com/ironcore-dev/ironcore-image/docker"
@@ -19,17 +21,22 @@ import (
 const (
 	RecommendedStorePathFlagName        = "store-path"
 	RecommendedDockerConfigPathFlagName = "docker-config-path"
+	RecommendedArchFlagName             = "arch"
 )
 
 const (
 	RecommendedStorePathFlagUsage        = "Path where to store all local images and index information (such as tags)."
 	RecommendedDockerConfigPathFlagUsage = "Path to look up for docker configuration. Leave empty for default location."
+	RecommendedArchFlagUsage             = "Architecture to select if the image is an index."
 )
 
 var (
 	// DefaultStorePath is the default store path. If your user does not have a home directory,
 	// this is empty and needs to be passed in as a flag.
 	DefaultStorePath string
+
+	// DefaultArch is the default architecture to select from index images.
+	DefaultArch = runtime.GOARCH
 )
 
 func init() {
@@ -79,3 +86,45 @@ func FuzzyResolveRef(ctx context.Context, store *store.Store, ref string) (strin
 
 	return dsc.Digest.String(), nil
 }
+
+// HumanSize formats the given number of bytes with a binary unit suffix, e.g. 1.5MiB.
+func HumanSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// HumanDuration formats the given duration in a coarse, human-readable form, e.g. 3 hours.
+func HumanDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return plural(int(d/time.Second), "second")
+	case d < time.Hour:
+		return plural(int(d/time.Minute), "minute")
+	case d < 48*time.Hour:
+		return plural(int(d/time.Hour), "hour")
+	case d < 14*24*time.Hour:
+		return plural(int(d/(24*time.Hour)), "day")
+	case d < 60*24*time.Hour:
+		return plural(int(d/(7*24*time.Hour)), "week")
+	case d < 2*365*24*time.Hour:
+		return plural(int(d/(30*24*time.Hour)), "month")
+	default:
+		return plural(int(d/(365*24*time.Hour)), "year")
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
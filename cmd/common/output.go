@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+
+	"github.com/spf13/pflag"
+	"go.yaml.in/yaml/v3"
+)
+
+// OutputFormat is the format command results are printed in.
+type OutputFormat string
+
+const (
+	OutputFormatTable    OutputFormat = "table"
+	OutputFormatJSON     OutputFormat = "json"
+	OutputFormatYAML     OutputFormat = "yaml"
+	OutputFormatTemplate OutputFormat = "template"
+)
+
+const (
+	RecommendedOutputFlagName   = "output"
+	RecommendedFormatFlagName   = "format"
+	RecommendedTemplateFlagName = "template"
+)
+
+const (
+	RecommendedOutputFlagUsage   = "Output format of command results. One of table, json, yaml or template."
+	RecommendedTemplateFlagUsage = "Go template to render command results with if the output format is template."
+)
+
+// OutputOptions configure how command results are printed.
+type OutputOptions struct {
+	Format   OutputFormat
+	Template string
+
+	// Out is where results are written to. Defaults to os.Stdout.
+	Out io.Writer
+	// ErrOut is where progress is written to for structured output formats. Defaults to os.Stderr.
+	ErrOut io.Writer
+}
+
+// AddFlags adds the flags for the OutputOptions to the given flag set.
+func (o *OutputOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP((*string)(&o.Format), RecommendedOutputFlagName, "o", string(OutputFormatTable), RecommendedOutputFlagUsage)
+	fs.StringVar(&o.Template, RecommendedTemplateFlagName, "", RecommendedTemplateFlagUsage)
+}
+
+// AddFormatFlags adds the flags for the OutputOptions like AddFlags, but names the output format flag
+// format without a shorthand. It is meant for commands that use the output flag for files.
+func (o *OutputOptions) AddFormatFlags(fs *pflag.FlagSet) {
+	fs.StringVar((*string)(&o.Format), RecommendedFormatFlagName, string(OutputFormatTable), RecommendedOutputFlagUsage)
+	fs.StringVar(&o.Template, RecommendedTemplateFlagName, "", RecommendedTemplateFlagUsage)
+}
+
+func (o *OutputOptions) out() io.Writer {
+	if o.Out == nil {
+		return os.Stdout
+	}
+	return o.Out
+}
+
+func (o *OutputOptions) errOut() io.Writer {
+	if o.ErrOut == nil {
+		return os.Stderr
+	}
+	return o.ErrOut
+}
+
+func (o *OutputOptions) format() OutputFormat {
+	if o.Format == "" {
+		return OutputFormatTable
+	}
+	return o.Format
+}
+
+// IsTable reports whether results are printed in the human-readable table format.
+func (o *OutputOptions) IsTable() bool {
+	return o.format() == OutputFormatTable
+}
+
+// Progressf prints human-readable progress information.
+// For structured output formats, progress is written to ErrOut to not interfere with the result.
+func (o *OutputOptions) Progressf(format string, args ...any) {
+	w := o.out()
+	if !o.IsTable() {
+		w = o.errOut()
+	}
+	_, _ = fmt.Fprintf(w, format, args...)
+}
+
+// Print prints the result in the configured format. printTable is used for the table format.
+func (o *OutputOptions) Print(result any, printTable func(w io.Writer) error) error {
+	w := o.out()
+	switch o.format() {
+	case OutputFormatTable:
+		return printTable(w)
+	case OutputFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	case OutputFormatYAML:
+		return printYAML(w, result)
+	case OutputFormatTemplate:
+		if o.Template == "" {
+			return fmt.Errorf("--%s has to be specified for output format %s", RecommendedTemplateFlagName, OutputFormatTemplate)
+		}
+		tmpl, err := template.New("output").Parse(o.Template)
+		if err != nil {
+			return fmt.Errorf("error parsing template: %w", err)
+		}
+		if err := tmpl.Execute(w, result); err != nil {
+			return fmt.Errorf("error executing template: %w", err)
+		}
+		_, err = fmt.Fprintln(w)
+		return err
+	default:
+		return fmt.Errorf("unknown output format %q", o.Format)
+	}
+}
+
+// printYAML prints the result as YAML using the JSON field names and order.
+func printYAML(w io.Writer, result any) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("error marshaling result: %w", err)
+	}
+
+	// JSON is valid YAML, decoding it into a node retains the field order.
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("error converting result to yaml: %w", err)
+	}
+	clearStyle(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return fmt.Errorf("error encoding yaml: %w", err)
+	}
+	return enc.Close()
+}
+
+// clearStyle removes the JSON flow and quoting style from the node, so it is printed in block style.
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"context"
+	"fmt"
+
+	ocicontent "github.com/ironcore-dev/ironcore-image/oci/content"
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ReferrersStore is a local store or remote registry holding referrer artifacts.
+type ReferrersStore interface {
+	image.ReferrersSource
+	image.ReferrersSink
+}
+
+// Subject is a manifest that referrer artifacts refer to.
+type Subject struct {
+	// Store is the local store or remote registry holding the subject.
+	Store ReferrersStore
+	// Ref is the (resolved) reference of the subject.
+	Ref string
+	// Descriptor is the descriptor of the subject manifest.
+	Descriptor ocispec.Descriptor
+}
+
+// ResolveSubject resolves ref in the local store or, if remote is set, in its remote registry.
+// If arch is set and ref is an index, the manifest of that architecture is selected.
+func ResolveSubject(
+	ctx context.Context,
+	storeFactory StoreFactory,
+	registryFactory RemoteRegistryFactory,
+	ref string,
+	remote bool,
+	arch string,
+) (*Subject, error) {
+	if remote {
+		registry, err := registryFactory()
+		if err != nil {
+			return nil, fmt.Errorf("could not create remote registry: %w", err)
+		}
+
+		img, err := registry.Get(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving ref %s: %w", ref, err)
+		}
+		desc := img.Descriptor()
+
+		if arch != "" && desc.MediaType == ocispec.MediaTypeImageIndex {
+			index, err := ocicontent.GetIndexManifest(ctx, img)
+			if err != nil {
+				return nil, fmt.Errorf("error reading index manifest: %w", err)
+			}
+
+			var found bool
+			for _, manifest := range index.Manifests {
+				if manifest.Platform != nil && manifest.Platform.Architecture == arch {
+					desc, found = manifest, true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("no manifest for architecture %s found in index %s", arch, ref)
+			}
+		}
+		return &Subject{Store: registry, Ref: ref, Descriptor: desc}, nil
+	}
+
+	s, err := storeFactory()
+	if err != nil {
+		return nil, fmt.Errorf("could not create store: %w", err)
+	}
+
+	ref, err = FuzzyResolveRef(ctx, s, ref)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving source: %w", err)
+	}
+
+	img, err := s.Resolve(ctx, ref)
+	if arch != "" {
+		img, err = s.ResolveArch(ctx, ref, arch)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error resolving ref %s: %w", ref, err)
+	}
+	return &Subject{Store: s, Ref: ref, Descriptor: img.Descriptor()}, nil
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/pflag"
+)
+
+const (
+	RecommendedSubManifestTagFlagName  = "sub-manifest-tag"
+	RecommendedSubManifestTagFlagUsage = "Template for the tags of the per-platform manifests of an index. Supports the placeholders {tag}, {os}, {arch} and {variant}. Use 'none' to not tag them, in which case they are only referenced by digest."
+)
+
+const (
+	// DefaultSubManifestTagTemplate is the default template for tags of per-platform manifests.
+	DefaultSubManifestTagTemplate = "{tag}-{arch}"
+	// NoSubManifestTag disables tagging per-platform manifests.
+	NoSubManifestTag = "none"
+)
+
+// AddSubManifestTagFlag adds the flag for the per-platform manifest tag template to the flag set.
+func AddSubManifestTagFlag(fs *pflag.FlagSet, template *string) {
+	fs.StringVar(template, RecommendedSubManifestTagFlagName, DefaultSubManifestTagTemplate, RecommendedSubManifestTagFlagUsage)
+}
+
+// subManifestTagSeparators are the separators dropped next to placeholders that render empty.
+const subManifestTagSeparators = "-_."
+
+// SubManifestRef renders the tag template for the per-platform manifest of the index referenced by ref.
+// If the template is NoSubManifestTag, an empty string is returned.
+//
+// A placeholder that renders empty (e.g. {variant} for platforms without variant) drops the
+// separator preceding it, so '{tag}-{arch}-{variant}' renders 'v1-amd64' for linux/amd64.
+// Separators left at the start or end of the tag are trimmed, so '{variant}-{arch}' renders 'amd64'.
+func SubManifestRef(ref, template string, platform ocispec.Platform) (string, error) {
+	if template == NoSubManifestTag {
+		return "", nil
+	}
+
+	named, err := reference.ParseNamed(ref)
+	if err != nil {
+		return "", fmt.Errorf("ref %s is no named reference: %w", ref, err)
+	}
+	tagged, ok := named.(reference.Tagged)
+	if !ok {
+		return "", fmt.Errorf("ref %s is not tagged", ref)
+	}
+
+	placeholders := []struct{ name, value string }{
+		{"{tag}", tagged.Tag()},
+		{"{os}", platform.OS},
+		{"{arch}", platform.Architecture},
+		{"{variant}", platform.Variant},
+	}
+
+	// The replacer substitutes all placeholders in a single pass, trying replacements in the given order.
+	var oldNew []string
+	for _, p := range placeholders {
+		if p.value == "" {
+			for _, sep := range subManifestTagSeparators {
+				oldNew = append(oldNew, string(sep)+p.name, "")
+			}
+		}
+		oldNew = append(oldNew, p.name, p.value)
+	}
+	tag := strings.NewReplacer(oldNew...).Replace(template)
+	if strings.ContainsAny(tag, "{}") {
+		return "", fmt.Errorf("unknown placeholder in tag template %q", template)
+	}
+	tag = strings.Trim(tag, subManifestTagSeparators)
+
+	subRef, err := reference.WithTag(reference.TrimNamed(named), tag)
+	if err != nil {
+		return "", fmt.Errorf("tag template %q renders invalid tag %q: %w", template, tag, err)
+	}
+	return subRef.String(), nil
+}
+
+// SubManifestRefs renders the tag template for the per-platform manifests of the index referenced by ref,
+// returning the refs in the order of the platforms. It fails if two platforms render the same tag or a tag
+// clashes with ref itself, e.g. for '{tag}-{os}' and platforms that only differ in their architecture.
+func SubManifestRefs(ref, template string, platforms []ocispec.Platform) ([]string, error) {
+	res := make([]string, 0, len(platforms))
+	seen := make(map[string]ocispec.Platform, len(platforms))
+	for _, platform := range platforms {
+		subRef, err := SubManifestRef(ref, template, platform)
+		if err != nil {
+			return nil, err
+		}
+		if subRef != "" {
+			if subRef == ref {
+				return nil, fmt.Errorf("tag template %q renders the tag of the index %s for platform %s", template, ref, formatPlatform(platform))
+			}
+			if other, ok := seen[subRef]; ok {
+				return nil, fmt.Errorf("tag template %q renders %s for both platform %s and %s", template, subRef, formatPlatform(other), formatPlatform(platform))
+			}
+			seen[subRef] = platform
+		}
+		res = append(res, subRef)
+	}
+	return res, nil
+}
+
+func formatPlatform(platform ocispec.Platform) string {
+	res := platform.OS + "/" + platform.Architecture
+	if platform.Variant != "" {
+		res += "/" + platform.Variant
+	}
+	return res
+}
+
+// DigestRef returns the reference to the manifest with the given digest in the repository of ref.
+func DigestRef(ref string, dgst digest.Digest) (string, error) {
+	named, err := reference.ParseNamed(ref)
+	if err != nil {
+		return "", fmt.Errorf("ref %s is no named reference: %w", ref, err)
+	}
+
+	digested, err := reference.WithDigest(reference.TrimNamed(named), dgst)
+	if err != nil {
+		return "", fmt.Errorf("error creating digest ref: %w", err)
+	}
+	return digested.String(), nil
+}
@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package common_test
+
+import (
+	. "github.com/ironcore-dev/ironcore-image/cmd/common"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+var _ = Describe("SubManifest", func() {
+	var (
+		amd64 = ocispec.Platform{OS: "linux", Architecture: "amd64"}
+		armv7 = ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+	)
+
+	DescribeTable("SubManifestRef",
+		func(template string, platform ocispec.Platform, expected string) {
+			Expect(SubManifestRef("example.org/foo:v1", template, platform)).To(Equal(expected))
+		},
+		Entry("default template", DefaultSubManifestTagTemplate, amd64, "example.org/foo:v1-amd64"),
+		Entry("no tag", NoSubManifestTag, amd64, ""),
+		Entry("all placeholders", "{tag}_{os}.{arch}-{variant}", armv7, "example.org/foo:v1_linux.arm-v7"),
+		Entry("empty variant drops its separator", "{tag}-{arch}-{variant}", amd64, "example.org/foo:v1-amd64"),
+		Entry("empty leading variant is trimmed", "{variant}-{arch}", amd64, "example.org/foo:amd64"),
+		Entry("placeholders in any order", "{arch}-{tag}", amd64, "example.org/foo:amd64-v1"),
+	)
+
+	DescribeTable("SubManifestRef errors",
+		func(ref, template string, matcher string) {
+			_, err := SubManifestRef(ref, template, amd64)
+			Expect(err).To(MatchError(ContainSubstring(matcher)))
+		},
+		Entry("unknown placeholder", "example.org/foo:v1", "{tag}-{platform}", "unknown placeholder"),
+		Entry("invalid tag", "example.org/foo:v1", "{tag}/{arch}", "renders invalid tag"),
+		Entry("untagged ref", "example.org/foo", DefaultSubManifestTagTemplate, "is not tagged"),
+	)
+
+	DescribeTable("SubManifestRefs",
+		func(template string, platforms []ocispec.Platform, expected []string, matcher string) {
+			refs, err := SubManifestRefs("example.org/foo:v1", template, platforms)
+			if matcher != "" {
+				Expect(err).To(MatchError(ContainSubstring(matcher)))
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(refs).To(Equal(expected))
+		},
+		Entry("distinct tags", DefaultSubManifestTagTemplate, []ocispec.Platform{amd64, armv7},
+			[]string{"example.org/foo:v1-amd64", "example.org/foo:v1-arm"}, ""),
+		Entry("no tags", NoSubManifestTag, []ocispec.Platform{amd64, armv7}, []string{"", ""}, ""),
+		Entry("duplicate tags", "{tag}-{os}", []ocispec.Platform{amd64, armv7}, nil,
+			"renders example.org/foo:v1-linux for both platform linux/amd64 and linux/arm/v7"),
+		Entry("tag of the index", "{tag}", []ocispec.Platform{amd64}, nil, "renders the tag of the index"),
+	)
+})
@@ -11,18 +11,34 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func Command(storeFactory common.StoreFactory) *cobra.Command {
+func Command(storeFactory common.StoreFactory, registryFactory common.RemoteRegistryFactory, requestResolverFactory common.RequestResolverFactory) *cobra.Command {
+	var (
+		remote     bool
+		remoteOpts RemoteOptions
+	)
+
 	cmd := &cobra.Command{
 		Use:   "delete image[:tag]",
-		Short: "Delete a local image.",
+		Short: "Delete a local image or, with --remote, an image in its remote registry.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			srcImage := args[0]
+			if remote {
+				remoteOpts.In = cmd.InOrStdin()
+				remoteOpts.Out = cmd.OutOrStdout()
+				return RunRemote(ctx, registryFactory, requestResolverFactory, srcImage, remoteOpts)
+			}
 			return Run(ctx, storeFactory, srcImage)
 		},
 	}
 
+	cmd.Flags().BoolVar(&remote, "remote", false, "Delete the image in its remote registry. Registries delete by digest, so all tags of the image are removed.")
+	cmd.Flags().BoolVar(&remoteOpts.SubManifests, "sub-manifests", false, "With --remote, also delete the per-platform manifests tagged according to --sub-manifest-tag that were pushed along with an index.")
+	common.AddSubManifestTagFlag(cmd.Flags(), &remoteOpts.SubManifestTagTemplate)
+	cmd.Flags().BoolVar(&remoteOpts.DryRun, "dry-run", false, "With --remote, only print what would be deleted.")
+	cmd.Flags().BoolVarP(&remoteOpts.Yes, "yes", "y", false, "With --remote, do not ask for confirmation.")
+
 	return cmd
 }
 
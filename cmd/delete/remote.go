@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package delete
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/containerd/errdefs"
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	ocicontent "github.com/ironcore-dev/ironcore-image/oci/content"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// RemoteOptions are options for deleting a remote image.
+type RemoteOptions struct {
+	// SubManifests controls whether the per-platform manifests of an index are deleted as well.
+	SubManifests bool
+	// SubManifestTagTemplate is the template the per-platform manifests were tagged with.
+	SubManifestTagTemplate string
+	// DryRun only prints what would be deleted.
+	DryRun bool
+	// Yes skips the confirmation.
+	Yes bool
+
+	In  io.Reader
+	Out io.Writer
+}
+
+type deletion struct {
+	ref    string
+	digest digest.Digest
+}
+
+func RunRemote(
+	ctx context.Context,
+	registryFactory common.RemoteRegistryFactory,
+	requestResolverFactory common.RequestResolverFactory,
+	ref string,
+	opts RemoteOptions,
+) error {
+	registry, err := registryFactory()
+	if err != nil {
+		return fmt.Errorf("could not create remote registry: %w", err)
+	}
+
+	resolver, err := requestResolverFactory()
+	if err != nil {
+		return fmt.Errorf("error creating request resolver: %w", err)
+	}
+
+	img, err := registry.Get(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("error resolving ref %s: %w", ref, err)
+	}
+
+	deletions := []deletion{{ref: ref, digest: img.Descriptor().Digest}}
+	if opts.SubManifests && img.Descriptor().MediaType == ocispec.MediaTypeImageIndex {
+		indexManifest, err := ocicontent.GetIndexManifest(ctx, img)
+		if err != nil {
+			return fmt.Errorf("error reading index manifest: %w", err)
+		}
+
+		for _, manifest := range indexManifest.Manifests {
+			if manifest.Platform == nil {
+				continue
+			}
+
+			subRef, err := common.SubManifestRef(ref, opts.SubManifestTagTemplate, *manifest.Platform)
+			if err != nil {
+				return fmt.Errorf("error determining ref for sub-manifest %s: %w", manifest.Digest, err)
+			}
+			if subRef == "" {
+				subRef, err = common.DigestRef(ref, manifest.Digest)
+				if err != nil {
+					return fmt.Errorf("error determining ref for sub-manifest %s: %w", manifest.Digest, err)
+				}
+			}
+
+			subImg, err := registry.Get(ctx, subRef)
+			if err != nil {
+				if errdefs.IsNotFound(err) {
+					_, _ = fmt.Fprintf(opts.Out, "Skipping %s: not found\n", subRef)
+					continue
+				}
+				return fmt.Errorf("error resolving sub-manifest ref %s: %w", subRef, err)
+			}
+			if subImg.Descriptor().Digest != manifest.Digest {
+				_, _ = fmt.Fprintf(opts.Out, "Skipping %s: points to %s instead of the index manifest %s\n", subRef, subImg.Descriptor().Digest, manifest.Digest)
+				continue
+			}
+
+			deletions = append(deletions, deletion{ref: subRef, digest: manifest.Digest})
+		}
+	}
+
+	for _, d := range deletions {
+		_, _ = fmt.Fprintf(opts.Out, "Deleting %s (%s)\n", d.ref, d.digest)
+	}
+
+	if opts.DryRun {
+		_, _ = fmt.Fprintln(opts.Out, "Dry run, nothing deleted")
+		return nil
+	}
+
+	if !opts.Yes {
+		ok, err := confirm(opts.In, opts.Out)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("aborted")
+		}
+	}
+
+	for _, d := range deletions {
+		if err := resolver.DeleteManifest(ctx, d.ref, d.digest); err != nil {
+			return fmt.Errorf("error deleting %s: %w", d.ref, err)
+		}
+		_, _ = fmt.Fprintln(opts.Out, "Successfully deleted", d.ref)
+	}
+	return nil
+}
+
+func confirm(in io.Reader, out io.Writer) (bool, error) {
+	_, _ = fmt.Fprint(out, "Continue? [y/N] ")
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("error reading confirmation: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package df
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	"github.com/ironcore-dev/ironcore-image/oci/store"
+	"github.com/spf13/cobra"
+)
+
+func Command(storeFactory common.StoreFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "df",
+		Short: "Show the disk usage of the local store.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return Run(ctx, storeFactory, outputOptions)
+		},
+	}
+
+	outputOptions.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func Run(ctx context.Context, storeFactory common.StoreFactory, outputOptions *common.OutputOptions) error {
+	s, err := storeFactory()
+	if err != nil {
+		return fmt.Errorf("could not create store: %w", err)
+	}
+
+	usage, err := s.DiskUsage(ctx)
+	if err != nil {
+		return fmt.Errorf("error computing disk usage: %w", err)
+	}
+
+	if usage.Images == nil {
+		usage.Images = []store.ImageUsage{}
+	}
+	sort.Slice(usage.Images, func(i, j int) bool {
+		return usage.Images[i].Size > usage.Images[j].Size
+	})
+	for _, img := range usage.Images {
+		sort.Strings(img.Names)
+	}
+
+	return outputOptions.Print(usage, func(out io.Writer) error {
+		w := tabwriter.NewWriter(out, 12, 0, 1, ' ', 0)
+		_, _ = fmt.Fprintln(w, "IMAGE ID\tNAMES\tSIZE\tSHARED SIZE\tUNIQUE SIZE")
+		for _, img := range usage.Images {
+			names := "<none>"
+			if len(img.Names) > 0 {
+				names = strings.Join(img.Names, ",")
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				img.Digest.Encoded()[:12],
+				names,
+				common.HumanSize(img.Size),
+				common.HumanSize(img.SharedSize),
+				common.HumanSize(img.UniqueSize),
+			)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintln(out)
+		w = tabwriter.NewWriter(out, 12, 0, 1, ' ', 0)
+		_, _ = fmt.Fprintln(w, "TYPE\tCOUNT\tSIZE")
+		_, _ = fmt.Fprintf(w, "Images\t%d\t\n", len(usage.Images))
+		_, _ = fmt.Fprintf(w, "Referrers\t%d\t%s\n", usage.ReferrerCount, common.HumanSize(usage.ReferrerSize))
+		_, _ = fmt.Fprintf(w, "Blobs\t%d\t%s\n", usage.BlobCount, common.HumanSize(usage.TotalSize))
+		_, _ = fmt.Fprintf(w, "Orphaned blobs\t%d\t%s\n", usage.OrphanedBlobCount, common.HumanSize(usage.OrphanedSize))
+		_, _ = fmt.Fprintf(w, "Ingests\t%d\t%s\n", usage.IngestCount, common.HumanSize(usage.IngestSize))
+		return w.Flush()
+	})
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package extract
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	ironcoreimage "github.com/ironcore-dev/ironcore-image"
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	ocicontent "github.com/ironcore-dev/ironcore-image/oci/content"
+	"github.com/spf13/cobra"
+)
+
+func Command(storeFactory common.StoreFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	var (
+		layer  string
+		all    bool
+		output string
+		arch   string
+		verify bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "extract image[:tag]",
+		Short: "Extract layers of a local image to the file system.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			ref := args[0]
+			if verify {
+				ctx = ocicontent.WithVerifyDigests(ctx, true)
+			}
+			return Run(ctx, storeFactory, outputOptions, ref, arch, ironcoreimage.LayerType(layer), all, output)
+		},
+	}
+
+	cmd.Flags().StringVar(&layer, "layer", "", "Type of the layer to extract (kernel, initramfs, rootfs, squashfs, uki, iso).")
+	cmd.Flags().BoolVar(&all, "all", false, "Extract all layers into the output directory, naming files by layer type.")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file (or directory if --all is set).")
+	cmd.Flags().StringVar(&arch, common.RecommendedArchFlagName, common.DefaultArch, common.RecommendedArchFlagUsage)
+	cmd.Flags().BoolVar(&verify, "verify", false, "Verify the layer contents against their digests while extracting them.")
+	cmd.MarkFlagsMutuallyExclusive("layer", "all")
+	cmd.MarkFlagsOneRequired("layer", "all")
+	_ = cmd.MarkFlagRequired("output")
+	outputOptions.AddFormatFlags(cmd.Flags())
+
+	return cmd
+}
+
+// ExtractedLayer is a layer extracted to the file system.
+type ExtractedLayer struct {
+	// Type is the type of the layer.
+	Type ironcoreimage.LayerType `json:"type"`
+	// Path is the path of the file the layer was extracted to.
+	Path string `json:"path"`
+}
+
+// Result is the result of extracting layers.
+type Result struct {
+	// Ref is the resolved reference of the image.
+	Ref string `json:"ref"`
+	// Layers are the extracted layers.
+	Layers []ExtractedLayer `json:"layers"`
+}
+
+func Run(
+	ctx context.Context,
+	storeFactory common.StoreFactory,
+	outputOptions *common.OutputOptions,
+	ref, arch string,
+	layerType ironcoreimage.LayerType,
+	all bool,
+	output string,
+) error {
+	s, err := storeFactory()
+	if err != nil {
+		return fmt.Errorf("could not create store: %w", err)
+	}
+
+	ref, err = common.FuzzyResolveRef(ctx, s, ref)
+	if err != nil {
+		return fmt.Errorf("error resolving source: %w", err)
+	}
+
+	ociImg, err := s.ResolveArch(ctx, ref, arch)
+	if err != nil {
+		return fmt.Errorf("error resolving ref %s: %w", ref, err)
+	}
+
+	img, err := ironcoreimage.ResolveImage(ctx, ociImg)
+	if err != nil {
+		return fmt.Errorf("error resolving ironcore image: %w", err)
+	}
+
+	res := Result{Ref: ref, Layers: []ExtractedLayer{}}
+	if all {
+		paths, err := img.MaterializeAll(ctx, output)
+		if err != nil {
+			return fmt.Errorf("error extracting layers: %w", err)
+		}
+		for _, layerType := range ironcoreimage.LayerTypes {
+			if path, ok := paths[layerType]; ok {
+				res.Layers = append(res.Layers, ExtractedLayer{Type: layerType, Path: path})
+			}
+		}
+	} else {
+		if err := img.Materialize(ctx, layerType, output); err != nil {
+			return fmt.Errorf("error extracting %s layer: %w", layerType, err)
+		}
+		res.Layers = append(res.Layers, ExtractedLayer{Type: layerType, Path: output})
+	}
+
+	return outputOptions.Print(res, func(w io.Writer) error {
+		for _, layer := range res.Layers {
+			if _, err := fmt.Fprintf(w, "Successfully extracted %s layer to %s\n", layer.Type, layer.Path); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
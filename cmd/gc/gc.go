@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package gc
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	"github.com/ironcore-dev/ironcore-image/oci/store"
+	"github.com/opencontainers/go-digest"
+	"github.com/spf13/cobra"
+)
+
+func Command(storeFactory common.StoreFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Remove all local blobs that are neither referenced by an image nor pinned by a lease.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return Run(ctx, storeFactory, outputOptions, dryRun)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only print what would be removed.")
+	outputOptions.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Result is the result of collecting garbage.
+type Result struct {
+	// DryRun reports whether the removed blobs were only determined but not removed.
+	DryRun bool `json:"dryRun"`
+	*store.GCResult
+}
+
+func Run(ctx context.Context, storeFactory common.StoreFactory, outputOptions *common.OutputOptions, dryRun bool) error {
+	s, err := storeFactory()
+	if err != nil {
+		return fmt.Errorf("could not create store: %w", err)
+	}
+
+	gcRes, err := s.GarbageCollect(ctx, store.GCOptions{DryRun: dryRun})
+	if err != nil {
+		return fmt.Errorf("error collecting garbage: %w", err)
+	}
+
+	if gcRes.ExpiredLeases == nil {
+		gcRes.ExpiredLeases = []string{}
+	}
+	if gcRes.Removed == nil {
+		gcRes.Removed = []digest.Digest{}
+	}
+	res := Result{DryRun: dryRun, GCResult: gcRes}
+	return outputOptions.Print(res, func(w io.Writer) error {
+		for _, id := range res.ExpiredLeases {
+			_, _ = fmt.Fprintln(w, "Deleted expired lease", id)
+		}
+		verb := "Removed"
+		if dryRun {
+			verb = "Would remove"
+		}
+		for _, dgst := range res.Removed {
+			_, _ = fmt.Fprintln(w, verb, dgst)
+		}
+		_, err := fmt.Fprintf(w, "%s %d blobs, %d bytes\n", verb, len(res.Removed), res.ReclaimedBytes)
+		return err
+	})
+}
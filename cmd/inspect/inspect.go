@@ -7,7 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
+	"io"
 
 	ironcoreimage "github.com/ironcore-dev/ironcore-image"
 	"github.com/ironcore-dev/ironcore-image/cmd/common"
@@ -17,47 +17,98 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func Command(storeFactory common.StoreFactory) *cobra.Command {
+func Command(storeFactory common.StoreFactory, registryFactory common.RemoteRegistryFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	var (
+		remote           bool
+		recursive        bool
+		provenanceOutput bool
+	)
+
 	cmd := &cobra.Command{
 		Use:   "inspect image[:tag]",
-		Short: "Inspect a local image, i.e. get its manifest and some of its metadata.",
+		Short: "Inspect a local or remote image, i.e. get its manifest and some of its metadata.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			srcImage := args[0]
-			return Run(ctx, storeFactory, srcImage)
+			if provenanceOutput {
+				return RunProvenance(ctx, storeFactory, registryFactory, outputOptions, srcImage, remote)
+			}
+			if remote {
+				return RunRemote(ctx, registryFactory, outputOptions, srcImage)
+			}
+			return Run(ctx, storeFactory, outputOptions, srcImage, recursive)
 		},
 	}
 
+	cmd.Flags().BoolVar(&remote, "remote", false, "Inspect the image in its remote registry without pulling it. Only manifests and configs are fetched.")
+	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "If the image is an index, inspect all of its manifests as well.")
+	cmd.Flags().BoolVar(&provenanceOutput, "provenance", false, "Display the provenance statements attached to the image instead of its manifest.")
+	cmd.MarkFlagsMutuallyExclusive("provenance", "recursive")
+	outputOptions.AddFlags(cmd.Flags())
+
 	return cmd
 }
 
+// Output is the result of inspecting an image.
+// Either Manifest and Config or Index are set, depending on the media type of the image.
+// Manifests holds the inspected manifests of an index, if requested.
 type Output struct {
-	Descriptor ocispec.Descriptor   `json:"descriptor"`
-	Manifest   ocispec.Manifest     `json:"manifest"`
-	Config     ironcoreimage.Config `json:"config"`
+	Descriptor ocispec.Descriptor    `json:"descriptor"`
+	Manifest   *ocispec.Manifest     `json:"manifest,omitempty"`
+	Config     *ironcoreimage.Config `json:"config,omitempty"`
+	Index      *ocispec.Index        `json:"index,omitempty"`
+	Manifests  []Output              `json:"manifests,omitempty"`
 }
 
-func readImageConfig(ctx context.Context, img ociimage.Image) (*ironcoreimage.Config, error) {
-	configLayer, err := img.Config(ctx)
+func inspectManifest(ctx context.Context, img ociimage.Image) (*Output, error) {
+	manifest, err := img.Manifest(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error getting config layer: %w", err)
+		return nil, fmt.Errorf("error reading image manifest: %w", err)
 	}
 
-	rc, err := configLayer.Content(ctx)
+	ironcoreImg, err := ironcoreimage.ResolveImage(ctx, img)
 	if err != nil {
-		return nil, fmt.Errorf("error getting config content: %w", err)
+		return nil, fmt.Errorf("error resolving ironcore image: %w", err)
+	}
+
+	return &Output{
+		Descriptor: img.Descriptor(),
+		Manifest:   manifest,
+		Config:     &ironcoreImg.Config,
+	}, nil
+}
+
+// getManifestFunc returns the image of a manifest referenced by an index.
+type getManifestFunc func(ctx context.Context, desc ocispec.Descriptor) (ociimage.Image, error)
+
+func inspectIndex(ctx context.Context, img ociimage.Image, getManifest getManifestFunc) (*Output, error) {
+	indexManifest, err := ocicontent.GetIndexManifest(ctx, img)
+	if err != nil {
+		return nil, fmt.Errorf("error reading index manifest: %w", err)
+	}
+
+	output := &Output{Descriptor: img.Descriptor(), Index: indexManifest}
+	if getManifest == nil {
+		return output, nil
 	}
-	defer func() { _ = rc.Close() }()
 
-	config := &ironcoreimage.Config{}
-	if err := json.NewDecoder(rc).Decode(config); err != nil {
-		return nil, fmt.Errorf("error decoding config: %w", err)
+	for _, manifestDesc := range indexManifest.Manifests {
+		manifestImg, err := getManifest(ctx, manifestDesc)
+		if err != nil {
+			return nil, fmt.Errorf("error getting manifest %s: %w", manifestDesc.Digest, err)
+		}
+
+		manifestOutput, err := inspectManifest(ctx, manifestImg)
+		if err != nil {
+			return nil, fmt.Errorf("error inspecting manifest %s: %w", manifestDesc.Digest, err)
+		}
+		output.Manifests = append(output.Manifests, *manifestOutput)
 	}
-	return config, nil
+	return output, nil
 }
 
-func Run(ctx context.Context, storeFactory common.StoreFactory, srcImage string) error {
+func Run(ctx context.Context, storeFactory common.StoreFactory, outputOptions *common.OutputOptions, srcImage string, recursive bool) error {
 	s, err := storeFactory()
 	if err != nil {
 		return fmt.Errorf("could not create store: %w", err)
@@ -73,32 +124,80 @@ func Run(ctx context.Context, storeFactory common.StoreFactory, srcImage string)
 		return fmt.Errorf("error getting image: %w", err)
 	}
 
-	desc := img.Descriptor()
-	if desc.MediaType == ocispec.MediaTypeImageIndex {
-		indexManifest, err := ocicontent.GetIndexManifest(ctx, img)
+	if img.Descriptor().MediaType == ocispec.MediaTypeImageIndex {
+		if !recursive {
+			output, err := inspectIndex(ctx, img, nil)
+			if err != nil {
+				return err
+			}
+
+			return outputOptions.Print(output, func(w io.Writer) error {
+				return printJSON(w, output.Index)
+			})
+		}
+
+		output, err := inspectIndex(ctx, img, func(ctx context.Context, desc ocispec.Descriptor) (ociimage.Image, error) {
+			return ocicontent.Image(s.Layout().ContentStore(), desc), nil
+		})
 		if err != nil {
-			return fmt.Errorf("error reading index manifest: %w", err)
+			return err
 		}
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		return enc.Encode(indexManifest) // Directly encode the indexManifest
+
+		return outputOptions.Print(output, func(w io.Writer) error {
+			return printTree(w, ref, output)
+		})
 	}
 
-	manifest, err := img.Manifest(ctx)
+	output, err := inspectManifest(ctx, img)
 	if err != nil {
-		return fmt.Errorf("error reading image manifest: %w", err)
+		return err
 	}
 
-	config, err := readImageConfig(ctx, img)
+	return outputOptions.Print(output, func(w io.Writer) error {
+		return printJSON(w, output)
+	})
+}
+
+// RunRemote inspects an image in its remote registry. For an index, every manifest
+// of the index is inspected as well. Layer contents are never fetched.
+func RunRemote(ctx context.Context, registryFactory common.RemoteRegistryFactory, outputOptions *common.OutputOptions, ref string) error {
+	ctx = ironcoreimage.SetupContext(ctx)
+
+	registry, err := registryFactory()
 	if err != nil {
-		return fmt.Errorf("error reading image config: %w", err)
+		return fmt.Errorf("could not create remote registry: %w", err)
 	}
 
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(Output{
-		Descriptor: img.Descriptor(),
-		Manifest:   *manifest,
-		Config:     *config,
+	img, err := registry.Get(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("error getting image: %w", err)
+	}
+
+	if img.Descriptor().MediaType == ocispec.MediaTypeImageIndex {
+		output, err := inspectIndex(ctx, img, func(ctx context.Context, desc ocispec.Descriptor) (ociimage.Image, error) {
+			return registry.GetManifest(ctx, ref, desc)
+		})
+		if err != nil {
+			return err
+		}
+
+		return outputOptions.Print(output, func(w io.Writer) error {
+			return printTree(w, ref, output)
+		})
+	}
+
+	output, err := inspectManifest(ctx, img)
+	if err != nil {
+		return err
+	}
+
+	return outputOptions.Print(output, func(w io.Writer) error {
+		return printJSON(w, output)
 	})
 }
+
+func printJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package inspect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	"github.com/ironcore-dev/ironcore-image/provenance"
+	"github.com/opencontainers/go-digest"
+)
+
+// ProvenanceOutput is the result of inspecting the provenance of an image.
+type ProvenanceOutput struct {
+	// Subject is the digest of the manifest whose provenance is inspected.
+	Subject digest.Digest `json:"subject"`
+	// Statements are the provenance statements of the subject, most recent first.
+	Statements []*provenance.Statement `json:"statements"`
+}
+
+// RunProvenance displays the provenance statements attached to an image in the local store or,
+// if remote is set, in its remote registry.
+func RunProvenance(
+	ctx context.Context,
+	storeFactory common.StoreFactory,
+	registryFactory common.RemoteRegistryFactory,
+	outputOptions *common.OutputOptions,
+	ref string,
+	remote bool,
+) error {
+	subject, err := common.ResolveSubject(ctx, storeFactory, registryFactory, ref, remote, "")
+	if err != nil {
+		return err
+	}
+
+	statements, err := provenance.Find(ctx, subject.Store, subject.Ref, subject.Descriptor.Digest)
+	if err != nil {
+		return err
+	}
+	if len(statements) == 0 {
+		return fmt.Errorf("no provenance found for %s", subject.Ref)
+	}
+
+	output := ProvenanceOutput{Subject: subject.Descriptor.Digest, Statements: statements}
+	return outputOptions.Print(output, func(w io.Writer) error {
+		for i, statement := range output.Statements {
+			if i > 0 {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+			if err := printStatement(w, statement); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func printStatement(out io.Writer, statement *provenance.Statement) error {
+	var (
+		definition = statement.Predicate.BuildDefinition
+		details    = statement.Predicate.RunDetails
+	)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Builder:\t%s\n", details.Builder.ID)
+	for _, component := range sortedKeys(details.Builder.Version) {
+		_, _ = fmt.Fprintf(w, "  %s\t%s\n", component, details.Builder.Version[component])
+	}
+	_, _ = fmt.Fprintf(w, "Build type:\t%s\n", definition.BuildType)
+	_, _ = fmt.Fprintf(w, "Started:\t%s\n", details.Metadata.StartedOn.Format(time.RFC3339))
+	_, _ = fmt.Fprintf(w, "Finished:\t%s\n", details.Metadata.FinishedOn.Format(time.RFC3339))
+
+	_, _ = fmt.Fprintln(w, "Subjects:")
+	for _, subject := range statement.Subject {
+		_, _ = fmt.Fprintf(w, "  %s\t%s\n", subject.Name, digestString(subject.Digest))
+	}
+
+	_, _ = fmt.Fprintln(w, "Inputs:")
+	for _, dependency := range definition.ResolvedDependencies {
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\n", dependency.Name, dependency.URI, digestString(dependency.Digest))
+	}
+
+	_, _ = fmt.Fprintln(w, "Parameters:")
+	for _, key := range sortedKeys(definition.ExternalParameters) {
+		_, _ = fmt.Fprintf(w, "  %s\t%s\n", key, parameterString(definition.ExternalParameters[key]))
+	}
+	return w.Flush()
+}
+
+// parameterString formats strings as is and any other build parameter as JSON.
+func parameterString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func digestString(set map[string]string) string {
+	for _, algorithm := range sortedKeys(set) {
+		return algorithm + ":" + set[algorithm]
+	}
+	return "<unknown>"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package inspect
+
+import (
+	"fmt"
+	"io"
+
+	ironcoreimage "github.com/ironcore-dev/ironcore-image"
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	treeBranch = "├── "
+	treeLast   = "└── "
+	treePipe   = "│   "
+	treeSpace  = "    "
+)
+
+// printTree prints an inspected index as a tree of platform → manifest → layers.
+func printTree(w io.Writer, name string, output *Output) error {
+	if _, err := fmt.Fprintf(w, "%s (index %s)\n", name, shortDigest(output.Descriptor.Digest)); err != nil {
+		return err
+	}
+
+	for i, manifest := range output.Manifests {
+		prefix, childPrefix := treeBranch, treePipe
+		if i == len(output.Manifests)-1 {
+			prefix, childPrefix = treeLast, treeSpace
+		}
+
+		if _, err := fmt.Fprintf(w, "%s%s (manifest %s)\n", prefix, platformString(manifest.Descriptor.Platform), shortDigest(manifest.Descriptor.Digest)); err != nil {
+			return err
+		}
+		if err := printManifestTree(w, childPrefix, manifest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printManifestTree(w io.Writer, prefix string, output Output) error {
+	var lines []string
+	if output.Config != nil && output.Config.CommandLine != "" {
+		lines = append(lines, fmt.Sprintf("cmdline: %q", output.Config.CommandLine))
+	}
+	if output.Manifest != nil {
+		for _, layer := range output.Manifest.Layers {
+			name := layer.MediaType
+			if layerType, ok := ironcoreimage.LayerTypeForMediaType(layer.MediaType); ok {
+				name = string(layerType)
+				if _, compression := ironcoreimage.SplitMediaType(layer.MediaType); compression != ironcoreimage.CompressionNone {
+					name += "+" + string(compression)
+				}
+			}
+			lines = append(lines, fmt.Sprintf("%s %s (%s)", name, shortDigest(layer.Digest), common.HumanSize(layer.Size)))
+		}
+	}
+
+	for i, line := range lines {
+		branch := treeBranch
+		if i == len(lines)-1 {
+			branch = treeLast
+		}
+		if _, err := fmt.Fprintf(w, "%s%s%s\n", prefix, branch, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func platformString(platform *ocispec.Platform) string {
+	if platform == nil {
+		return "<unknown platform>"
+	}
+	res := platform.OS + "/" + platform.Architecture
+	if platform.Variant != "" {
+		res += "/" + platform.Variant
+	}
+	return res
+}
+
+func shortDigest(dgst digest.Digest) string {
+	encoded := dgst.Encoded()
+	if len(encoded) > 12 {
+		encoded = encoded[:12]
+	}
+	return dgst.Algorithm().String() + ":" + encoded
+}
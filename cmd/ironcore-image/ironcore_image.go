@@ -4,22 +4,33 @@
 package main
 
 import (
+	"github.com/ironcore-dev/ironcore-image/cmd/attach"
 	"github.com/ironcore-dev/ironcore-image/cmd/build"
+	"github.com/ironcore-dev/ironcore-image/cmd/catalog"
 	"github.com/ironcore-dev/ironcore-image/cmd/common"
 	"github.com/ironcore-dev/ironcore-image/cmd/delete"
+	"github.com/ironcore-dev/ironcore-image/cmd/df"
+	"github.com/ironcore-dev/ironcore-image/cmd/extract"
+	"github.com/ironcore-dev/ironcore-image/cmd/gc"
 	"github.com/ironcore-dev/ironcore-image/cmd/inspect"
+	"github.com/ironcore-dev/ironcore-image/cmd/lease"
 	"github.com/ironcore-dev/ironcore-image/cmd/list"
 	"github.com/ironcore-dev/ironcore-image/cmd/pull"
 	"github.com/ironcore-dev/ironcore-image/cmd/push"
+	"github.com/ironcore-dev/ironcore-image/cmd/referrers"
+	"github.com/ironcore-dev/ironcore-image/cmd/sign"
 	"github.com/ironcore-dev/ironcore-image/cmd/tag"
+	"github.com/ironcore-dev/ironcore-image/cmd/tags"
 	"github.com/ironcore-dev/ironcore-image/cmd/url"
+	"github.com/ironcore-dev/ironcore-image/cmd/verify"
 	"github.com/spf13/cobra"
 )
 
 func Command() *cobra.Command {
 	var (
-		storePath  string
-		configPath string
+		storePath     string
+		configPath    string
+		outputOptions common.OutputOptions
 	)
 
 	var (
@@ -34,14 +45,24 @@ func Command() *cobra.Command {
 	}
 
 	cmd.AddCommand(
-		build.Command(storeFactory),
-		push.Command(storeFactory, registryFactory),
-		pull.Command(storeFactory, registryFactory),
-		tag.Command(storeFactory),
-		list.Command(storeFactory),
-		inspect.Command(storeFactory),
-		delete.Command(storeFactory),
+		build.Command(storeFactory, registryFactory, &outputOptions),
+		push.Command(storeFactory, registryFactory, &outputOptions),
+		pull.Command(storeFactory, registryFactory, &outputOptions),
+		tag.Command(storeFactory, &outputOptions),
+		list.Command(storeFactory, &outputOptions),
+		inspect.Command(storeFactory, registryFactory, &outputOptions),
+		delete.Command(storeFactory, registryFactory, requestResolverFactory),
+		extract.Command(storeFactory, &outputOptions),
+		lease.Command(storeFactory, &outputOptions),
+		gc.Command(storeFactory, &outputOptions),
+		df.Command(storeFactory, &outputOptions),
 		url.Command(requestResolverFactory),
+		tags.Command(requestResolverFactory, &outputOptions),
+		catalog.Command(requestResolverFactory, &outputOptions),
+		attach.Command(storeFactory, &outputOptions),
+		referrers.Command(storeFactory, registryFactory, &outputOptions),
+		sign.Command(storeFactory, registryFactory, &outputOptions),
+		verify.Command(storeFactory, registryFactory, &outputOptions),
 	)
 
 	cmd.PersistentFlags().StringVar(&storePath, common.RecommendedStorePathFlagName, common.DefaultStorePath, common.RecommendedStorePathFlagUsage)
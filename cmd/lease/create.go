@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package lease
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	"github.com/ironcore-dev/ironcore-image/oci/lease"
+	"github.com/opencontainers/go-digest"
+	"github.com/spf13/cobra"
+)
+
+func CreateCommand(storeFactory common.StoreFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	var (
+		id         string
+		expiration time.Duration
+		labels     map[string]string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create [image[:tag]...]",
+		Short: "Create a lease pinning the given local images.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return RunCreate(ctx, storeFactory, outputOptions, id, expiration, labels, args)
+		},
+	}
+
+	cmd.Flags().StringVar(&id, "id", "", "ID of the lease. If empty, a random ID is generated.")
+	cmd.Flags().DurationVar(&expiration, "expiration", 0, "Duration after which the lease expires. Zero means the lease never expires.")
+	cmd.Flags().StringToStringVar(&labels, "label", nil, "Labels of the lease in the format 'key=value'.")
+
+	return cmd
+}
+
+func RunCreate(
+	ctx context.Context,
+	storeFactory common.StoreFactory,
+	outputOptions *common.OutputOptions,
+	id string,
+	expiration time.Duration,
+	labels map[string]string,
+	refs []string,
+) error {
+	s, err := storeFactory()
+	if err != nil {
+		return fmt.Errorf("could not create store: %w", err)
+	}
+
+	resources := make([]digest.Digest, 0, len(refs))
+	for _, ref := range refs {
+		ref, err := common.FuzzyResolveRef(ctx, s, ref)
+		if err != nil {
+			return fmt.Errorf("error resolving ref: %w", err)
+		}
+
+		img, err := s.Resolve(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("error resolving ref %s: %w", ref, err)
+		}
+		resources = append(resources, img.Descriptor().Digest)
+	}
+
+	opts := []lease.Opt{
+		lease.WithID(id),
+		lease.WithLabels(labels),
+		lease.WithResources(resources...),
+	}
+	if expiration > 0 {
+		opts = append(opts, lease.WithExpiration(expiration))
+	}
+
+	l, err := s.Leases().Create(ctx, opts...)
+	if err != nil {
+		return fmt.Errorf("error creating lease: %w", err)
+	}
+
+	return outputOptions.Print(l, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, "Successfully created lease", l.ID)
+		return err
+	})
+}
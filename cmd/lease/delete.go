@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package lease
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	"github.com/spf13/cobra"
+)
+
+func DeleteCommand(storeFactory common.StoreFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete id...",
+		Short: "Delete leases of the local store.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return RunDelete(ctx, storeFactory, outputOptions, args)
+		},
+	}
+
+	return cmd
+}
+
+// DeleteResult is the result of deleting leases.
+type DeleteResult struct {
+	// Deleted are the IDs of the deleted leases.
+	Deleted []string `json:"deleted"`
+}
+
+func RunDelete(ctx context.Context, storeFactory common.StoreFactory, outputOptions *common.OutputOptions, ids []string) error {
+	s, err := storeFactory()
+	if err != nil {
+		return fmt.Errorf("could not create store: %w", err)
+	}
+
+	res := DeleteResult{Deleted: make([]string, 0, len(ids))}
+	for _, id := range ids {
+		if err := s.Leases().Delete(ctx, id); err != nil {
+			return fmt.Errorf("error deleting lease %s: %w", id, err)
+		}
+		res.Deleted = append(res.Deleted, id)
+	}
+
+	return outputOptions.Print(res, func(w io.Writer) error {
+		for _, id := range res.Deleted {
+			if _, err := fmt.Fprintln(w, "Successfully deleted lease", id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,26 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package lease
+
+import (
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	"github.com/spf13/cobra"
+)
+
+func Command(storeFactory common.StoreFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lease",
+		Short: "Manage leases protecting local images from deletion and garbage collection.",
+	}
+
+	cmd.AddCommand(
+		CreateCommand(storeFactory, outputOptions),
+		ListCommand(storeFactory, outputOptions),
+		DeleteCommand(storeFactory, outputOptions),
+	)
+
+	outputOptions.AddFlags(cmd.PersistentFlags())
+
+	return cmd
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package lease
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	"github.com/ironcore-dev/ironcore-image/oci/lease"
+	"github.com/spf13/cobra"
+)
+
+func ListCommand(storeFactory common.StoreFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all leases of the local store.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return RunList(ctx, storeFactory, outputOptions)
+		},
+	}
+
+	return cmd
+}
+
+// ListResult is the result of listing leases.
+type ListResult struct {
+	Leases []lease.Lease `json:"leases"`
+}
+
+func RunList(ctx context.Context, storeFactory common.StoreFactory, outputOptions *common.OutputOptions) error {
+	s, err := storeFactory()
+	if err != nil {
+		return fmt.Errorf("could not create store: %w", err)
+	}
+
+	leases, err := s.Leases().List(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing leases: %w", err)
+	}
+
+	sort.Slice(leases, func(i, j int) bool {
+		return leases[i].ID < leases[j].ID
+	})
+
+	res := ListResult{Leases: leases}
+	if res.Leases == nil {
+		res.Leases = []lease.Lease{}
+	}
+	return outputOptions.Print(res, func(out io.Writer) error {
+		now := time.Now()
+		w := tabwriter.NewWriter(out, 12, 0, 1, ' ', 0)
+		_, _ = fmt.Fprintln(w, "ID\tCREATED AT\tEXPIRES AT\tRESOURCES")
+		for _, l := range res.Leases {
+			expiresAt := "<never>"
+			if l.ExpiresAt != nil {
+				expiresAt = l.ExpiresAt.Format(time.RFC3339)
+				if l.Expired(now) {
+					expiresAt += " (expired)"
+				}
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", l.ID, l.CreatedAt.Format(time.RFC3339), expiresAt, len(l.Resources))
+		}
+		return w.Flush()
+	})
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package list
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	ironcoreimage "github.com/ironcore-dev/ironcore-image"
+)
+
+// Filter reports whether an image should be listed.
+type Filter func(img Image) bool
+
+func and(filters ...Filter) Filter {
+	return func(img Image) bool {
+		for _, filter := range filters {
+			if !filter(img) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func parseFilter(value string) (Filter, error) {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok {
+		return nil, fmt.Errorf("invalid filter %q, expected 'key=value'", value)
+	}
+
+	switch key {
+	case "arch":
+		return func(img Image) bool {
+			for _, platform := range img.Platforms {
+				if platform.Architecture == val {
+					return true
+				}
+			}
+			return false
+		}, nil
+	case "os":
+		return func(img Image) bool {
+			for _, platform := range img.Platforms {
+				if platform.OS == val {
+					return true
+				}
+			}
+			return false
+		}, nil
+	case "has":
+		layerType := ironcoreimage.LayerType(val)
+		if !slices.Contains(ironcoreimage.LayerTypes, layerType) {
+			return nil, fmt.Errorf("unknown layer type %q in filter %q", val, value)
+		}
+		return func(img Image) bool {
+			return slices.Contains(img.LayerTypes, layerType)
+		}, nil
+	case "kind":
+		kind := Kind(val)
+		if kind != KindIndex && kind != KindManifest {
+			return nil, fmt.Errorf("unknown kind %q in filter %q", val, value)
+		}
+		return func(img Image) bool {
+			return img.Kind == kind
+		}, nil
+	case "dangling":
+		dangling, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q in filter %q: %w", val, value, err)
+		}
+		return func(img Image) bool {
+			return img.Dangling == dangling
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown filter key %q", key)
+	}
+}
+
+func parseFilters(values []string) (Filter, error) {
+	filters := make([]Filter, 0, len(values))
+	for _, value := range values {
+		filter, err := parseFilter(value)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return and(filters...), nil
+}
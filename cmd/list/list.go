@@ -6,42 +6,96 @@ package list
 import (
 	"context"
 	"fmt"
-	"os"
+	"io"
+	"slices"
 	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/distribution/reference"
+	ironcoreimage "github.com/ironcore-dev/ironcore-image"
 	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	ocicontent "github.com/ironcore-dev/ironcore-image/oci/content"
 	"github.com/ironcore-dev/ironcore-image/oci/descriptormatcher"
+	"github.com/ironcore-dev/ironcore-image/oci/store"
+	"github.com/ironcore-dev/ironcore-image/utils/sets"
+	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 )
 
-func Command(storeFactory common.StoreFactory) *cobra.Command {
+func Command(storeFactory common.StoreFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	var filters []string
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all images that are available locally.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
-			return Run(ctx, storeFactory)
+			return Run(ctx, storeFactory, outputOptions, filters)
 		},
 	}
 
+	cmd.Flags().StringArrayVar(&filters, "filter", nil, "Filter images in the format 'key=value'. Supported keys are arch, os, has (layer type), kind (index or manifest) and dangling (true or false). Can be specified multiple times.")
+	outputOptions.AddFlags(cmd.Flags())
+
 	return cmd
 }
 
-func Run(ctx context.Context, storeFactory common.StoreFactory) error {
+// Kind is the kind of image.
+type Kind string
+
+const (
+	KindIndex    Kind = "index"
+	KindManifest Kind = "manifest"
+)
+
+// Image is a locally available image.
+type Image struct {
+	// Repository is the repository of the image name. Empty for untagged images.
+	Repository string `json:"repository,omitempty"`
+	// Tag is the tag of the image name. Empty for untagged images.
+	Tag string `json:"tag,omitempty"`
+	// Digest is the digest of the image manifest or index.
+	Digest digest.Digest `json:"digest"`
+	// MediaType is the media type of the image manifest or index.
+	MediaType string `json:"mediaType"`
+	// Kind is the kind of image, derived from the media type.
+	Kind Kind `json:"kind"`
+	// Platforms are the platforms contained in an index or, for a manifest, the platforms
+	// it is referenced with by local indices.
+	Platforms []ocispec.Platform `json:"platforms,omitempty"`
+	// LayerTypes are the layer types present in the image (or any manifest of an index).
+	LayerTypes []ironcoreimage.LayerType `json:"layerTypes,omitempty"`
+	// Size is the total size of all blobs of the image.
+	Size int64 `json:"size"`
+	// CreatedAt is the time the image was stored locally.
+	CreatedAt time.Time `json:"createdAt"`
+	// Dangling is true if the image is neither tagged nor part of another image.
+	Dangling bool `json:"dangling"`
+}
+
+// Result is the result of listing images.
+type Result struct {
+	Images []Image `json:"images"`
+}
+
+func Run(ctx context.Context, storeFactory common.StoreFactory, outputOptions *common.OutputOptions, filters []string) error {
+	filter, err := parseFilters(filters)
+	if err != nil {
+		return err
+	}
+
 	s, err := storeFactory()
 	if err != nil {
 		return fmt.Errorf("could not create layout: %w", err)
 	}
 
-	descs, err := s.Layout().Indexer().List(ctx, descriptormatcher.MediaTypes(
-		ocispec.MediaTypeImageManifest,
-		ocispec.MediaTypeImageIndex,
+	descs, err := s.Layout().Indexer().List(ctx, descriptormatcher.And(
+		descriptormatcher.MediaTypes(ocispec.MediaTypeImageManifest, ocispec.MediaTypeImageIndex),
+		func(desc ocispec.Descriptor) bool { return !store.IsReferrer(desc) },
 	))
-
 	if err != nil {
 		return fmt.Errorf("error listing images: %w", err)
 	}
@@ -49,26 +103,206 @@ func Run(ctx context.Context, storeFactory common.StoreFactory) error {
 	// Sort for some deterministic output
 	sort.Slice(descs, func(i, j int) bool {
 		r1 := descs[i].Annotations[ocispec.AnnotationRefName]
-		r2 := descs[i].Annotations[ocispec.AnnotationRefName]
+		r2 := descs[j].Annotations[ocispec.AnnotationRefName]
 		if res := strings.Compare(r1, r2); res != 0 {
 			return res < 0
 		}
 		return descs[i].Digest > descs[j].Digest
 	})
 
-	w := tabwriter.NewWriter(os.Stdout, 12, 0, 1, ' ', 0)
-	_, _ = fmt.Fprintln(w, "REPOSITORY\tTAG\tIMAGE ID")
+	details, err := collectDetails(ctx, s, descs)
+	if err != nil {
+		return err
+	}
+
+	res := Result{Images: make([]Image, 0, len(descs))}
 	for _, item := range descs {
-		repo := "<none>"
-		tag := "<none>"
+		detail := details[item.Digest]
+		img := Image{
+			Digest:     item.Digest,
+			MediaType:  item.MediaType,
+			Kind:       detail.kind,
+			Platforms:  detail.platforms,
+			LayerTypes: detail.layerTypes,
+			Size:       detail.size,
+			CreatedAt:  detail.createdAt,
+			Dangling:   detail.dangling,
+		}
 		r := item.Annotations[ocispec.AnnotationRefName]
 		if ref, err := reference.ParseNamed(r); err == nil {
-			repo = ref.Name()
+			img.Repository = ref.Name()
 			if tagged, ok := ref.(reference.Tagged); ok {
-				tag = tagged.Tag()
+				img.Tag = tagged.Tag()
+			}
+		}
+		if filter(img) {
+			res.Images = append(res.Images, img)
+		}
+	}
+
+	return outputOptions.Print(res, func(out io.Writer) error {
+		return printTable(out, res)
+	})
+}
+
+type details struct {
+	kind       Kind
+	platforms  []ocispec.Platform
+	layerTypes []ironcoreimage.LayerType
+	size       int64
+	createdAt  time.Time
+	dangling   bool
+}
+
+func manifestLayerTypes(ctx context.Context, s *store.Store, desc ocispec.Descriptor) (sets.Set[ironcoreimage.LayerType], error) {
+	manifest, err := ocicontent.Image(s.Layout().ContentStore(), desc).Manifest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	layerTypes := sets.New[ironcoreimage.LayerType]()
+	for _, layer := range manifest.Layers {
+		if layerType, ok := ironcoreimage.LayerTypeForMediaType(layer.MediaType); ok {
+			layerTypes.Insert(layerType)
+		}
+	}
+	return layerTypes, nil
+}
+
+// collectDetails collects the details of each distinct image of the given descriptors.
+func collectDetails(ctx context.Context, s *store.Store, descs []ocispec.Descriptor) (map[digest.Digest]*details, error) {
+	var (
+		res      = make(map[digest.Digest]*details)
+		tagged   = sets.New[digest.Digest]()
+		nested   = sets.New[digest.Digest]()
+		children = make(map[digest.Digest][]ocispec.Platform)
+		dgsts    []digest.Digest
+	)
+	for _, desc := range descs {
+		if _, ok := desc.Annotations[ocispec.AnnotationRefName]; ok {
+			tagged.Insert(desc.Digest)
+		}
+		if _, ok := res[desc.Digest]; ok {
+			continue
+		}
+		dgsts = append(dgsts, desc.Digest)
+
+		d := &details{kind: KindManifest}
+		res[desc.Digest] = d
+
+		if info, err := s.Layout().ContentStore().Info(ctx, desc.Digest); err == nil {
+			d.createdAt = info.CreatedAt
+		}
+
+		if desc.MediaType != ocispec.MediaTypeImageIndex {
+			layerTypes, err := manifestLayerTypes(ctx, s, desc)
+			if err != nil {
+				return nil, fmt.Errorf("error reading manifest %s: %w", desc.Digest, err)
+			}
+			d.layerTypes = sortedLayerTypes(layerTypes)
+			continue
+		}
+
+		d.kind = KindIndex
+		index, err := ocicontent.GetIndexManifest(ctx, ocicontent.IndexImage(s.Layout().ContentStore(), desc))
+		if err != nil {
+			return nil, fmt.Errorf("error reading index %s: %w", desc.Digest, err)
+		}
+
+		layerTypes := sets.New[ironcoreimage.LayerType]()
+		for _, manifest := range index.Manifests {
+			nested.Insert(manifest.Digest)
+			if manifest.Platform != nil {
+				d.platforms = append(d.platforms, *manifest.Platform)
+				if !slices.ContainsFunc(children[manifest.Digest], func(platform ocispec.Platform) bool {
+					return formatPlatform(platform) == formatPlatform(*manifest.Platform)
+				}) {
+					children[manifest.Digest] = append(children[manifest.Digest], *manifest.Platform)
+				}
 			}
+
+			manifestTypes, err := manifestLayerTypes(ctx, s, manifest)
+			if err != nil {
+				// Manifests of an index may not be present locally.
+				continue
+			}
+			layerTypes.Insert(sortedLayerTypes(manifestTypes)...)
+		}
+		d.layerTypes = sortedLayerTypes(layerTypes)
+	}
+	if len(dgsts) == 0 {
+		return res, nil
+	}
+
+	sizes, err := s.ImageSizes(ctx, dgsts...)
+	if err != nil {
+		return nil, fmt.Errorf("error computing image sizes: %w", err)
+	}
+
+	for dgst, d := range res {
+		d.size = sizes[dgst]
+		d.dangling = !tagged.Has(dgst) && !nested.Has(dgst)
+		if d.kind == KindManifest {
+			d.platforms = children[dgst]
 		}
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", repo, tag, item.Digest.Encoded()[:12])
+	}
+	return res, nil
+}
+
+func sortedLayerTypes(layerTypes sets.Set[ironcoreimage.LayerType]) []ironcoreimage.LayerType {
+	var res []ironcoreimage.LayerType
+	for _, layerType := range ironcoreimage.LayerTypes {
+		if layerTypes.Has(layerType) {
+			res = append(res, layerType)
+		}
+	}
+	return res
+}
+
+func printTable(out io.Writer, res Result) error {
+	now := time.Now()
+	w := tabwriter.NewWriter(out, 12, 0, 1, ' ', 0)
+	_, _ = fmt.Fprintln(w, "REPOSITORY\tTAG\tIMAGE ID\tKIND\tPLATFORMS\tLAYERS\tSIZE\tCREATED")
+	for _, img := range res.Images {
+		platforms := make([]string, 0, len(img.Platforms))
+		for _, platform := range img.Platforms {
+			platforms = append(platforms, formatPlatform(platform))
+		}
+		layerTypes := make([]string, 0, len(img.LayerTypes))
+		for _, layerType := range img.LayerTypes {
+			layerTypes = append(layerTypes, string(layerType))
+		}
+
+		created := "<unknown>"
+		if !img.CreatedAt.IsZero() {
+			created = common.HumanDuration(now.Sub(img.CreatedAt)) + " ago"
+		}
+
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			orNone(img.Repository),
+			orNone(img.Tag),
+			img.Digest.Encoded()[:12],
+			img.Kind,
+			orNone(strings.Join(platforms, ",")),
+			orNone(strings.Join(layerTypes, ",")),
+			common.HumanSize(img.Size),
+			created,
+		)
 	}
 	return w.Flush()
 }
+
+func formatPlatform(platform ocispec.Platform) string {
+	res := platform.OS + "/" + platform.Architecture
+	if platform.Variant != "" {
+		res += "/" + platform.Variant
+	}
+	return res
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
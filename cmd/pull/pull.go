@@ -6,13 +6,23 @@ package pull
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/ironcore-dev/ironcore-image/cmd/common"
 	"github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/ironcore-dev/ironcore-image/oci/signature"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 )
 
-func Command(storeFactory common.StoreFactory, registryFactory common.RemoteRegistryFactory) *cobra.Command {
+func Command(storeFactory common.StoreFactory, registryFactory common.RemoteRegistryFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	var (
+		pullReferrers bool
+		verifyKeys    []string
+		policyPath    string
+	)
+
 	cmd := &cobra.Command{
 		Use:   "pull image[:tag]",
 		Short: "Pull an image from a remote registry determined by the image name.",
@@ -20,18 +30,40 @@ func Command(storeFactory common.StoreFactory, registryFactory common.RemoteRegi
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			ref := args[0]
-			return Run(ctx, storeFactory, registryFactory, ref)
+			return Run(ctx, storeFactory, registryFactory, outputOptions, ref, pullReferrers, verifyKeys, policyPath)
 		},
 	}
 
+	cmd.Flags().BoolVar(&pullReferrers, "referrers", true, "Pull the artifacts referring to the image (e.g. SBOMs or signatures) along with it.")
+	cmd.Flags().StringArrayVar(&verifyKeys, "verify-key", nil, "Path to a PEM encoded public key. If set, the image is only pulled if it is signed by one of the given keys. Can be specified multiple times.")
+	cmd.Flags().StringVar(&policyPath, "signature-policy", "", "Path to a signature verification policy file. The image is only pulled if the policy accepts it.")
+	cmd.MarkFlagsMutuallyExclusive("verify-key", "signature-policy")
+	outputOptions.AddFlags(cmd.Flags())
+
 	return cmd
 }
 
+// Result is the result of pulling an image.
+type Result struct {
+	// Ref is the pulled reference.
+	Ref string `json:"ref"`
+	// Digest is the digest of the pulled image manifest.
+	Digest digest.Digest `json:"digest"`
+	// MediaType is the media type of the pulled image manifest.
+	MediaType string `json:"mediaType"`
+	// Referrers are the artifacts referring to the image that were pulled along with it.
+	Referrers []ocispec.Descriptor `json:"referrers,omitempty"`
+}
+
 func Run(
 	ctx context.Context,
 	storeFactory common.StoreFactory,
 	registryFactory common.RemoteRegistryFactory,
+	outputOptions *common.OutputOptions,
 	ref string,
+	pullReferrers bool,
+	verifyKeys []string,
+	policyPath string,
 ) error {
 	s, err := storeFactory()
 	if err != nil {
@@ -43,11 +75,42 @@ func Run(
 		return fmt.Errorf("could not create remote registry: %w", err)
 	}
 
-	img, err := image.Copy(ctx, s, registry, ref)
+	if len(verifyKeys) > 0 {
+		verifier, err := signature.LoadVerifier(verifyKeys...)
+		if err != nil {
+			return err
+		}
+		registry = registry.WithVerifier(verifier)
+	}
+	if policyPath != "" {
+		policy, err := signature.LoadPolicy(policyPath)
+		if err != nil {
+			return err
+		}
+		registry = registry.WithVerifier(policy)
+	}
+
+	var (
+		img       image.Image
+		referrers []ocispec.Descriptor
+	)
+	if pullReferrers {
+		img, referrers, err = image.CopyWithReferrers(ctx, s, registry, ref)
+	} else {
+		img, err = image.Copy(ctx, s, registry, ref)
+	}
 	if err != nil {
 		return fmt.Errorf("error pulling ref %s: %w", ref, err)
 	}
 
-	fmt.Println("Successfully pulled", ref, img.Descriptor().Digest.Encoded())
-	return nil
+	res := Result{
+		Ref:       ref,
+		Digest:    img.Descriptor().Digest,
+		MediaType: img.Descriptor().MediaType,
+		Referrers: referrers,
+	}
+	return outputOptions.Print(res, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, "Successfully pulled", res.Ref, res.Digest.Encoded())
+		return err
+	})
 }
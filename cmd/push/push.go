@@ -6,15 +6,23 @@ package push
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/ironcore-dev/ironcore-image/oci/content"
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 
 	"github.com/ironcore-dev/ironcore-image/cmd/common"
 	"github.com/spf13/cobra"
 )
 
-func Command(storeFactory common.StoreFactory, registryFactory common.RemoteRegistryFactory) *cobra.Command {
-	var pushSubManifests bool
+func Command(storeFactory common.StoreFactory, registryFactory common.RemoteRegistryFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	var (
+		pushSubManifests  bool
+		subManifestTagTpl string
+		pushReferrers     bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "push image[:tag]",
@@ -23,20 +31,50 @@ func Command(storeFactory common.StoreFactory, registryFactory common.RemoteRegi
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			name := args[0]
-			return Run(ctx, storeFactory, registryFactory, name, pushSubManifests)
+			return Run(ctx, storeFactory, registryFactory, outputOptions, name, pushSubManifests, subManifestTagTpl, pushReferrers)
 		},
 	}
 
 	cmd.Flags().BoolVar(&pushSubManifests, "push-sub-manifests", true, "Push sub-manifests along with the index manifest.")
+	common.AddSubManifestTagFlag(cmd.Flags(), &subManifestTagTpl)
+	cmd.Flags().BoolVar(&pushReferrers, "referrers", true, "Push the artifacts referring to the image (e.g. SBOMs or signatures) along with it.")
+	outputOptions.AddFlags(cmd.Flags())
 	return cmd
 }
 
+// SubManifest is a pushed sub-manifest of an index.
+type SubManifest struct {
+	// Ref is the reference the sub-manifest was pushed to.
+	Ref string `json:"ref"`
+	// Digest is the digest of the sub-manifest.
+	Digest digest.Digest `json:"digest"`
+	// Platform is the platform of the sub-manifest.
+	Platform *ocispec.Platform `json:"platform,omitempty"`
+}
+
+// Result is the result of pushing an image.
+type Result struct {
+	// Ref is the pushed reference.
+	Ref string `json:"ref"`
+	// Digest is the digest of the pushed image manifest or index.
+	Digest digest.Digest `json:"digest"`
+	// MediaType is the media type of the pushed image manifest or index.
+	MediaType string `json:"mediaType"`
+	// SubManifests are the sub-manifests pushed along with an index.
+	SubManifests []SubManifest `json:"subManifests,omitempty"`
+	// Referrers are the artifacts referring to the image that were pushed along with it.
+	Referrers []ocispec.Descriptor `json:"referrers,omitempty"`
+}
+
 func Run(
 	ctx context.Context,
 	storeFactory common.StoreFactory,
 	registryFactory common.RemoteRegistryFactory,
+	outputOptions *common.OutputOptions,
 	ref string,
 	pushSubManifests bool,
+	subManifestTagTpl string,
+	pushReferrers bool,
 ) error {
 	store, err := storeFactory()
 	if err != nil {
@@ -53,18 +91,38 @@ func Run(
 		return fmt.Errorf("error resolving ref %s: %w", ref, err)
 	}
 
+	res := Result{
+		Ref:       ref,
+		Digest:    img.Descriptor().Digest,
+		MediaType: img.Descriptor().MediaType,
+	}
+
 	// Check if the image is an index manifest
 	if indexManifest, err := content.GetIndexManifest(ctx, img); err == nil && pushSubManifests {
-		fmt.Println("Detected index manifest. Pushing sub-manifests...")
+		outputOptions.Progressf("Detected index manifest. Pushing sub-manifests...\n")
 
+		platforms := make([]ocispec.Platform, 0, len(indexManifest.Manifests))
 		for _, manifest := range indexManifest.Manifests {
-			platform := manifest.Platform
-			if platform == nil {
+			if manifest.Platform == nil {
 				return fmt.Errorf("platform information is missing for sub-manifest %s, cannot proceed", manifest.Digest)
 			}
-			archSuffix := "-" + platform.Architecture
+			platforms = append(platforms, *manifest.Platform)
+		}
+		subRefs, err := common.SubManifestRefs(ref, subManifestTagTpl, platforms)
+		if err != nil {
+			return fmt.Errorf("error determining refs of the sub-manifests: %w", err)
+		}
 
-			subRef := fmt.Sprintf("%s%s", ref, archSuffix)
+		for i, manifest := range indexManifest.Manifests {
+			platform := manifest.Platform
+			subRef := subRefs[i]
+			if subRef == "" {
+				// Push by digest only.
+				subRef, err = common.DigestRef(ref, manifest.Digest)
+				if err != nil {
+					return fmt.Errorf("error determining ref for sub-manifest %s: %w", manifest.Digest, err)
+				}
+			}
 
 			subImg, err := store.Resolve(ctx, manifest.Digest.String())
 			if err != nil {
@@ -73,20 +131,46 @@ func Run(
 			if err := registry.Push(ctx, subRef, subImg); err != nil {
 				return fmt.Errorf("error pushing sub-manifest %s: %w", manifest.Digest, err)
 			}
-			fmt.Printf("Successfully pushed sub-manifest: %s\n", manifest.Digest)
+			outputOptions.Progressf("Successfully pushed sub-manifest: %s\n", manifest.Digest)
+
+			res.SubManifests = append(res.SubManifests, SubManifest{
+				Ref:      subRef,
+				Digest:   manifest.Digest,
+				Platform: platform,
+			})
 		}
 
 		if err := registry.Push(ctx, ref, img); err != nil {
 			return fmt.Errorf("error pushing index manifest %s: %w", ref, err)
 		}
-		fmt.Println("Successfully pushed index manifest:", ref)
-		return nil
+
+		if pushReferrers {
+			subjects := []digest.Digest{res.Digest}
+			for _, subManifest := range res.SubManifests {
+				subjects = append(subjects, subManifest.Digest)
+			}
+			if res.Referrers, err = image.CopyReferrers(ctx, registry, store, ref, subjects...); err != nil {
+				return fmt.Errorf("error pushing referrers: %w", err)
+			}
+		}
+		return outputOptions.Print(res, func(w io.Writer) error {
+			_, err := fmt.Fprintln(w, "Successfully pushed index manifest:", res.Ref)
+			return err
+		})
 	}
 
 	if err := registry.Push(ctx, ref, img); err != nil {
 		return fmt.Errorf("error pushing image to %s: %w", ref, err)
 	}
 
-	fmt.Println("Successfully pushed", ref, img.Descriptor().Digest.Encoded())
-	return nil
+	if pushReferrers {
+		if res.Referrers, err = image.CopyReferrers(ctx, registry, store, ref, res.Digest); err != nil {
+			return fmt.Errorf("error pushing referrers: %w", err)
+		}
+	}
+
+	return outputOptions.Print(res, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, "Successfully pushed", res.Ref, res.Digest.Encoded())
+		return err
+	})
 }
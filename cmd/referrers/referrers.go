@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package referrers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+)
+
+// Options are options for listing referrers.
+type Options struct {
+	// Remote lists the referrers in the remote registry instead of the local store.
+	Remote bool
+	// ArtifactType only lists referrers of the given artifact type.
+	ArtifactType string
+	// Arch selects the manifest of an index to list the referrers of.
+	Arch string
+}
+
+func Command(storeFactory common.StoreFactory, registryFactory common.RemoteRegistryFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	var opts Options
+
+	cmd := &cobra.Command{
+		Use:   "referrers image[:tag]",
+		Short: "List the artifacts referring to an image, e.g. SBOMs or signatures.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			ref := args[0]
+			return Run(ctx, storeFactory, registryFactory, outputOptions, ref, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Remote, "remote", false, "List the referrers in the remote registry instead of the local store.")
+	cmd.Flags().StringVar(&opts.ArtifactType, "artifact-type", "", "Only list referrers of the given artifact type.")
+	cmd.Flags().StringVar(&opts.Arch, common.RecommendedArchFlagName, "", "Architecture of the manifest to list the referrers of if the image is an index. Leave empty to use the index itself.")
+	outputOptions.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Result is the result of listing referrers.
+type Result struct {
+	// Subject is the digest of the manifest whose referrers are listed.
+	Subject digest.Digest `json:"subject"`
+	// Referrers are the descriptors of the referrers.
+	Referrers []ocispec.Descriptor `json:"referrers"`
+}
+
+func Run(
+	ctx context.Context,
+	storeFactory common.StoreFactory,
+	registryFactory common.RemoteRegistryFactory,
+	outputOptions *common.OutputOptions,
+	ref string,
+	opts Options,
+) error {
+	subject, err := common.ResolveSubject(ctx, storeFactory, registryFactory, ref, opts.Remote, opts.Arch)
+	if err != nil {
+		return err
+	}
+
+	descs, err := subject.Store.Referrers(ctx, subject.Ref, subject.Descriptor.Digest, opts.ArtifactType)
+	if err != nil {
+		return fmt.Errorf("error listing referrers of %s: %w", subject.Descriptor.Digest, err)
+	}
+
+	res := Result{Subject: subject.Descriptor.Digest, Referrers: descs}
+	if res.Referrers == nil {
+		res.Referrers = []ocispec.Descriptor{}
+	}
+	return outputOptions.Print(res, func(out io.Writer) error {
+		return printTable(out, res)
+	})
+}
+
+func printTable(out io.Writer, res Result) error {
+	w := tabwriter.NewWriter(out, 12, 0, 1, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DIGEST\tARTIFACT TYPE\tSIZE\tCREATED")
+	for _, desc := range res.Referrers {
+		created := desc.Annotations[ocispec.AnnotationCreated]
+		if created == "" {
+			created = "<unknown>"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", desc.Digest, desc.ArtifactType, common.HumanSize(desc.Size), created)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sign
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/ironcore-dev/ironcore-image/oci/signature"
+	"github.com/opencontainers/go-digest"
+	"github.com/spf13/cobra"
+)
+
+// Options are options for signing an image.
+type Options struct {
+	// Key is the path of the PEM encoded private key to sign with.
+	Key string
+	// Remote signs the image in its remote registry instead of the local store.
+	Remote bool
+	// Annotations are added to the signed payload.
+	Annotations map[string]string
+	// Arch selects the manifest of an index to sign. If empty, the ref itself is signed.
+	Arch string
+}
+
+func Command(storeFactory common.StoreFactory, registryFactory common.RemoteRegistryFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	var opts Options
+
+	cmd := &cobra.Command{
+		Use:   "sign image[:tag]",
+		Short: "Sign an image with a private key, storing the signature as an artifact referring to the image.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			ref := args[0]
+			return Run(ctx, storeFactory, registryFactory, outputOptions, ref, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Key, "key", "", "Path to the PEM encoded private key (ECDSA, Ed25519 or RSA) to sign with.")
+	cmd.Flags().BoolVar(&opts.Remote, "remote", false, "Sign the image in its remote registry and push the signature there instead of to the local store.")
+	cmd.Flags().StringToStringVar(&opts.Annotations, "annotation", nil, "Annotations to add to the signed payload in the format 'key=value'.")
+	cmd.Flags().StringVar(&opts.Arch, common.RecommendedArchFlagName, "", "Architecture of the manifest to sign if the image is an index. Leave empty to sign the index itself.")
+	_ = cmd.MarkFlagRequired("key")
+	outputOptions.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Result is the result of signing an image.
+type Result struct {
+	// Subject is the digest of the signed manifest.
+	Subject digest.Digest `json:"subject"`
+	// Digest is the digest of the signature artifact.
+	Digest digest.Digest `json:"digest"`
+	// KeyID is the ID of the signing key.
+	KeyID string `json:"keyID"`
+}
+
+func Run(
+	ctx context.Context,
+	storeFactory common.StoreFactory,
+	registryFactory common.RemoteRegistryFactory,
+	outputOptions *common.OutputOptions,
+	ref string,
+	opts Options,
+) error {
+	key, err := signature.LoadPrivateKey(opts.Key)
+	if err != nil {
+		return fmt.Errorf("error loading key: %w", err)
+	}
+
+	subject, err := common.ResolveSubject(ctx, storeFactory, registryFactory, ref, opts.Remote, opts.Arch)
+	if err != nil {
+		return err
+	}
+
+	sig, err := signature.Sign(subject.Descriptor, subject.Ref, key, opts.Annotations)
+	if err != nil {
+		return fmt.Errorf("error signing %s: %w", subject.Descriptor.Digest, err)
+	}
+
+	manifest, err := sig.Manifest(ctx)
+	if err != nil {
+		return fmt.Errorf("error reading signature manifest: %w", err)
+	}
+
+	if err := subject.Store.PushReferrer(image.WithManifestKeyPrefixes(ctx, manifest), subject.Ref, sig); err != nil {
+		return fmt.Errorf("error storing signature: %w", err)
+	}
+
+	keyID, err := signature.KeyID(key.Public())
+	if err != nil {
+		return err
+	}
+
+	res := Result{
+		Subject: subject.Descriptor.Digest,
+		Digest:  sig.Descriptor().Digest,
+		KeyID:   keyID,
+	}
+	return outputOptions.Print(res, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Successfully signed %s: %s\n", res.Subject, res.Digest)
+		return err
+	})
+}
@@ -6,12 +6,13 @@ package tag
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/ironcore-dev/ironcore-image/cmd/common"
 	"github.com/spf13/cobra"
 )
 
-func Command(storeFactory common.StoreFactory) *cobra.Command {
+func Command(storeFactory common.StoreFactory, outputOptions *common.OutputOptions) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "tag source-image[:tag] target-image[:tag]",
 		Short: "Tag a local image with a given name (and optional tag).",
@@ -20,14 +21,24 @@ func Command(storeFactory common.StoreFactory) *cobra.Command {
 			ctx := cmd.Context()
 			srcImage := args[0]
 			tgtImage := args[1]
-			return Run(ctx, storeFactory, srcImage, tgtImage)
+			return Run(ctx, storeFactory, outputOptions, srcImage, tgtImage)
 		},
 	}
 
+	outputOptions.AddFlags(cmd.Flags())
+
 	return cmd
 }
 
-func Run(ctx context.Context, storeFactory common.StoreFactory, srcImage, tgtImage string) error {
+// Result is the result of tagging an image.
+type Result struct {
+	// Source is the resolved source reference.
+	Source string `json:"source"`
+	// Target is the created tag.
+	Target string `json:"target"`
+}
+
+func Run(ctx context.Context, storeFactory common.StoreFactory, outputOptions *common.OutputOptions, srcImage, tgtImage string) error {
 	s, err := storeFactory()
 	if err != nil {
 		return fmt.Errorf("could not create store: %w", err)
@@ -42,6 +53,9 @@ func Run(ctx context.Context, storeFactory common.StoreFactory, srcImage, tgtIma
 		return fmt.Errorf("error tagging image: %w", err)
 	}
 
-	fmt.Println("Successfully tagged", tgtImage, "with", desc)
-	return nil
+	res := Result{Source: desc, Target: tgtImage}
+	return outputOptions.Print(res, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, "Successfully tagged", res.Target, "with", res.Source)
+		return err
+	})
 }
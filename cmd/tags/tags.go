@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package tags
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	"github.com/ironcore-dev/ironcore-image/docker"
+	"github.com/spf13/cobra"
+)
+
+// SortOrder is the order tags are listed in.
+type SortOrder string
+
+const (
+	// SortNone keeps the order the registry returned the tags in.
+	SortNone SortOrder = "none"
+	// SortSemver sorts tags by semantic version, highest first. Tags that are no semantic
+	// version are listed afterward in lexical order.
+	SortSemver SortOrder = "semver"
+)
+
+// Options are options for listing tags.
+type Options struct {
+	// Sort is the order to list the tags in.
+	Sort SortOrder
+	// Constraint is a semantic version constraint (e.g. '>= 1.2, < 2') tags have to satisfy.
+	// If set, tags that are no semantic version are omitted.
+	Constraint string
+	// PageSize is the number of tags to request per page.
+	PageSize int
+}
+
+func Command(requestResolverFactory common.RequestResolverFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	var opts Options
+
+	cmd := &cobra.Command{
+		Use:   "tags repository",
+		Short: "List the tags of a remote repository.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			repository := args[0]
+			return Run(ctx, requestResolverFactory, outputOptions, repository, opts)
+		},
+	}
+
+	cmd.Flags().StringVar((*string)(&opts.Sort), "sort", string(SortNone), "Order to list the tags in. One of none|semver.")
+	cmd.Flags().StringVar(&opts.Constraint, "semver", "", "Only list tags that are semantic versions satisfying the given constraint, e.g. '>= 1.2, < 2'.")
+	cmd.Flags().IntVar(&opts.PageSize, "page-size", 0, "Number of tags to request per page. Leave zero for the registry default.")
+	outputOptions.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Result is the result of listing tags.
+type Result struct {
+	// Repository is the listed repository.
+	Repository string `json:"repository"`
+	// Tags are the tags of the repository.
+	Tags []string `json:"tags"`
+}
+
+func Run(ctx context.Context, requestResolverFactory common.RequestResolverFactory, outputOptions *common.OutputOptions, repository string, opts Options) error {
+	if opts.Sort != SortNone && opts.Sort != SortSemver {
+		return fmt.Errorf("unknown sort order %q", opts.Sort)
+	}
+
+	var constraint *semver.Constraints
+	if opts.Constraint != "" {
+		c, err := semver.NewConstraint(opts.Constraint)
+		if err != nil {
+			return fmt.Errorf("invalid semver constraint %q: %w", opts.Constraint, err)
+		}
+		constraint = c
+	}
+
+	resolver, err := requestResolverFactory()
+	if err != nil {
+		return fmt.Errorf("error creating request resolver: %w", err)
+	}
+
+	tags, err := resolver.Tags(ctx, repository, docker.ListOptions{PageSize: opts.PageSize})
+	if err != nil {
+		return fmt.Errorf("error listing tags of %s: %w", repository, err)
+	}
+
+	if constraint != nil {
+		tags = filterTags(tags, constraint)
+	}
+	if opts.Sort == SortSemver {
+		sortTags(tags)
+	}
+
+	res := Result{Repository: repository, Tags: tags}
+	if res.Tags == nil {
+		res.Tags = []string{}
+	}
+	return outputOptions.Print(res, func(w io.Writer) error {
+		for _, tag := range res.Tags {
+			if _, err := fmt.Fprintln(w, tag); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func filterTags(tags []string, constraint *semver.Constraints) []string {
+	var res []string
+	for _, tag := range tags {
+		version, err := semver.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+		if constraint.Check(version) {
+			res = append(res, tag)
+		}
+	}
+	return res
+}
+
+func sortTags(tags []string) {
+	versions := make(map[string]*semver.Version, len(tags))
+	for _, tag := range tags {
+		if version, err := semver.NewVersion(tag); err == nil {
+			versions[tag] = version
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		vi, vj := versions[tags[i]], versions[tags[j]]
+		switch {
+		case vi != nil && vj != nil:
+			if c := vi.Compare(vj); c != 0 {
+				return c > 0
+			}
+			return tags[i] < tags[j]
+		case vi != nil:
+			return true
+		case vj != nil:
+			return false
+		default:
+			return tags[i] < tags[j]
+		}
+	})
+}
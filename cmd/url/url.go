@@ -16,15 +16,15 @@ import (
 	"github.com/spf13/cobra"
 )
 
-type LayerType string
+type LayerType = ironcoreimage.LayerType
 
 const (
-	Kernel    LayerType = "kernel"
-	RootFS    LayerType = "rootfs"
-	InitRAMFS LayerType = "initramfs"
-	SquashFS  LayerType = "squashfs"
-	UKI       LayerType = "uki"
-	ISO       LayerType = "iso"
+	Kernel    = ironcoreimage.KernelLayerType
+	RootFS    = ironcoreimage.RootFSLayerType
+	InitRAMFS = ironcoreimage.InitRAMFSLayerType
+	SquashFS  = ironcoreimage.SquashFSLayerType
+	UKI       = ironcoreimage.UKILayerType
+	ISO       = ironcoreimage.ISOLayerType
 )
 
 func Command(requestResolverFactory common.RequestResolverFactory) *cobra.Command {
@@ -84,7 +84,8 @@ func Run(ctx context.Context, requestResolverFactory common.RequestResolverFacto
 			desc            *ocispec.Descriptor
 		)
 		for _, layer := range manifest.Layers {
-			if layer.MediaType == mediaType {
+			// Compressed layers are served as stored, their media type has a compression suffix.
+			if baseMediaType, _ := ironcoreimage.SplitMediaType(layer.MediaType); baseMediaType == mediaType {
 				layer := layer
 				desc = &layer
 				break
@@ -104,6 +105,11 @@ func Run(ctx context.Context, requestResolverFactory common.RequestResolverFacto
 			}
 		}
 
+		if _, compression := ironcoreimage.SplitMediaType(desc.MediaType); compression != ironcoreimage.CompressionNone {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: layer %s is %s compressed (%s), its content has to be decompressed after retrieval\n",
+				desc.Digest, compression, desc.MediaType)
+		}
+
 		layerInfo, err := info.Layer(ctx, *desc)
 		if err != nil {
 			return fmt.Errorf("could not lookup layer %s: %w", desc.Digest, err)
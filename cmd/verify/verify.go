@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package verify
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ironcore-dev/ironcore-image/cmd/common"
+	"github.com/ironcore-dev/ironcore-image/oci/signature"
+	"github.com/opencontainers/go-digest"
+	"github.com/spf13/cobra"
+)
+
+// Options are options for verifying an image.
+type Options struct {
+	// Keys are the paths of the PEM encoded public keys to accept signatures of.
+	Keys []string
+	// Remote verifies the image in its remote registry instead of the local store.
+	Remote bool
+	// Arch selects the manifest of an index to verify. If empty, the ref itself is verified.
+	Arch string
+}
+
+func Command(storeFactory common.StoreFactory, registryFactory common.RemoteRegistryFactory, outputOptions *common.OutputOptions) *cobra.Command {
+	var opts Options
+
+	cmd := &cobra.Command{
+		Use:   "verify image[:tag]",
+		Short: "Verify that an image is signed by one of the given public keys.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			ref := args[0]
+			return Run(ctx, storeFactory, registryFactory, outputOptions, ref, opts)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&opts.Keys, "key", nil, "Path to a PEM encoded public key to accept signatures of. Can be specified multiple times.")
+	cmd.Flags().BoolVar(&opts.Remote, "remote", false, "Verify the image in its remote registry instead of the local store.")
+	cmd.Flags().StringVar(&opts.Arch, common.RecommendedArchFlagName, "", "Architecture of the manifest to verify if the image is an index. Leave empty to verify the index itself.")
+	_ = cmd.MarkFlagRequired("key")
+	outputOptions.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Result is the result of verifying an image.
+type Result struct {
+	// Subject is the digest of the verified manifest.
+	Subject digest.Digest `json:"subject"`
+	// Signature is the valid signature found.
+	Signature signature.Signature `json:"signature"`
+}
+
+func Run(
+	ctx context.Context,
+	storeFactory common.StoreFactory,
+	registryFactory common.RemoteRegistryFactory,
+	outputOptions *common.OutputOptions,
+	ref string,
+	opts Options,
+) error {
+	verifier, err := signature.LoadVerifier(opts.Keys...)
+	if err != nil {
+		return err
+	}
+
+	subject, err := common.ResolveSubject(ctx, storeFactory, registryFactory, ref, opts.Remote, opts.Arch)
+	if err != nil {
+		return err
+	}
+
+	sig, err := verifier.VerifySignature(ctx, subject.Store, subject.Ref, subject.Descriptor.Digest)
+	if err != nil {
+		return fmt.Errorf("error verifying %s: %w", subject.Ref, err)
+	}
+
+	res := Result{Subject: subject.Descriptor.Digest, Signature: *sig}
+	return outputOptions.Print(res, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Verified %s: signed by key %s (signature %s)\n", res.Subject, res.Signature.KeyID, res.Signature.Descriptor.Digest)
+		return err
+	})
+}
@@ -0,0 +1,280 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ironcoreimage
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	ocicontent "github.com/ironcore-dev/ironcore-image/oci/content"
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/ironcore-dev/ironcore-image/seekable"
+	"github.com/klauspost/compress/zstd"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// UncompressedDigestAnnotation is the layer annotation holding the digest of the uncompressed layer content.
+	UncompressedDigestAnnotation = "dev.ironcore.image.uncompressed-digest"
+	// UncompressedSizeAnnotation is the layer annotation holding the size of the uncompressed layer content.
+	UncompressedSizeAnnotation = "dev.ironcore.image.uncompressed-size"
+	// SeekableAnnotation marks zstd compressed layers in the zstd seekable format, see package seekable.
+	SeekableAnnotation = "dev.ironcore.image.seekable"
+	// SeekableTableDigestAnnotation is the layer annotation holding the digest of the seek table and frame
+	// digests of a seekable layer, which random access to the uncompressed content is verified against.
+	SeekableTableDigestAnnotation = "dev.ironcore.image.seekable-table-digest"
+)
+
+// ErrNotSeekable is returned for layers whose uncompressed content cannot be accessed randomly.
+var ErrNotSeekable = errors.New("layer is not seekable")
+
+// Compression is the compression of a layer, indicated by a media type suffix.
+type Compression string
+
+const (
+	CompressionNone Compression = ""
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+// Compressions are all supported layer compressions.
+var Compressions = []Compression{CompressionGzip, CompressionZstd}
+
+// ParseCompression parses a compression name. "none" and the empty string denote uncompressed layers.
+func ParseCompression(s string) (Compression, error) {
+	switch Compression(s) {
+	case CompressionNone, "none":
+		return CompressionNone, nil
+	case CompressionGzip, CompressionZstd:
+		return Compression(s), nil
+	default:
+		return "", fmt.Errorf("unsupported compression %q, must be one of %v", s, Compressions)
+	}
+}
+
+// CompressedMediaType returns the media type of mediaType compressed with c.
+func CompressedMediaType(mediaType string, c Compression) string {
+	if c == CompressionNone {
+		return mediaType
+	}
+	return mediaType + "+" + string(c)
+}
+
+// SplitMediaType splits a layer media type into its uncompressed media type and its compression.
+func SplitMediaType(mediaType string) (string, Compression) {
+	for _, c := range Compressions {
+		if base, ok := strings.CutSuffix(mediaType, "+"+string(c)); ok {
+			return base, c
+		}
+	}
+	return mediaType, CompressionNone
+}
+
+// UncompressedDigest returns the digest of the uncompressed content of the layer described by desc.
+// For uncompressed layers, this is the layer digest. It returns an empty digest if a compressed
+// layer does not record its uncompressed digest.
+func UncompressedDigest(desc ocispec.Descriptor) digest.Digest {
+	if _, c := SplitMediaType(desc.MediaType); c == CompressionNone {
+		return desc.Digest
+	}
+	return digest.Digest(desc.Annotations[UncompressedDigestAnnotation])
+}
+
+// Compress writes the content of r compressed with c to w. The output is deterministic.
+// It returns the descriptor annotations recording the digest and size of the uncompressed content.
+func Compress(w io.Writer, r io.Reader, c Compression) (map[string]string, error) {
+	var (
+		cw  io.WriteCloser
+		err error
+	)
+	switch c {
+	case CompressionGzip:
+		cw = gzip.NewWriter(w)
+	case CompressionZstd:
+		// A single encoder goroutine keeps the output independent of the number of CPUs.
+		cw, err = zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, fmt.Errorf("error creating zstd encoder: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", c)
+	}
+
+	annotations, err := copyUncompressed(cw, r)
+	if err != nil {
+		_ = cw.Close()
+		return nil, fmt.Errorf("error compressing: %w", err)
+	}
+	if err := cw.Close(); err != nil {
+		return nil, fmt.Errorf("error compressing: %w", err)
+	}
+	return annotations, nil
+}
+
+// CompressSeekable writes the content of r zstd compressed in the seekable format to w, using frames of
+// chunkSize uncompressed bytes. The output is deterministic. The returned descriptor annotations record
+// the digest and size of the uncompressed content and mark the layer as seekable with its table digest.
+func CompressSeekable(w io.Writer, r io.Reader, chunkSize int) (map[string]string, error) {
+	sw, err := seekable.NewWriter(w, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations, err := copyUncompressed(sw, r)
+	if err != nil {
+		return nil, fmt.Errorf("error compressing: %w", err)
+	}
+	if err := sw.Close(); err != nil {
+		return nil, fmt.Errorf("error compressing: %w", err)
+	}
+	annotations[SeekableAnnotation] = string(CompressionZstd)
+	annotations[SeekableTableDigestAnnotation] = sw.TableDigest().String()
+	return annotations, nil
+}
+
+// copyUncompressed copies r to w and returns the annotations recording the digest and size of the content.
+func copyUncompressed(w io.Writer, r io.Reader) (map[string]string, error) {
+	digester := digest.Canonical.Digester()
+	n, err := io.Copy(io.MultiWriter(w, digester.Hash()), r)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		UncompressedDigestAnnotation: digester.Digest().String(),
+		UncompressedSizeAnnotation:   strconv.FormatInt(n, 10),
+	}, nil
+}
+
+// ReaderAt is random access to content of known size.
+type ReaderAt interface {
+	io.ReaderAt
+	Size() int64
+}
+
+// UncompressedReaderAt returns random access to the uncompressed content of the layer described by desc,
+// given random access to its stored content, e.g. a remote.BlobReader.
+// Uncompressed layers are returned as is, layers in the zstd seekable format are decompressed on demand
+// and every decompressed frame is verified against the table digest of the layer.
+// It returns ErrNotSeekable for other compressed layers and seekable layers without table digest.
+func UncompressedReaderAt(r ReaderAt, desc ocispec.Descriptor) (ReaderAt, error) {
+	switch _, c := SplitMediaType(desc.MediaType); {
+	case c == CompressionNone:
+		return r, nil
+	case c == CompressionZstd && desc.Annotations[SeekableAnnotation] == string(CompressionZstd):
+		tableDigest, err := digest.Parse(desc.Annotations[SeekableTableDigestAnnotation])
+		if err != nil {
+			return nil, fmt.Errorf("%w: layer %s has no valid table digest: %w", ErrNotSeekable, desc.Digest, err)
+		}
+		sr, err := seekable.NewVerifiedReader(r, r.Size(), tableDigest)
+		if err != nil {
+			return nil, fmt.Errorf("error reading seek table of layer %s: %w", desc.Digest, err)
+		}
+		return sr, nil
+	default:
+		return nil, fmt.Errorf("%w: %s compressed layer %s", ErrNotSeekable, c, desc.Digest)
+	}
+}
+
+// Decompress returns a reader of the content of r decompressed with c.
+func Decompress(r io.Reader, c Compression) (io.ReadCloser, error) {
+	switch c {
+	case CompressionNone:
+		return io.NopCloser(r), nil
+	case CompressionGzip:
+		return gzip.NewReader(r)
+	case CompressionZstd:
+		dec, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", c)
+	}
+}
+
+// decompressedLayer is a compressed layer whose Content is transparently decompressed.
+// It keeps the descriptor of the stored, compressed layer.
+type decompressedLayer struct {
+	image.Layer
+	compression Compression
+}
+
+func (l *decompressedLayer) Content(ctx context.Context) (io.ReadCloser, error) {
+	rc, err := l.Layer.Content(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dr, err := Decompress(rc, l.compression)
+	if err != nil {
+		_ = rc.Close()
+		return nil, fmt.Errorf("error decompressing layer %s: %w", l.Descriptor().Digest, err)
+	}
+
+	res := &decompressedReader{ReadCloser: dr, compressed: rc}
+	dgst, err := digest.Parse(l.Descriptor().Annotations[UncompressedDigestAnnotation])
+	if err != nil || !dgst.Algorithm().Available() {
+		return res, nil
+	}
+
+	size := int64(-1)
+	if s, err := strconv.ParseInt(l.Descriptor().Annotations[UncompressedSizeAnnotation], 10, 64); err == nil {
+		size = s
+	}
+	vr, err := ocicontent.NewVerifyingReader(res, dgst, size)
+	if err != nil {
+		_ = res.Close()
+		return nil, err
+	}
+	return vr, nil
+}
+
+// ReaderAt returns random access to the uncompressed layer content if the layer is in the zstd seekable
+// format and the compressed layer supports random access. Otherwise, it returns ErrNotSeekable or
+// image.ErrRangeNotSupported.
+func (l *decompressedLayer) ReaderAt(ctx context.Context) (image.ReaderAt, error) {
+	// Frames are verified against the table digest instead of the digest of the compressed layer.
+	compressed, err := image.LayerReaderAt(ocicontent.WithVerifyDigests(ctx, false), l.Layer)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := UncompressedReaderAt(compressed, l.Descriptor())
+	if err != nil {
+		_ = compressed.Close()
+		return nil, err
+	}
+	return &uncompressedReaderAt{ReaderAt: r, compressed: compressed}, nil
+}
+
+// uncompressedReaderAt is random access to uncompressed content that closes the compressed content on Close.
+type uncompressedReaderAt struct {
+	ReaderAt
+	compressed io.Closer
+}
+
+func (r *uncompressedReaderAt) Close() error {
+	if c, ok := r.ReaderAt.(io.Closer); ok {
+		_ = c.Close()
+	}
+	return r.compressed.Close()
+}
+
+// decompressedReader is decompressed content that closes the compressed content on Close.
+type decompressedReader struct {
+	io.ReadCloser
+	compressed io.Closer
+}
+
+func (r *decompressedReader) Close() error {
+	_ = r.ReadCloser.Close()
+	return r.compressed.Close()
+}
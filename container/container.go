@@ -0,0 +1,431 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package container flattens the tar layers of OCI and Docker container images into a single
+// root file system, applying whiteouts the way container runtimes do.
+package container
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	ironcoreimage "github.com/ironcore-dev/ironcore-image"
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// Docker media types of container image layers.
+	MediaTypeDockerLayer        = "application/vnd.docker.image.rootfs.diff.tar.gzip"
+	MediaTypeDockerForeignLayer = "application/vnd.docker.image.rootfs.foreign.diff.tar.gzip"
+
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = whiteoutPrefix + whiteoutPrefix + ".opq"
+	xattrPAXPrefix = "SCHILY.xattr."
+)
+
+// ErrUnsupportedLayer is returned for layers that are not tar layers of a container image.
+var ErrUnsupportedLayer = errors.New("unsupported container image layer")
+
+// LayerCompression returns the compression of a container image layer of the given media type.
+func LayerCompression(mediaType string) (ironcoreimage.Compression, error) {
+	switch mediaType {
+	case ocispec.MediaTypeImageLayer, ocispec.MediaTypeImageLayerNonDistributable: //nolint:staticcheck
+		return ironcoreimage.CompressionNone, nil
+	case ocispec.MediaTypeImageLayerGzip, ocispec.MediaTypeImageLayerNonDistributableGzip, //nolint:staticcheck
+		MediaTypeDockerLayer, MediaTypeDockerForeignLayer:
+		return ironcoreimage.CompressionGzip, nil
+	case ocispec.MediaTypeImageLayerZstd, ocispec.MediaTypeImageLayerNonDistributableZstd: //nolint:staticcheck
+		return ironcoreimage.CompressionZstd, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedLayer, mediaType)
+	}
+}
+
+// Platform returns the platform of the container image img as recorded in its config.
+func Platform(ctx context.Context, img image.Image) (ocispec.Platform, error) {
+	config, err := img.Config(ctx)
+	if err != nil {
+		return ocispec.Platform{}, fmt.Errorf("error getting config: %w", err)
+	}
+
+	rc, err := config.Content(ctx)
+	if err != nil {
+		return ocispec.Platform{}, fmt.Errorf("error getting config content: %w", err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	var imgConfig ocispec.Image
+	if err := json.NewDecoder(rc).Decode(&imgConfig); err != nil {
+		return ocispec.Platform{}, fmt.Errorf("error decoding config: %w", err)
+	}
+	return imgConfig.Platform, nil
+}
+
+// Flatten applies the layers of the container image img in order to an empty root file system.
+// The contents of regular files are stored in dir, which has to exist and must be kept until
+// the returned RootFS is no longer used.
+func Flatten(ctx context.Context, img image.Image, dir string) (*RootFS, error) {
+	layers, err := img.Layers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting layers: %w", err)
+	}
+
+	r := &RootFS{dir: dir}
+	r.root = &node{children: make(map[string]*node), inode: r.newInode(fs.ModeDir | 0o755)}
+	for i, layer := range layers {
+		if err := r.applyLayer(ctx, layer, i+1); err != nil {
+			return nil, fmt.Errorf("error applying layer %d (%s): %w", i, layer.Descriptor().Digest, err)
+		}
+	}
+	return r, nil
+}
+
+// node is a directory entry of the root file system. Hard links are nodes sharing an inode.
+type node struct {
+	name     string
+	parent   *node
+	children map[string]*node
+	inode    *inode
+	// layer is the number of the layer that last wrote the entry.
+	layer int
+}
+
+type inode struct {
+	ino    uint64
+	nlink  uint32
+	mode   fs.FileMode
+	uid    uint32
+	gid    uint32
+	mtime  time.Time
+	xattrs map[string][]byte
+	major  uint32
+	minor  uint32
+	target string
+	// data is the path of the file holding the content of a regular file.
+	data string
+	size int64
+}
+
+func (r *RootFS) newInode(mode fs.FileMode) *inode {
+	r.inodes++
+	return &inode{ino: r.inodes, nlink: 1, mode: mode}
+}
+
+// release drops a link to the inode, removing the content of regular files without links.
+func (r *RootFS) release(ino *inode) error {
+	ino.nlink--
+	if ino.nlink > 0 || ino.data == "" {
+		return nil
+	}
+	return os.Remove(ino.data)
+}
+
+func (r *RootFS) applyLayer(ctx context.Context, layer image.Layer, number int) error {
+	compression, err := LayerCompression(layer.Descriptor().MediaType)
+	if err != nil {
+		return err
+	}
+
+	rc, err := layer.Content(ctx)
+	if err != nil {
+		return fmt.Errorf("error reading layer: %w", err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	dr, err := ironcoreimage.Decompress(rc, compression)
+	if err != nil {
+		return fmt.Errorf("error decompressing layer: %w", err)
+	}
+	defer func() { _ = dr.Close() }()
+
+	var whiteouts, opaques []string
+	tr := tar.NewReader(dr)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("error reading tar entry: %w", err)
+		}
+
+		name := cleanPath(hdr.Name)
+		dir, base := path.Split(name)
+		switch {
+		case base == whiteoutOpaque:
+			opaques = append(opaques, path.Clean(dir))
+			continue
+		case strings.HasPrefix(base, whiteoutPrefix):
+			whiteouts = append(whiteouts, path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix)))
+			continue
+		}
+
+		if err := r.applyEntry(hdr, name, tr, number); err != nil {
+			return fmt.Errorf("error applying %s: %w", hdr.Name, err)
+		}
+	}
+
+	// Whiteouts only hide entries of lower layers, regardless of their position in the tar stream.
+	for _, name := range opaques {
+		n, err := r.lookup(name)
+		if err != nil {
+			return err
+		}
+		if n != nil && n.inode.mode.IsDir() {
+			if err := r.prune(n, number); err != nil {
+				return err
+			}
+		}
+	}
+	for _, name := range whiteouts {
+		n, err := r.lookup(name)
+		if err != nil {
+			return err
+		}
+		switch {
+		case n == nil || n == r.root:
+		case written(n, number):
+			if err := r.prune(n, number); err != nil {
+				return err
+			}
+		default:
+			if err := r.remove(n); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cleanPath returns the slash separated path of a tar entry relative to the root, "." for the root itself.
+func cleanPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+func (r *RootFS) applyEntry(hdr *tar.Header, name string, content io.Reader, number int) error {
+	if name == "" {
+		if hdr.Typeflag == tar.TypeDir {
+			r.setMetadata(r.root.inode, hdr)
+		}
+		return nil
+	}
+
+	dir, base := path.Split(name)
+	parent, err := r.mkdirAll(path.Clean(dir), number)
+	if err != nil {
+		return err
+	}
+
+	existing := parent.children[base]
+	if hdr.Typeflag == tar.TypeDir && existing != nil && existing.inode.mode.IsDir() {
+		r.setMetadata(existing.inode, hdr)
+		existing.layer = number
+		return nil
+	}
+
+	var ino *inode
+	switch hdr.Typeflag {
+	case tar.TypeLink:
+		target, err := r.lookup(cleanPath(hdr.Linkname))
+		if err != nil {
+			return err
+		}
+		if target == nil || target.inode.mode.IsDir() {
+			return fmt.Errorf("invalid hard link target %s", hdr.Linkname)
+		}
+		if target == existing {
+			return nil
+		}
+		ino = target.inode
+		ino.nlink++
+	case tar.TypeReg, tar.TypeRegA: //nolint:staticcheck
+		ino = r.newInode(0)
+		if err := r.writeData(ino, content); err != nil {
+			return err
+		}
+	case tar.TypeDir, tar.TypeSymlink, tar.TypeChar, tar.TypeBlock, tar.TypeFifo:
+		ino = r.newInode(0)
+		ino.target = hdr.Linkname
+		ino.major = uint32(hdr.Devmajor)
+		ino.minor = uint32(hdr.Devminor)
+	default:
+		return nil
+	}
+	if hdr.Typeflag != tar.TypeLink {
+		r.setMetadata(ino, hdr)
+	}
+
+	if existing != nil {
+		if err := r.remove(existing); err != nil {
+			return err
+		}
+	}
+	n := &node{name: base, parent: parent, inode: ino, layer: number}
+	if ino.mode.IsDir() {
+		n.children = make(map[string]*node)
+	}
+	parent.children[base] = n
+	return nil
+}
+
+func (r *RootFS) setMetadata(ino *inode, hdr *tar.Header) {
+	ino.mode = hdr.FileInfo().Mode()
+	ino.uid = uint32(hdr.Uid)
+	ino.gid = uint32(hdr.Gid)
+	ino.mtime = hdr.ModTime
+	ino.xattrs = nil
+	for key, value := range hdr.PAXRecords {
+		if name, ok := strings.CutPrefix(key, xattrPAXPrefix); ok {
+			if ino.xattrs == nil {
+				ino.xattrs = make(map[string][]byte)
+			}
+			ino.xattrs[name] = []byte(value)
+		}
+	}
+}
+
+func (r *RootFS) writeData(ino *inode, content io.Reader) error {
+	f, err := os.Create(filepath.Join(r.dir, strconv.FormatUint(ino.ino, 10)))
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	ino.data = f.Name()
+	if ino.size, err = io.Copy(f, content); err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+	return f.Close()
+}
+
+// mkdirAll returns the directory name, creating it and its parents if they do not exist.
+// Symbolic links in the path are followed within the root, so an upper layer writing below a
+// symbolic link of a lower layer writes into its target. Other entries that are not directories
+// are replaced, as an upper layer directory hides them.
+func (r *RootFS) mkdirAll(name string, number int) (*node, error) {
+	return r.walk(name, func(n *node, elem string) (*node, error) {
+		if child := n.children[elem]; child != nil {
+			if err := r.remove(child); err != nil {
+				return nil, err
+			}
+		}
+		child := &node{
+			name:     elem,
+			parent:   n,
+			children: make(map[string]*node),
+			inode:    r.newInode(fs.ModeDir | 0o755),
+			layer:    number,
+		}
+		n.children[elem] = child
+		return child, nil
+	})
+}
+
+// lookup returns the entry name, following symbolic links within the root in all but the final path
+// element. It returns nil if the entry does not exist.
+func (r *RootFS) lookup(name string) (*node, error) {
+	if name == "." {
+		return r.root, nil
+	}
+	dir, base := path.Split(name)
+	n, err := r.walk(path.Clean(dir), func(*node, string) (*node, error) { return nil, nil })
+	if n == nil || err != nil {
+		return nil, err
+	}
+	return n.children[base], nil
+}
+
+// walk returns the directory name, following symbolic links in all path elements. Like in a chroot,
+// absolute link targets and ".." are resolved within the root. For path elements that are missing or
+// no directories, missing is called with their parent directory; walk returns nil if missing does.
+func (r *RootFS) walk(name string, missing func(n *node, elem string) (*node, error)) (*node, error) {
+	n := r.root
+	elems := strings.Split(name, "/")
+	for links := 0; len(elems) > 0; {
+		elem := elems[0]
+		elems = elems[1:]
+
+		switch elem {
+		case "", ".":
+			continue
+		case "..":
+			if n.parent != nil {
+				n = n.parent
+			}
+			continue
+		}
+
+		child := n.children[elem]
+		switch {
+		case child != nil && child.inode.mode.Type() == fs.ModeSymlink:
+			if links++; links > maxSymlinks {
+				return nil, fmt.Errorf("too many levels of symbolic links in %s", name)
+			}
+			if strings.HasPrefix(child.inode.target, "/") {
+				n = r.root
+			}
+			elems = append(strings.Split(child.inode.target, "/"), elems...)
+			continue
+		case child == nil || !child.inode.mode.IsDir():
+			var err error
+			if child, err = missing(n, elem); child == nil || err != nil {
+				return nil, err
+			}
+		}
+		n = child
+	}
+	return n, nil
+}
+
+// remove removes n and everything below it.
+func (r *RootFS) remove(n *node) error {
+	for _, child := range n.children {
+		if err := r.remove(child); err != nil {
+			return err
+		}
+	}
+	delete(n.parent.children, n.name)
+	return r.release(n.inode)
+}
+
+// prune removes all entries below n not written by the given layer.
+func (r *RootFS) prune(n *node, number int) error {
+	for _, child := range n.children {
+		switch {
+		case !written(child, number):
+			if err := r.remove(child); err != nil {
+				return err
+			}
+		case child.inode.mode.IsDir():
+			if err := r.prune(child, number); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// written reports whether n or any entry below it was written by the given layer.
+func written(n *node, number int) bool {
+	if n.layer == number {
+		return true
+	}
+	for _, child := range n.children {
+		if written(child, number) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package container_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	ironcoreimage "github.com/ironcore-dev/ironcore-image"
+	. "github.com/ironcore-dev/ironcore-image/container"
+	"github.com/ironcore-dev/ironcore-image/fs/squashfs"
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/ironcore-dev/ironcore-image/oci/imageutil"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+var mtime = time.Unix(1700000000, 0)
+
+func dirEntry(name string) *tar.Header {
+	return &tar.Header{Typeflag: tar.TypeDir, Name: name, Mode: 0o755, ModTime: mtime}
+}
+
+// regularFile is a regular file entry of a tar layer.
+type regularFile struct {
+	name, content string
+}
+
+func tarLayer(compression ironcoreimage.Compression, entries ...any) image.Layer {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, entry := range entries {
+		switch entry := entry.(type) {
+		case *tar.Header:
+			Expect(tw.WriteHeader(entry)).To(Succeed())
+		case string:
+			_, err := tw.Write([]byte(entry))
+			Expect(err).NotTo(HaveOccurred())
+		case regularFile:
+			Expect(tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeReg, Name: entry.name, Mode: 0o644, Size: int64(len(entry.content)), ModTime: mtime,
+			})).To(Succeed())
+			_, err := tw.Write([]byte(entry.content))
+			Expect(err).NotTo(HaveOccurred())
+		}
+	}
+	Expect(tw.Close()).To(Succeed())
+
+	data := buf.Bytes()
+	if compression != ironcoreimage.CompressionNone {
+		var compressed bytes.Buffer
+		_, err := ironcoreimage.Compress(&compressed, &buf, compression)
+		Expect(err).NotTo(HaveOccurred())
+		data = compressed.Bytes()
+	}
+	return imageutil.BytesLayer(data, imageutil.WithMediaType(ironcoreimage.CompressedMediaType(ocispec.MediaTypeImageLayer, compression)))
+}
+
+func flatten(ctx context.Context, layers ...image.Layer) *RootFS {
+	img, err := imageutil.NewBytesConfigBuilder([]byte("{}"), imageutil.WithMediaType(ocispec.MediaTypeImageConfig)).
+		Layers(layers...).
+		Complete()
+	Expect(err).NotTo(HaveOccurred())
+
+	rootFS, err := Flatten(ctx, img, GinkgoT().TempDir())
+	Expect(err).NotTo(HaveOccurred())
+	return rootFS
+}
+
+func readFile(rootFS *RootFS, name string) string {
+	data, err := fs.ReadFile(rootFS, name)
+	Expect(err).NotTo(HaveOccurred())
+	return string(data)
+}
+
+var _ = Describe("Container", func() {
+	It("should flatten layers of all compressions", func(ctx SpecContext) {
+		rootFS := flatten(ctx,
+			tarLayer(ironcoreimage.CompressionNone, dirEntry("etc/"), regularFile{"etc/os-release", "ID=test\n"}),
+			tarLayer(ironcoreimage.CompressionGzip, regularFile{"etc/hostname", "gzip\n"}),
+			tarLayer(ironcoreimage.CompressionZstd, regularFile{"./etc/hostname", "zstd\n"}),
+		)
+
+		Expect(readFile(rootFS, "etc/os-release")).To(Equal("ID=test\n"))
+		Expect(readFile(rootFS, "etc/hostname")).To(Equal("zstd\n"))
+		Expect(fs.Glob(rootFS, "etc/*")).To(Equal([]string{"etc/hostname", "etc/os-release"}))
+	})
+
+	It("should apply whiteouts of lower layers", func(ctx SpecContext) {
+		rootFS := flatten(ctx,
+			tarLayer(ironcoreimage.CompressionNone,
+				dirEntry("a/"), regularFile{"a/one", "1"}, regularFile{"a/two", "2"},
+				dirEntry("b/"), regularFile{"b/one", "1"}, regularFile{"b/two", "2"},
+				regularFile{"c", "c"},
+			),
+			tarLayer(ironcoreimage.CompressionNone,
+				regularFile{"a/three", "3"},
+				&tar.Header{Typeflag: tar.TypeReg, Name: "a/.wh..wh..opq"},
+				&tar.Header{Typeflag: tar.TypeReg, Name: "b/.wh.one"},
+				regularFile{"c", "new"},
+				&tar.Header{Typeflag: tar.TypeReg, Name: ".wh.c"},
+			),
+		)
+
+		Expect(fs.Glob(rootFS, "*/*")).To(Equal([]string{"a/three", "b/two"}))
+		Expect(readFile(rootFS, "c")).To(Equal("new"))
+	})
+
+	It("should keep ownership, links, devices and xattrs", func(ctx SpecContext) {
+		rootFS := flatten(ctx, tarLayer(ironcoreimage.CompressionGzip,
+			dirEntry("usr/"), dirEntry("usr/lib/"),
+			&tar.Header{Typeflag: tar.TypeSymlink, Name: "lib", Linkname: "usr/lib", ModTime: mtime},
+			&tar.Header{
+				Typeflag: tar.TypeReg, Name: "usr/lib/data", Mode: 0o4750, Uid: 1000, Gid: 100, Size: 4, ModTime: mtime,
+				PAXRecords: map[string]string{"SCHILY.xattr.user.test": "value"},
+			}, "data",
+			&tar.Header{Typeflag: tar.TypeLink, Name: "usr/lib/link", Linkname: "usr/lib/data"},
+			&tar.Header{Typeflag: tar.TypeChar, Name: "null", Mode: 0o666, Devmajor: 1, Devminor: 3, ModTime: mtime},
+		))
+
+		Expect(readFile(rootFS, "lib/link")).To(Equal("data"))
+		Expect(rootFS.ReadLink("lib")).To(Equal("usr/lib"))
+		Expect(rootFS.Xattrs("usr/lib/link")).To(Equal(map[string][]byte{"user.test": []byte("value")}))
+
+		info, err := rootFS.Lstat("usr/lib/data")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode()).To(Equal(fs.ModeSetuid | 0o750))
+		Expect(info.ModTime()).To(BeTemporally("==", mtime))
+		link, err := rootFS.Lstat("usr/lib/link")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(link.Sys()).To(Equal(info.Sys()))
+		Expect(info.Sys()).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"UID":   BeEquivalentTo(1000),
+			"GID":   BeEquivalentTo(100),
+			"Nlink": BeEquivalentTo(2),
+		})))
+
+		info, err = rootFS.Lstat("null")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode()).To(Equal(fs.ModeDevice | fs.ModeCharDevice | 0o666))
+		Expect(info.Sys()).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Major": BeEquivalentTo(1),
+			"Minor": BeEquivalentTo(3),
+		})))
+	})
+
+	It("should write below symbolic links of lower layers into their targets", func(ctx SpecContext) {
+		rootFS := flatten(ctx,
+			tarLayer(ironcoreimage.CompressionNone,
+				dirEntry("usr/"), dirEntry("usr/lib/"), regularFile{"usr/lib/old.so", "old"},
+				&tar.Header{Typeflag: tar.TypeSymlink, Name: "lib", Linkname: "usr/lib", ModTime: mtime},
+				&tar.Header{Typeflag: tar.TypeSymlink, Name: "sbin", Linkname: "/../usr/sbin", ModTime: mtime},
+			),
+			tarLayer(ironcoreimage.CompressionNone,
+				regularFile{"lib/new.so", "new"},
+				&tar.Header{Typeflag: tar.TypeLink, Name: "lib/link.so", Linkname: "lib/new.so"},
+				&tar.Header{Typeflag: tar.TypeReg, Name: "lib/.wh.old.so"},
+				regularFile{"sbin/init", "init"},
+			),
+		)
+
+		Expect(rootFS.ReadLink("lib")).To(Equal("usr/lib"))
+		Expect(rootFS.ReadLink("sbin")).To(Equal("/../usr/sbin"))
+		Expect(fs.Glob(rootFS, "usr/*/*")).To(Equal([]string{"usr/lib/link.so", "usr/lib/new.so", "usr/sbin/init"}))
+		Expect(readFile(rootFS, "lib/link.so")).To(Equal("new"))
+		Expect(readFile(rootFS, "sbin/init")).To(Equal("init"))
+	})
+
+	It("should not escape the root", func(ctx SpecContext) {
+		rootFS := flatten(ctx, tarLayer(ironcoreimage.CompressionNone,
+			regularFile{"../../escape", "x"},
+			&tar.Header{Typeflag: tar.TypeSymlink, Name: "up", Linkname: "../../..", ModTime: mtime},
+		))
+
+		Expect(readFile(rootFS, "escape")).To(Equal("x"))
+		Expect(readFile(rootFS, "up/escape")).To(Equal("x"))
+	})
+
+	It("should be packed into squashfs images", func(ctx SpecContext) {
+		rootFS := flatten(ctx, tarLayer(ironcoreimage.CompressionNone,
+			dirEntry("etc/"), regularFile{"etc/os-release", "ID=test\n"},
+			&tar.Header{Typeflag: tar.TypeLink, Name: "etc/link", Linkname: "etc/os-release"},
+		))
+
+		path := filepath.Join(GinkgoT().TempDir(), "rootfs.squashfs")
+		f, err := os.Create(path)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(f.Close)
+		Expect(squashfs.WriteFS(f, rootFS, squashfs.WriteOptions{})).To(Succeed())
+
+		sqfs, err := squashfs.New(f)
+		Expect(err).NotTo(HaveOccurred())
+		data, err := fs.ReadFile(sqfs, "etc/link")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("ID=test\n"))
+		info, err := sqfs.Lstat("etc/link")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Sys()).To(PointTo(MatchFields(IgnoreExtras, Fields{"Nlink": BeEquivalentTo(2)})))
+	})
+
+	It("should fail for layers of other media types", func(ctx SpecContext) {
+		img, err := imageutil.NewBytesConfigBuilder([]byte("{}")).
+			BytesLayer([]byte("data"), imageutil.WithMediaType("application/octet-stream")).
+			Complete()
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = Flatten(ctx, img, GinkgoT().TempDir())
+		Expect(err).To(MatchError(ErrUnsupportedLayer))
+	})
+
+	It("should read the platform from the config", func(ctx SpecContext) {
+		img, err := imageutil.NewBytesConfigBuilder([]byte(`{"architecture":"arm64","os":"linux","variant":"v8"}`),
+			imageutil.WithMediaType(ocispec.MediaTypeImageConfig)).
+			Complete()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(Platform(ctx, img)).To(Equal(ocispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}))
+	})
+})
@@ -0,0 +1,250 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package container
+
+import (
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ironcore-dev/ironcore-image/fs/squashfs"
+)
+
+// maxSymlinks is the maximum number of symbolic links followed when resolving a path.
+const maxSymlinks = 40
+
+// RootFS is the flattened root file system of a container image.
+// It implements fs.ReadDirFS, fs.ReadLinkFS and squashfs.XattrFS, so it can be packed with squashfs.WriteFS.
+// Ownership, hard links and devices are reported by the *squashfs.Stat returned by Sys.
+type RootFS struct {
+	root   *node
+	dir    string
+	inodes uint64
+}
+
+var (
+	_ fs.ReadDirFS     = (*RootFS)(nil)
+	_ fs.ReadLinkFS    = (*RootFS)(nil)
+	_ squashfs.XattrFS = (*RootFS)(nil)
+)
+
+// resolve returns the entry name, following symbolic links in all path elements and,
+// if follow is set, in the final one. Absolute link targets are resolved against the root.
+func (r *RootFS) resolve(op, name string, follow bool) (*node, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	n := r.root
+	elems := strings.Split(name, "/")
+	if name == "." {
+		elems = nil
+	}
+	for links := 0; len(elems) > 0; {
+		elem := elems[0]
+		elems = elems[1:]
+
+		switch elem {
+		case ".":
+			continue
+		case "..":
+			if n.parent != nil {
+				n = n.parent
+			}
+			continue
+		}
+		if !n.inode.mode.IsDir() {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		child := n.children[elem]
+		if child == nil {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		if child.inode.mode.Type() != fs.ModeSymlink || (len(elems) == 0 && !follow) {
+			n = child
+			continue
+		}
+
+		if links++; links > maxSymlinks {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+		}
+		target := child.inode.target
+		if strings.HasPrefix(target, "/") {
+			n = r.root
+		}
+		elems = append(strings.Split(strings.Trim(target, "/"), "/"), elems...)
+	}
+	return n, nil
+}
+
+// Open opens the named file, following symbolic links.
+func (r *RootFS) Open(name string) (fs.File, error) {
+	n, err := r.resolve("open", name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &fileInfo{name: path.Base(name), node: n}
+	switch {
+	case n.inode.mode.IsDir():
+		return &dir{info: info, entries: r.entries(n)}, nil
+	case n.inode.data != "":
+		f, err := os.Open(n.inode.data)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		return &file{File: f, info: info}, nil
+	default:
+		return &empty{info: info}, nil
+	}
+}
+
+// ReadDir reads the named directory, returning its entries sorted by name.
+func (r *RootFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	n, err := r.resolve("readdir", name, true)
+	if err != nil {
+		return nil, err
+	}
+	if !n.inode.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	return r.entries(n), nil
+}
+
+func (r *RootFS) entries(n *node) []fs.DirEntry {
+	entries := make([]fs.DirEntry, 0, len(n.children))
+	for _, name := range slices.Sorted(maps.Keys(n.children)) {
+		entries = append(entries, fs.FileInfoToDirEntry(&fileInfo{name: name, node: n.children[name]}))
+	}
+	return entries
+}
+
+// ReadLink returns the target of the named symbolic link.
+func (r *RootFS) ReadLink(name string) (string, error) {
+	n, err := r.resolve("readlink", name, false)
+	if err != nil {
+		return "", err
+	}
+	if n.inode.mode.Type() != fs.ModeSymlink {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return n.inode.target, nil
+}
+
+// Lstat returns a FileInfo describing the named file without following a final symbolic link.
+func (r *RootFS) Lstat(name string) (fs.FileInfo, error) {
+	n, err := r.resolve("lstat", name, false)
+	if err != nil {
+		return nil, err
+	}
+	return &fileInfo{name: path.Base(name), node: n}, nil
+}
+
+// Xattrs returns the extended attributes of the named file without following a final symbolic link.
+func (r *RootFS) Xattrs(name string) (map[string][]byte, error) {
+	n, err := r.resolve("xattrs", name, false)
+	if err != nil {
+		return nil, err
+	}
+	return maps.Clone(n.inode.xattrs), nil
+}
+
+// Path returns the path of the local file holding the content of the named regular file,
+// following symbolic links. The file must not be modified by the caller.
+func (r *RootFS) Path(name string) (string, error) {
+	n, err := r.resolve("open", name, true)
+	if err != nil {
+		return "", err
+	}
+	if !n.inode.mode.IsRegular() {
+		return "", &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	return n.inode.data, nil
+}
+
+type fileInfo struct {
+	name string
+	node *node
+}
+
+func (fi *fileInfo) Name() string {
+	if fi.name == "." || fi.name == "/" {
+		return "."
+	}
+	return fi.name
+}
+
+func (fi *fileInfo) Size() int64 {
+	if fi.node.inode.mode.Type() == fs.ModeSymlink {
+		return int64(len(fi.node.inode.target))
+	}
+	return fi.node.inode.size
+}
+
+func (fi *fileInfo) Mode() fs.FileMode  { return fi.node.inode.mode }
+func (fi *fileInfo) ModTime() time.Time { return fi.node.inode.mtime }
+func (fi *fileInfo) IsDir() bool        { return fi.node.inode.mode.IsDir() }
+
+func (fi *fileInfo) Sys() any {
+	ino := fi.node.inode
+	nlink := ino.nlink
+	if ino.mode.IsDir() {
+		nlink = 2
+		for _, child := range fi.node.children {
+			if child.inode.mode.IsDir() {
+				nlink++
+			}
+		}
+	}
+	return &squashfs.Stat{UID: ino.uid, GID: ino.gid, Ino: ino.ino, Nlink: nlink, Major: ino.major, Minor: ino.minor}
+}
+
+type file struct {
+	*os.File
+	info *fileInfo
+}
+
+func (f *file) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+// empty is an open file without content, e.g. a device or named pipe.
+type empty struct {
+	info *fileInfo
+}
+
+func (e *empty) Stat() (fs.FileInfo, error) { return e.info, nil }
+func (e *empty) Read([]byte) (int, error)   { return 0, io.EOF }
+func (e *empty) Close() error               { return nil }
+
+type dir struct {
+	info    *fileInfo
+	entries []fs.DirEntry
+}
+
+func (d *dir) Stat() (fs.FileInfo, error) { return d.info, nil }
+
+func (d *dir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *dir) Close() error { return nil }
+
+func (d *dir) ReadDir(count int) ([]fs.DirEntry, error) {
+	if count <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	count = min(count, len(d.entries))
+	entries := d.entries[:count]
+	d.entries = d.entries[count:]
+	return entries, nil
+}
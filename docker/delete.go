@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+)
+
+var (
+	// ErrDeleteUnsupported is returned if a registry does not allow deleting manifests.
+	ErrDeleteUnsupported = errors.New("registry does not allow deleting manifests")
+	// ErrDeleteDenied is returned if the credentials do not permit deleting manifests.
+	ErrDeleteDenied = errors.New("not permitted to delete manifests")
+	// ErrManifestNotFound is returned if the manifest to delete does not exist.
+	ErrManifestNotFound = errors.New("manifest not found")
+)
+
+// DeleteManifest deletes the manifest with the given digest from the repository of ref.
+// Registries delete manifests by digest only, so all tags pointing to the manifest are removed as well.
+func (u *RequestResolver) DeleteManifest(ctx context.Context, ref string, dgst digest.Digest) error {
+	named, err := reference.ParseNamed(ref)
+	if err != nil {
+		return fmt.Errorf("ref %s is no named reference: %w", ref, err)
+	}
+	if err := dgst.Validate(); err != nil {
+		return fmt.Errorf("invalid digest %s: %w", dgst, err)
+	}
+
+	registry, err := u.pushHost(reference.Domain(named))
+	if err != nil {
+		return err
+	}
+
+	name := reference.Path(named)
+	ctx = docker.WithScope(ctx, fmt.Sprintf("repository:%s:delete", name))
+
+	target := fmt.Sprintf("%s://%s%s/%s/manifests/%s", registry.Scheme, registry.Host, registry.Path, name, dgst)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, target, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := u.do(ctx, *registry, req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		return nil
+	case res.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s@%s", ErrManifestNotFound, named.Name(), dgst)
+	case res.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%w: %s@%s", ErrDeleteDenied, named.Name(), dgst)
+	case res.StatusCode == http.StatusMethodNotAllowed, isUnsupported(res):
+		return fmt.Errorf("%w: %s responded with %s", ErrDeleteUnsupported, registry.Host, res.Status)
+	default:
+		return fmt.Errorf("erroneous response status: %s for url %s", res.Status, req.URL)
+	}
+}
+
+// isUnsupported reports whether the response carries the distribution UNSUPPORTED error code,
+// which some registries send with status 400 if deletion is disabled.
+func isUnsupported(res *http.Response) bool {
+	if res.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	body, err := io.ReadAll(io.LimitReader(res.Body, 4096))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(body), `"UNSUPPORTED"`)
+}
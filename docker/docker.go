@@ -21,6 +21,7 @@ import (
 type RequestResolver struct {
 	resolver remotes.Resolver
 	hosts    docker.RegistryHosts
+	client   *http.Client
 }
 
 type Info interface {
@@ -225,6 +226,30 @@ func (i *layerInfo) Descriptor() ocispec.Descriptor {
 	return i.desc
 }
 
+// pullHost returns the first registry host of the given host name providing pull capability.
+func (u *RequestResolver) pullHost(host string) (*docker.RegistryHost, error) {
+	return u.registryHost(host, docker.HostCapabilityPull, "pull")
+}
+
+// pushHost returns the first registry host of the given host name providing push capability.
+func (u *RequestResolver) pushHost(host string) (*docker.RegistryHost, error) {
+	return u.registryHost(host, docker.HostCapabilityPush, "push")
+}
+
+func (u *RequestResolver) registryHost(host string, capability docker.HostCapabilities, capabilityName string) (*docker.RegistryHost, error) {
+	regs, err := u.hosts(host)
+	if err != nil {
+		return nil, fmt.Errorf("error getting host for %s", host)
+	}
+	for _, reg := range regs {
+		if reg.Capabilities.Has(capability) {
+			reg := reg
+			return &reg, nil
+		}
+	}
+	return nil, fmt.Errorf("no registry providing %s capability for host %s", capabilityName, host)
+}
+
 func (u *RequestResolver) Resolve(ctx context.Context, ref string) (ManifestInfo, error) {
 	r, err := reference.ParseNamed(ref)
 	if err != nil {
@@ -236,21 +261,9 @@ func (u *RequestResolver) Resolve(ctx context.Context, ref string) (ManifestInfo
 		tag = tagged.Tag()
 	}
 
-	host := reference.Domain(r)
-	regs, err := u.hosts(host)
+	found, err := u.pullHost(reference.Domain(r))
 	if err != nil {
-		return nil, fmt.Errorf("error getting host for %s", host)
-	}
-	var found *docker.RegistryHost
-	for _, reg := range regs {
-		if reg.Capabilities.Has(docker.HostCapabilityPull) {
-			reg := reg
-			found = &reg
-			break
-		}
-	}
-	if found == nil {
-		return nil, fmt.Errorf("no registry providing pull capability for host %s", host)
+		return nil, err
 	}
 
 	info := &manifestInfo{
@@ -259,7 +272,7 @@ func (u *RequestResolver) Resolve(ctx context.Context, ref string) (ManifestInfo
 		resolver: u.resolver,
 		baseInfo: baseInfo{
 			name:     reference.Path(r),
-			client:   http.DefaultClient,
+			client:   u.client,
 			registry: *found,
 		},
 	}
@@ -307,5 +320,6 @@ func NewRequestResolver(o RequestResolverOptions) (*RequestResolver, error) {
 	return &RequestResolver{
 		resolver: resolver,
 		hosts:    hosts,
+		client:   o.Client,
 	}, nil
 }
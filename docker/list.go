@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/distribution/reference"
+	"github.com/ironcore-dev/ironcore-image/oci/remote"
+)
+
+// ListOptions are options for listing tags or repositories.
+type ListOptions struct {
+	// PageSize is the number of entries to request per page. If zero, the registry default is used.
+	PageSize int
+}
+
+// Tags lists all tags of the given repository, following pagination until all tags are retrieved.
+func (u *RequestResolver) Tags(ctx context.Context, repository string, o ListOptions) ([]string, error) {
+	named, err := reference.ParseNormalizedNamed(repository)
+	if err != nil {
+		return nil, fmt.Errorf("repository %s is no named reference: %w", repository, err)
+	}
+	if !reference.IsNameOnly(named) {
+		return nil, fmt.Errorf("repository %s must not contain a tag or digest", repository)
+	}
+
+	registry, err := u.pullHost(reference.Domain(named))
+	if err != nil {
+		return nil, err
+	}
+
+	name := reference.Path(named)
+	ctx = docker.ContextWithAppendPullRepositoryScope(ctx, name)
+
+	var tags []string
+	err = u.list(ctx, *registry, fmt.Sprintf("/%s/tags/list", name), o, func(body io.Reader) error {
+		var res struct {
+			Tags []string `json:"tags"`
+		}
+		if err := json.NewDecoder(body).Decode(&res); err != nil {
+			return fmt.Errorf("error decoding tag list: %w", err)
+		}
+		tags = append(tags, res.Tags...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Catalog lists all repositories of the given registry host using the registry catalog API.
+// Not all registries support the catalog API or allow all users to access it.
+func (u *RequestResolver) Catalog(ctx context.Context, host string, o ListOptions) ([]string, error) {
+	registry, err := u.pullHost(host)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = docker.WithScope(ctx, "registry:catalog:*")
+
+	var repositories []string
+	err = u.list(ctx, *registry, "/_catalog", o, func(body io.Reader) error {
+		var res struct {
+			Repositories []string `json:"repositories"`
+		}
+		if err := json.NewDecoder(body).Decode(&res); err != nil {
+			return fmt.Errorf("error decoding catalog: %w", err)
+		}
+		repositories = append(repositories, res.Repositories...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repositories, nil
+}
+
+// list issues authorized GET requests against the given path of the registry and calls handle
+// for each page, following the 'next' links of the responses.
+func (u *RequestResolver) list(ctx context.Context, registry docker.RegistryHost, path string, o ListOptions, handle func(body io.Reader) error) error {
+	next, err := url.Parse(fmt.Sprintf("%s://%s%s%s", registry.Scheme, registry.Host, registry.Path, path))
+	if err != nil {
+		return err
+	}
+	if o.PageSize > 0 {
+		next.RawQuery = url.Values{"n": []string{strconv.Itoa(o.PageSize)}}.Encode()
+	}
+
+	for next != nil {
+		res, err := u.get(ctx, registry, next)
+		if err != nil {
+			return err
+		}
+
+		err = handle(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		next, err = nextLink(res)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *RequestResolver) get(ctx context.Context, registry docker.RegistryHost, target *url.URL) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := u.do(ctx, registry, req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		_ = res.Body.Close()
+		return nil, fmt.Errorf("erroneous response status: %s for url %s", res.Status, req.URL)
+	}
+	return res, nil
+}
+
+// do authorizes and issues the request, retrying on authentication challenges and
+// transient errors. The final response is returned regardless of its status code.
+func (u *RequestResolver) do(ctx context.Context, registry docker.RegistryHost, req *http.Request) (*http.Response, error) {
+	return remote.Do(ctx, u.client, registry, req)
+}
+
+// nextLink returns the URL of the 'next' link of the response, if any.
+func nextLink(res *http.Response) (*url.URL, error) {
+	for _, link := range res.Header.Values("Link") {
+		for _, part := range strings.Split(link, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(part), ";")
+			if !ok || !strings.Contains(strings.ReplaceAll(params, " ", ""), `rel="next"`) {
+				continue
+			}
+
+			target = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(target), "<"), ">")
+			next, err := res.Request.URL.Parse(target)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing next link %q: %w", target, err)
+			}
+			return next, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ironcoreimage
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/ironcore-dev/ironcore-image/fs/ext4"
+	"github.com/ironcore-dev/ironcore-image/fs/squashfs"
+	"github.com/klauspost/compress/zstd"
+)
+
+// FormatAnnotation is the layer annotation holding the detected format of the (uncompressed) layer content.
+const FormatAnnotation = "dev.ironcore.image.format"
+
+// FormatType is a type of layer content, detected by its magic numbers.
+type FormatType string
+
+const (
+	// FormatBzImage is an x86 Linux kernel in bzImage format.
+	FormatBzImage FormatType = "bzimage"
+	// FormatARM64Image is an arm64 Linux kernel image.
+	FormatARM64Image FormatType = "arm64-image"
+	// FormatRISCVImage is a riscv Linux kernel image.
+	FormatRISCVImage FormatType = "riscv-image"
+	// FormatPE is a PE executable, e.g. a Linux kernel with EFI stub.
+	FormatPE FormatType = "pe"
+	// FormatELF is an ELF executable, e.g. an uncompressed vmlinux.
+	FormatELF FormatType = "elf"
+	// FormatUKI is a unified kernel image, a PE executable with a .linux section.
+	FormatUKI FormatType = "uki"
+	// FormatCPIO is a cpio archive, e.g. an initramfs.
+	FormatCPIO FormatType = "cpio"
+	// FormatSquashFS is a squashfs file system image.
+	FormatSquashFS FormatType = "squashfs"
+	// FormatExt4 is an ext2/3/4 file system image.
+	FormatExt4 FormatType = "ext4"
+	// FormatEROFS is an erofs file system image.
+	FormatEROFS FormatType = "erofs"
+	// FormatISO9660 is an ISO 9660 image.
+	FormatISO9660 FormatType = "iso9660"
+)
+
+var (
+	// ErrFormatMismatch is returned by CheckFormat if the content is of a format not suitable for the layer type.
+	ErrFormatMismatch = errors.New("format does not match layer type")
+	// ErrUnknownFormat is returned by CheckFormat if the format of the content could not be detected.
+	ErrUnknownFormat = errors.New("unknown format")
+)
+
+// layerFormats are the format types suitable for each layer type.
+var layerFormats = map[LayerType][]FormatType{
+	KernelLayerType:    {FormatBzImage, FormatARM64Image, FormatRISCVImage, FormatPE, FormatELF},
+	InitRAMFSLayerType: {FormatCPIO},
+	RootFSLayerType:    {FormatExt4, FormatSquashFS, FormatEROFS},
+	SquashFSLayerType:  {FormatSquashFS},
+	UKILayerType:       {FormatUKI},
+	ISOLayerType:       {FormatISO9660},
+}
+
+const (
+	// sniffSize is the amount of content read to detect its format.
+	// It covers the primary volume descriptor of ISO 9660 images at 32 KiB.
+	sniffSize = 0x8010
+
+	erofsMagic = 0xE0F5E1E2
+)
+
+// compressions are the magic numbers of compressed content. Compressions without decompressor
+// are only detected, the format of their content remains unknown.
+var compressions = []struct {
+	name  string
+	magic []byte
+	open  func(r io.Reader) (io.ReadCloser, error)
+}{
+	{"gzip", []byte{0x1f, 0x8b}, func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }},
+	{"zstd", []byte{0x28, 0xb5, 0x2f, 0xfd}, func(r io.Reader) (io.ReadCloser, error) {
+		d, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	}},
+	{"bzip2", []byte("BZh"), func(r io.Reader) (io.ReadCloser, error) { return io.NopCloser(bzip2.NewReader(r)), nil }},
+	{"xz", []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, nil},
+	{"lz4", []byte{0x02, 0x21, 0x4c, 0x18}, nil},
+	{"lz4", []byte{0x04, 0x22, 0x4d, 0x18}, nil},
+}
+
+// Format is the detected format of layer content.
+type Format struct {
+	// Type is the type of the (decompressed) content. Empty if unknown.
+	Type FormatType
+	// Compression is the compression of the content, e.g. gzip or xz. Empty if uncompressed.
+	Compression string
+}
+
+// String returns the format as type with compression suffix, e.g. "cpio+zstd". It returns only the
+// compression if the type is unknown and an empty string if nothing was detected.
+func (f Format) String() string {
+	switch {
+	case f.Compression == "":
+		return string(f.Type)
+	case f.Type == "":
+		return f.Compression
+	default:
+		return string(f.Type) + "+" + f.Compression
+	}
+}
+
+// DetectFormat detects the format of the content of r by its magic numbers. The content of
+// compressed data is detected by decompressing its beginning, if a decompressor is available.
+func DetectFormat(r io.ReaderAt) (Format, error) {
+	header, err := readHeader(r)
+	if err != nil {
+		return Format{}, err
+	}
+	if t := detectType(bytes.NewReader(header), header); t != "" {
+		return Format{Type: t}, nil
+	}
+
+	for _, c := range compressions {
+		if !bytes.HasPrefix(header, c.magic) {
+			continue
+		}
+
+		f := Format{Compression: c.name}
+		if c.open == nil {
+			return f, nil
+		}
+		rc, err := c.open(io.NewSectionReader(r, 0, 1<<62))
+		if err != nil {
+			// Corrupt compressed data is reported as is, with unknown content.
+			return f, nil
+		}
+		defer func() { _ = rc.Close() }()
+
+		// Errors are ignored, as only the beginning of the content is decompressed.
+		content, _ := io.ReadAll(io.LimitReader(rc, sniffSize))
+		f.Type = detectType(bytes.NewReader(content), content)
+		return f, nil
+	}
+	return Format{}, nil
+}
+
+func readHeader(r io.ReaderAt) ([]byte, error) {
+	header := make([]byte, sniffSize)
+	n, err := r.ReadAt(header, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error reading content: %w", err)
+	}
+	return header[:n], nil
+}
+
+// detectType detects the type of the content of r, of which header is the beginning.
+func detectType(r io.ReaderAt, header []byte) FormatType {
+	at := func(offset int, magic string) bool {
+		return len(header) >= offset+len(magic) && string(header[offset:offset+len(magic)]) == magic
+	}
+
+	isPE := false
+	if at(0, "MZ") && len(header) >= 0x40 {
+		peOffset := int(binary.LittleEndian.Uint32(header[0x3c:]))
+		isPE = at(peOffset, "PE\x00\x00")
+	}
+	if isPE {
+		if f, err := pe.NewFile(r); err == nil && slices.ContainsFunc(f.Sections, func(s *pe.Section) bool { return s.Name == ".linux" }) {
+			return FormatUKI
+		}
+	}
+
+	switch {
+	case at(0x1fe, "\x55\xaa") && at(0x202, "HdrS"):
+		return FormatBzImage
+	case at(0x38, "ARM\x64"):
+		return FormatARM64Image
+	case at(0x38, "RSC\x05"):
+		return FormatRISCVImage
+	case isPE:
+		return FormatPE
+	case at(0, "\x7fELF"):
+		return FormatELF
+	case at(0, "070701"), at(0, "070702"), at(0, "070707"), at(0, "\xc7\x71"), at(0, "\x71\xc7"):
+		return FormatCPIO
+	case squashfs.IsSquashFS(r):
+		return FormatSquashFS
+	case len(header) >= 1028 && binary.LittleEndian.Uint32(header[1024:]) == erofsMagic:
+		return FormatEROFS
+	case at(0x8001, "CD001"):
+		return FormatISO9660
+	case ext4.IsExt4(r):
+		return FormatExt4
+	default:
+		return ""
+	}
+}
+
+// CheckFormat checks whether content of format f is suitable for a layer of the given type.
+// It returns an error wrapping ErrFormatMismatch if not, and one wrapping ErrUnknownFormat if
+// the format is unknown. As the kernel decompresses initramfs archives itself, compressed
+// content of unknown format is accepted for initramfs layers.
+func CheckFormat(layerType LayerType, f Format) error {
+	formats, ok := layerFormats[layerType]
+	switch {
+	case !ok:
+		return fmt.Errorf("unknown layer type %q", layerType)
+	case f.Type != "" && slices.Contains(formats, f.Type):
+		return nil
+	case f.Type != "":
+		return fmt.Errorf("%w: detected %s content for %s layer, expected one of %v", ErrFormatMismatch, f, layerType, formats)
+	case f.Compression != "" && layerType == InitRAMFSLayerType:
+		return nil
+	case f.Compression != "":
+		return fmt.Errorf("%w: detected %s compressed content of unknown format for %s layer", ErrUnknownFormat, f.Compression, layerType)
+	default:
+		return fmt.Errorf("%w of %s layer", ErrUnknownFormat, layerType)
+	}
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ironcoreimage_test
+
+import (
+	"bytes"
+
+	. "github.com/ironcore-dev/ironcore-image"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// content returns data of the given size with magic at offset.
+func content(size, offset int, magic string) []byte {
+	data := make([]byte, size)
+	copy(data[offset:], magic)
+	return data
+}
+
+func compressed(data []byte, c Compression) []byte {
+	var buf bytes.Buffer
+	_, err := Compress(&buf, bytes.NewReader(data), c)
+	Expect(err).NotTo(HaveOccurred())
+	return buf.Bytes()
+}
+
+var _ = Describe("Format", func() {
+	bzImage := content(0x400, 0x1fe, "\x55\xaa\xeb\x66HdrS")
+	copy(bzImage, "MZ")
+	cpio := content(0x200, 0, "070701")
+
+	DescribeTable("DetectFormat",
+		func(data []byte, expected Format) {
+			Expect(DetectFormat(bytes.NewReader(data))).To(Equal(expected))
+		},
+		Entry("bzImage", bzImage, Format{Type: FormatBzImage}),
+		Entry("arm64 Image", content(0x100, 0x38, "ARM\x64"), Format{Type: FormatARM64Image}),
+		Entry("ELF", content(0x100, 0, "\x7fELF"), Format{Type: FormatELF}),
+		Entry("cpio", cpio, Format{Type: FormatCPIO}),
+		Entry("zstd compressed cpio", compressed(cpio, CompressionZstd), Format{Type: FormatCPIO, Compression: "zstd"}),
+		Entry("gzip compressed arm64 Image", compressed(content(0x100, 0x38, "ARM\x64"), CompressionGzip),
+			Format{Type: FormatARM64Image, Compression: "gzip"}),
+		Entry("xz compressed content", content(0x100, 0, "\xfd7zXZ\x00"), Format{Compression: "xz"}),
+		Entry("squashfs", content(0x1000, 0, "hsqs"), Format{Type: FormatSquashFS}),
+		Entry("ext4", content(0x1000, 1080, "\x53\xef"), Format{Type: FormatExt4}),
+		Entry("erofs", content(0x1000, 1024, "\xe2\xe1\xf5\xe0"), Format{Type: FormatEROFS}),
+		Entry("ISO 9660", content(0x9000, 0x8001, "CD001"), Format{Type: FormatISO9660}),
+		Entry("unknown content", []byte("kernel"), Format{}),
+	)
+
+	It("should format the format with its compression", func() {
+		Expect(Format{Type: FormatCPIO, Compression: "zstd"}.String()).To(Equal("cpio+zstd"))
+		Expect(Format{Compression: "xz"}.String()).To(Equal("xz"))
+		Expect(Format{Type: FormatSquashFS}.String()).To(Equal("squashfs"))
+	})
+
+	It("should check the format against the layer type", func() {
+		Expect(CheckFormat(KernelLayerType, Format{Type: FormatBzImage})).To(Succeed())
+		Expect(CheckFormat(KernelLayerType, Format{Type: FormatCPIO, Compression: "gzip"})).To(MatchError(ErrFormatMismatch))
+		Expect(CheckFormat(RootFSLayerType, Format{Type: FormatSquashFS})).To(Succeed())
+		Expect(CheckFormat(SquashFSLayerType, Format{Type: FormatExt4})).To(MatchError(ErrFormatMismatch))
+		Expect(CheckFormat(InitRAMFSLayerType, Format{Compression: "xz"})).To(Succeed())
+		Expect(CheckFormat(KernelLayerType, Format{Compression: "xz"})).To(MatchError(ErrUnknownFormat))
+		Expect(CheckFormat(ISOLayerType, Format{})).To(MatchError(ErrUnknownFormat))
+	})
+})
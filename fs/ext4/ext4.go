@@ -0,0 +1,511 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ext4 implements read-only access to ext2, ext3 and ext4 file system images.
+//
+// Only what is required to read regular files, directories and symbolic links is supported,
+// i.e. extent trees, block maps and inline data. Journals are ignored, so images should be
+// cleanly unmounted.
+package ext4
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+
+	"github.com/ironcore-dev/ironcore-image/fs/internal/inodefs"
+)
+
+const (
+	superblockOffset = 1024
+	superblockSize   = 1024
+
+	magic = 0xEF53
+
+	// maxLogBlockSize is the binary logarithm of the maximum block size of 64 KiB divided by 1 KiB.
+	maxLogBlockSize = 6
+
+	rootInode = 2
+
+	incompatFileType = 0x0002
+	incompat64Bit    = 0x0080
+
+	flagExtents    = 0x80000
+	flagInlineData = 0x10000000
+
+	extentMagic = 0xF30A
+)
+
+// ErrNotExt4 is returned by New if the image is not an ext2, ext3 or ext4 file system.
+var ErrNotExt4 = errors.New("not an ext file system")
+
+// IsExt4 reports whether r starts with an ext2, ext3 or ext4 superblock.
+func IsExt4(r io.ReaderAt) bool {
+	var buf [2]byte
+	if _, err := r.ReadAt(buf[:], superblockOffset+56); err != nil {
+		return false
+	}
+	return binary.LittleEndian.Uint16(buf[:]) == magic
+}
+
+// FS is a read-only ext file system. It implements fs.FS, fs.ReadDirFS and fs.ReadFileFS.
+type FS struct {
+	r io.ReaderAt
+
+	blockSize      int64
+	inodeSize      int64
+	inodesPerGroup uint32
+	descSize       int64
+	descTableStart int64
+	incompat       uint32
+}
+
+// New opens the ext file system image r.
+func New(r io.ReaderAt) (*FS, error) {
+	sb := make([]byte, superblockSize)
+	if _, err := r.ReadAt(sb, superblockOffset); err != nil {
+		return nil, fmt.Errorf("error reading superblock: %w", err)
+	}
+	if binary.LittleEndian.Uint16(sb[56:]) != magic {
+		return nil, ErrNotExt4
+	}
+	logBlockSize := binary.LittleEndian.Uint32(sb[24:])
+	if logBlockSize > maxLogBlockSize {
+		return nil, fmt.Errorf("invalid superblock: block size 2^%d", 10+logBlockSize)
+	}
+
+	f := &FS{
+		r:              r,
+		blockSize:      1024 << logBlockSize,
+		inodesPerGroup: binary.LittleEndian.Uint32(sb[40:]),
+		inodeSize:      128,
+		descSize:       32,
+		incompat:       binary.LittleEndian.Uint32(sb[96:]),
+	}
+	if revLevel := binary.LittleEndian.Uint32(sb[76:]); revLevel >= 1 {
+		f.inodeSize = int64(binary.LittleEndian.Uint16(sb[88:]))
+	}
+	if f.incompat&incompat64Bit != 0 {
+		if descSize := binary.LittleEndian.Uint16(sb[254:]); descSize != 0 {
+			f.descSize = int64(descSize)
+		}
+	}
+	if f.inodesPerGroup == 0 || f.inodeSize < 128 || f.descSize < 32 {
+		return nil, fmt.Errorf("invalid superblock")
+	}
+
+	firstDataBlock := int64(binary.LittleEndian.Uint32(sb[20:]))
+	f.descTableStart = (firstDataBlock + 1) * f.blockSize
+	return f, nil
+}
+
+// inode is a parsed on-disk inode.
+type inode struct {
+	num   uint32
+	mode  uint16
+	size  int64
+	mtime time.Time
+	flags uint32
+	block []byte
+}
+
+func (i *inode) Size() int64        { return i.size }
+func (i *inode) ModTime() time.Time { return i.mtime }
+func (i *inode) Sys() any           { return nil }
+
+func (i *inode) Mode() fs.FileMode {
+	mode := fs.FileMode(i.mode & 0o777)
+	switch i.mode & 0xF000 {
+	case 0x4000:
+		mode |= fs.ModeDir
+	case 0xA000:
+		mode |= fs.ModeSymlink
+	case 0x2000:
+		mode |= fs.ModeDevice | fs.ModeCharDevice
+	case 0x6000:
+		mode |= fs.ModeDevice
+	case 0x1000:
+		mode |= fs.ModeNamedPipe
+	case 0xC000:
+		mode |= fs.ModeSocket
+	}
+	if i.mode&0o4000 != 0 {
+		mode |= fs.ModeSetuid
+	}
+	if i.mode&0o2000 != 0 {
+		mode |= fs.ModeSetgid
+	}
+	if i.mode&0o1000 != 0 {
+		mode |= fs.ModeSticky
+	}
+	return mode
+}
+
+func (f *FS) readInode(num uint32) (*inode, error) {
+	if num == 0 {
+		return nil, fmt.Errorf("invalid inode 0")
+	}
+	group := int64((num - 1) / f.inodesPerGroup)
+	index := int64((num - 1) % f.inodesPerGroup)
+
+	desc := make([]byte, f.descSize)
+	if _, err := f.r.ReadAt(desc, f.descTableStart+group*f.descSize); err != nil {
+		return nil, fmt.Errorf("error reading group descriptor %d: %w", group, err)
+	}
+	inodeTable := int64(binary.LittleEndian.Uint32(desc[8:]))
+	if f.descSize >= 64 {
+		inodeTable |= int64(binary.LittleEndian.Uint32(desc[0x28:])) << 32
+	}
+
+	data := make([]byte, 128)
+	if _, err := f.r.ReadAt(data, inodeTable*f.blockSize+index*f.inodeSize); err != nil {
+		return nil, fmt.Errorf("error reading inode %d: %w", num, err)
+	}
+
+	return &inode{
+		num:   num,
+		mode:  binary.LittleEndian.Uint16(data[0:]),
+		size:  int64(binary.LittleEndian.Uint32(data[4:])) | int64(binary.LittleEndian.Uint32(data[0x6C:]))<<32,
+		mtime: time.Unix(int64(binary.LittleEndian.Uint32(data[0x10:])), 0),
+		flags: binary.LittleEndian.Uint32(data[0x20:]),
+		block: data[0x28:0x64],
+	}, nil
+}
+
+// extent maps a range of logical file blocks to physical blocks. A physical block of 0 is a hole.
+type extent struct {
+	logical  int64
+	physical int64
+	length   int64
+}
+
+// extents returns the block mapping of the inode, sorted by logical block.
+func (f *FS) extents(ino *inode) ([]extent, error) {
+	if ino.flags&flagExtents != 0 {
+		var res []extent
+		if err := f.walkExtentTree(ino.block, 0, &res); err != nil {
+			return nil, fmt.Errorf("inode %d: %w", ino.num, err)
+		}
+		return res, nil
+	}
+	return f.blockMap(ino)
+}
+
+func (f *FS) walkExtentTree(node []byte, depth int, res *[]extent) error {
+	if depth > 5 {
+		return fmt.Errorf("extent tree too deep")
+	}
+	if len(node) < 12 || binary.LittleEndian.Uint16(node[0:]) != extentMagic {
+		return fmt.Errorf("invalid extent header")
+	}
+	entries := int(binary.LittleEndian.Uint16(node[2:]))
+	treeDepth := binary.LittleEndian.Uint16(node[6:])
+	if 12+entries*12 > len(node) {
+		return fmt.Errorf("invalid extent entry count %d", entries)
+	}
+
+	for i := 0; i < entries; i++ {
+		entry := node[12+i*12 : 24+i*12]
+		if treeDepth == 0 {
+			length := int64(binary.LittleEndian.Uint16(entry[4:]))
+			physical := int64(binary.LittleEndian.Uint16(entry[6:]))<<32 | int64(binary.LittleEndian.Uint32(entry[8:]))
+			if length > 32768 {
+				// Uninitialized extents read as zeros.
+				length -= 32768
+				physical = 0
+			}
+			*res = append(*res, extent{
+				logical:  int64(binary.LittleEndian.Uint32(entry[0:])),
+				physical: physical,
+				length:   length,
+			})
+			continue
+		}
+
+		child := int64(binary.LittleEndian.Uint16(entry[8:]))<<32 | int64(binary.LittleEndian.Uint32(entry[4:]))
+		block := make([]byte, f.blockSize)
+		if _, err := f.r.ReadAt(block, child*f.blockSize); err != nil {
+			return fmt.Errorf("error reading extent block %d: %w", child, err)
+		}
+		if err := f.walkExtentTree(block, depth+1, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blockMap returns the mapping of an inode using direct and indirect block pointers (ext2/ext3).
+func (f *FS) blockMap(ino *inode) ([]extent, error) {
+	var (
+		res     []extent
+		logical int64
+		nblocks = (ino.size + f.blockSize - 1) / f.blockSize
+	)
+	add := func(physical int64) {
+		if n := len(res); n > 0 && physical != 0 && res[n-1].physical+res[n-1].length == physical && res[n-1].logical+res[n-1].length == logical {
+			res[n-1].length++
+		} else if physical != 0 {
+			res = append(res, extent{logical: logical, physical: physical, length: 1})
+		}
+		logical++
+	}
+
+	var walk func(block int64, level int) error
+	walk = func(block int64, level int) error {
+		perBlock := f.blockSize / 4
+		if block == 0 {
+			span := int64(1)
+			for i := 0; i < level; i++ {
+				span *= perBlock
+			}
+			logical += span
+			return nil
+		}
+		if level == 0 {
+			add(block)
+			return nil
+		}
+
+		data := make([]byte, f.blockSize)
+		if _, err := f.r.ReadAt(data, block*f.blockSize); err != nil {
+			return fmt.Errorf("error reading indirect block %d: %w", block, err)
+		}
+		for i := int64(0); i < perBlock && logical < nblocks; i++ {
+			if err := walk(int64(binary.LittleEndian.Uint32(data[i*4:])), level-1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for i := 0; i < 15 && logical < nblocks; i++ {
+		level := 0
+		if i >= 12 {
+			level = i - 11
+		}
+		if err := walk(int64(binary.LittleEndian.Uint32(ino.block[i*4:])), level); err != nil {
+			return nil, fmt.Errorf("inode %d: %w", ino.num, err)
+		}
+	}
+	return res, nil
+}
+
+// inodeReader reads the contents of an inode.
+type inodeReader struct {
+	f       *FS
+	size    int64
+	extents []extent
+	inline  []byte
+}
+
+func (f *FS) newInodeReader(ino *inode) (*inodeReader, error) {
+	if ino.flags&flagInlineData != 0 {
+		if ino.size > int64(len(ino.block)) {
+			return nil, fmt.Errorf("inode %d: inline data stored in extended attributes is not supported", ino.num)
+		}
+		return &inodeReader{f: f, size: ino.size, inline: ino.block[:ino.size]}, nil
+	}
+
+	extents, err := f.extents(ino)
+	if err != nil {
+		return nil, err
+	}
+	return &inodeReader{f: f, size: ino.size, extents: extents}, nil
+}
+
+func (r *inodeReader) ReadAt(p []byte, off int64) (int, error) {
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	if r.inline != nil {
+		n := copy(p, r.inline[off:])
+		if n < len(p) {
+			return n, io.EOF
+		}
+		return n, nil
+	}
+
+	var (
+		n   int
+		err error
+	)
+	if remaining := r.size - off; int64(len(p)) > remaining {
+		p = p[:remaining]
+		err = io.EOF
+	}
+
+	for len(p) > 0 {
+		logical := off / r.f.blockSize
+		inBlock := off % r.f.blockSize
+		chunk := r.f.blockSize - inBlock
+		if chunk > int64(len(p)) {
+			chunk = int64(len(p))
+		}
+
+		physical := r.physical(logical)
+		if physical == 0 {
+			clear(p[:chunk])
+		} else if _, rerr := r.f.r.ReadAt(p[:chunk], physical*r.f.blockSize+inBlock); rerr != nil {
+			return n, fmt.Errorf("error reading block %d: %w", physical, rerr)
+		}
+		n += int(chunk)
+		off += chunk
+		p = p[chunk:]
+	}
+	return n, err
+}
+
+// physical returns the physical block of the given logical block or 0 for holes.
+func (r *inodeReader) physical(logical int64) int64 {
+	lo, hi := 0, len(r.extents)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		e := r.extents[mid]
+		switch {
+		case logical < e.logical:
+			hi = mid
+		case logical >= e.logical+e.length:
+			lo = mid + 1
+		default:
+			if e.physical == 0 {
+				return 0
+			}
+			return e.physical + logical - e.logical
+		}
+	}
+	return 0
+}
+
+func (f *FS) readAll(ino *inode) ([]byte, error) {
+	r, err := f.newInodeReader(ino)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, ino.size)
+	if _, err := r.ReadAt(data, 0); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return data, nil
+}
+
+// dirEntry is an entry of a directory.
+type dirEntry struct {
+	name  string
+	inode uint32
+}
+
+func (f *FS) readDir(ino *inode) ([]dirEntry, error) {
+	data, err := f.readAll(ino)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []dirEntry
+	if ino.flags&flagInlineData != 0 {
+		// Inline directories start with the parent inode number instead of '.' and '..' entries.
+		if len(data) < 4 {
+			return nil, nil
+		}
+		data = data[4:]
+	}
+	for len(data) >= 8 {
+		num := binary.LittleEndian.Uint32(data[0:])
+		recLen := int(binary.LittleEndian.Uint16(data[4:]))
+		nameLen := int(data[6])
+		if f.incompat&incompatFileType == 0 {
+			nameLen = int(binary.LittleEndian.Uint16(data[6:]))
+		}
+		if recLen < 8 || recLen > len(data) || 8+nameLen > recLen {
+			return nil, fmt.Errorf("inode %d: invalid directory entry", ino.num)
+		}
+
+		name := string(data[8 : 8+nameLen])
+		if num != 0 && name != "." && name != ".." {
+			res = append(res, dirEntry{name: name, inode: num})
+		}
+		data = data[recLen:]
+	}
+	return res, nil
+}
+
+func (f *FS) readLink(ino *inode) (string, error) {
+	// Fast symlinks store their target in the block pointers.
+	if ino.flags&(flagExtents|flagInlineData) == 0 && ino.size < int64(len(ino.block)) {
+		return string(ino.block[:ino.size]), nil
+	}
+	data, err := f.readAll(ino)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// inodes implements inodefs.Inodes.
+type inodes struct {
+	f *FS
+}
+
+func (n inodes) Root() (*inode, error) {
+	return n.f.readInode(rootInode)
+}
+
+func (n inodes) Lookup(dir *inode, name string) (*inode, error) {
+	entries, err := n.f.readDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.name == name {
+			return n.f.readInode(entry.inode)
+		}
+	}
+	return nil, fs.ErrNotExist
+}
+
+func (n inodes) ReadDir(dir *inode) ([]inodefs.Entry[*inode], error) {
+	entries, err := n.f.readDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]inodefs.Entry[*inode], 0, len(entries))
+	for _, entry := range entries {
+		ino, err := n.f.readInode(entry.inode)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, inodefs.Entry[*inode]{Name: entry.name, Inode: ino})
+	}
+	return res, nil
+}
+
+func (n inodes) ReadLink(ino *inode) (string, error) {
+	return n.f.readLink(ino)
+}
+
+func (n inodes) Open(ino *inode) (io.ReaderAt, error) {
+	return n.f.newInodeReader(ino)
+}
+
+// Open implements fs.FS. Symbolic links are followed, also if they point outside of name's directory,
+// where absolute targets are resolved relative to the root of the file system.
+func (f *FS) Open(name string) (fs.File, error) {
+	return inodefs.Open[*inode](inodes{f}, name)
+}
+
+// ReadFile implements fs.ReadFileFS.
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	return inodefs.ReadFile[*inode](inodes{f}, name)
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return inodefs.ReadDir[*inode](inodes{f}, name)
+}
+
+// ReadLink returns the target of the symbolic link name.
+func (f *FS) ReadLink(name string) (string, error) {
+	return inodefs.ReadLink[*inode](inodes{f}, name)
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ext4_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing/fstest"
+
+	. "github.com/ironcore-dev/ironcore-image/fs/ext4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FS", func() {
+	var srcDir string
+
+	BeforeEach(func() {
+		if _, err := exec.LookPath("mkfs.ext4"); err != nil {
+			Skip("mkfs.ext4 not found")
+		}
+
+		srcDir = GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(srcDir, "var", "lib", "dpkg"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(srcDir, "var", "lib", "dpkg", "status"), []byte("Package: foo\n"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(srcDir, "small"), []byte("x"), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(srcDir, "big"), bytes.Repeat([]byte("0123456789"), 100_000), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(srcDir, "many"), 0755)).To(Succeed())
+		for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+			Expect(os.WriteFile(filepath.Join(srcDir, "many", name), []byte(name), 0644)).To(Succeed())
+		}
+	})
+
+	DescribeTable("should read file systems created by mkfs",
+		func(args ...string) {
+			img := filepath.Join(GinkgoT().TempDir(), "fs.img")
+			cmd := exec.Command("mkfs.ext4", append(append([]string{"-q", "-d", srcDir}, args...), img, "16M")...)
+			out, err := cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(out))
+
+			f, err := os.Open(img)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(f.Close)
+			Expect(IsExt4(f)).To(BeTrue())
+
+			fsys, err := New(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fstest.TestFS(fsys, "var/lib/dpkg/status", "small", "big", "many/h")).To(Succeed())
+
+			Expect(fs.ReadFile(fsys, "var/lib/dpkg/status")).To(Equal([]byte("Package: foo\n")))
+			Expect(fs.ReadFile(fsys, "big")).To(Equal(bytes.Repeat([]byte("0123456789"), 100_000)))
+		},
+		Entry("ext4", "-t", "ext4"),
+		Entry("ext4 with 1k blocks", "-t", "ext4", "-b", "1024"),
+		Entry("ext4 with inline data", "-t", "ext4", "-O", "inline_data"),
+		Entry("ext2", "-t", "ext2", "-b", "1024"),
+	)
+
+	It("should follow symbolic links", func() {
+		Expect(os.Symlink("../../var/lib/dpkg", filepath.Join(srcDir, "many", "rel"))).To(Succeed())
+		Expect(os.Symlink("/var/lib", filepath.Join(srcDir, "abs"))).To(Succeed())
+
+		img := filepath.Join(GinkgoT().TempDir(), "fs.img")
+		out, err := exec.Command("mkfs.ext4", "-q", "-d", srcDir, img, "16M").CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(out))
+
+		f, err := os.Open(img)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(f.Close)
+
+		fsys, err := New(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fs.ReadFile(fsys, "many/rel/status")).To(Equal([]byte("Package: foo\n")))
+		Expect(fs.ReadFile(fsys, "abs/dpkg/status")).To(Equal([]byte("Package: foo\n")))
+		Expect(fsys.ReadLink("abs")).To(Equal("/var/lib"))
+	})
+
+	It("should reject other images", func() {
+		_, err := New(bytes.NewReader(make([]byte, 4096)))
+		Expect(err).To(MatchError(ErrNotExt4))
+	})
+
+	It("should reject invalid block sizes", func() {
+		img := make([]byte, 4096)
+		binary.LittleEndian.PutUint16(img[1024+56:], 0xEF53)
+		binary.LittleEndian.PutUint32(img[1024+24:], 54)
+		binary.LittleEndian.PutUint32(img[1024+40:], 8)
+
+		_, err := New(bytes.NewReader(img))
+		Expect(err).To(MatchError(ContainSubstring("invalid superblock")))
+	})
+})
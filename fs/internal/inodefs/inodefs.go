@@ -0,0 +1,256 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package inodefs implements path resolution and the fs.FS interfaces for read-only file system images
+// on top of access to their inodes.
+package inodefs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxSymlinks is the maximum number of symbolic links followed while resolving a path, like on Linux.
+const maxSymlinks = 40
+
+// Inode is an inode of a file system image.
+type Inode interface {
+	Mode() fs.FileMode
+	Size() int64
+	ModTime() time.Time
+	Sys() any
+}
+
+// Entry is an entry of a directory.
+type Entry[I Inode] struct {
+	Name  string
+	Inode I
+}
+
+// Inodes is access to the inodes of a file system image.
+type Inodes[I Inode] interface {
+	// Root returns the root directory.
+	Root() (I, error)
+	// Lookup returns the inode of the entry name of the directory dir or fs.ErrNotExist.
+	Lookup(dir I, name string) (I, error)
+	// ReadDir returns the entries of the directory dir, excluding "." and "..".
+	ReadDir(dir I) ([]Entry[I], error)
+	// ReadLink returns the target of the symbolic link ino.
+	ReadLink(ino I) (string, error)
+	// Open returns the content of the regular file ino.
+	Open(ino I) (io.ReaderAt, error)
+}
+
+// Lookup resolves name to its inode. If follow is set, a final symbolic link is followed.
+// Symbolic links are resolved like in a chroot, i.e. absolute targets are resolved relative to the
+// root and ".." of the root is the root itself.
+func Lookup[I Inode](inodes Inodes[I], name string, follow bool) (I, error) {
+	return lookupFrom(inodes, nil, name, follow, 0)
+}
+
+// lookupFrom resolves name starting at the directory stack dirs.
+func lookupFrom[I Inode](inodes Inodes[I], dirs []I, name string, follow bool, links int) (I, error) {
+	var zero I
+	if len(dirs) == 0 || strings.HasPrefix(name, "/") {
+		root, err := inodes.Root()
+		if err != nil {
+			return zero, err
+		}
+		dirs = []I{root}
+	}
+
+	parts := strings.Split(strings.Trim(name, "/"), "/")
+	for i, part := range parts {
+		cur := dirs[len(dirs)-1]
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if len(dirs) > 1 {
+				dirs = dirs[:len(dirs)-1]
+			}
+			continue
+		}
+
+		if !cur.Mode().IsDir() {
+			return zero, fs.ErrNotExist
+		}
+		next, err := inodes.Lookup(cur, part)
+		if err != nil {
+			return zero, err
+		}
+
+		last := i == len(parts)-1
+		if next.Mode()&fs.ModeSymlink != 0 && (!last || follow) {
+			if links >= maxSymlinks {
+				return zero, fmt.Errorf("too many levels of symbolic links")
+			}
+			target, err := inodes.ReadLink(next)
+			if err != nil {
+				return zero, err
+			}
+			rest := path.Join(append([]string{target}, parts[i+1:]...)...)
+			return lookupFrom(inodes, dirs, rest, follow, links+1)
+		}
+		dirs = append(dirs, next)
+	}
+	return dirs[len(dirs)-1], nil
+}
+
+// Open implements fs.FS. Symbolic links are followed, where absolute targets are resolved
+// relative to the root of the file system.
+func Open[I Inode](inodes Inodes[I], name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	ino, err := Lookup(inodes, name, true)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	info := &fileInfo[I]{name: path.Base(name), ino: ino}
+	switch {
+	case ino.Mode().IsDir():
+		return &dir[I]{inodes: inodes, info: info}, nil
+	case ino.Mode().IsRegular():
+		r, err := inodes.Open(ino)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		return &file{info: info, r: io.NewSectionReader(r, 0, ino.Size())}, nil
+	default:
+		return &file{info: info, r: io.NewSectionReader(bytes.NewReader(nil), 0, 0)}, nil
+	}
+}
+
+// ReadFile implements fs.ReadFileFS.
+func ReadFile[I Inode](inodes Inodes[I], name string) ([]byte, error) {
+	file, err := Open(inodes, name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	return io.ReadAll(file)
+}
+
+// ReadDir implements fs.ReadDirFS.
+func ReadDir[I Inode](inodes Inodes[I], name string) ([]fs.DirEntry, error) {
+	file, err := Open(inodes, name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	d, ok := file.(*dir[I])
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	entries, err := d.ReadDir(-1)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, nil
+}
+
+// ReadLink returns the target of the symbolic link name.
+func ReadLink[I Inode](inodes Inodes[I], name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	ino, err := Lookup(inodes, name, false)
+	if err != nil {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: err}
+	}
+	if ino.Mode()&fs.ModeSymlink == 0 {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	target, err := inodes.ReadLink(ino)
+	if err != nil {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: err}
+	}
+	return target, nil
+}
+
+// Lstat returns the file info of name without following a final symbolic link.
+func Lstat[I Inode](inodes Inodes[I], name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "lstat", Path: name, Err: fs.ErrInvalid}
+	}
+	ino, err := Lookup(inodes, name, false)
+	if err != nil {
+		return nil, &fs.PathError{Op: "lstat", Path: name, Err: err}
+	}
+	return &fileInfo[I]{name: path.Base(name), ino: ino}, nil
+}
+
+type fileInfo[I Inode] struct {
+	name string
+	ino  I
+}
+
+func (i *fileInfo[I]) Name() string       { return i.name }
+func (i *fileInfo[I]) Size() int64        { return i.ino.Size() }
+func (i *fileInfo[I]) Mode() fs.FileMode  { return i.ino.Mode() }
+func (i *fileInfo[I]) ModTime() time.Time { return i.ino.ModTime() }
+func (i *fileInfo[I]) IsDir() bool        { return i.ino.Mode().IsDir() }
+func (i *fileInfo[I]) Sys() any           { return i.ino.Sys() }
+
+type file struct {
+	info fs.FileInfo
+	r    *io.SectionReader
+}
+
+func (f *file) Stat() (fs.FileInfo, error)                { return f.info, nil }
+func (f *file) Read(p []byte) (int, error)                { return f.r.Read(p) }
+func (f *file) ReadAt(p []byte, off int64) (int, error)   { return f.r.ReadAt(p, off) }
+func (f *file) Seek(off int64, whence int) (int64, error) { return f.r.Seek(off, whence) }
+func (f *file) Close() error                              { return nil }
+
+type dir[I Inode] struct {
+	inodes  Inodes[I]
+	info    *fileInfo[I]
+	entries []fs.DirEntry
+	read    bool
+}
+
+func (d *dir[I]) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *dir[I]) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+func (d *dir[I]) Close() error { return nil }
+
+func (d *dir[I]) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.read {
+		entries, err := d.inodes.ReadDir(d.info.ino)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			d.entries = append(d.entries, fs.FileInfoToDirEntry(&fileInfo[I]{name: entry.Name, ino: entry.Inode}))
+		}
+		d.read = true
+	}
+
+	if n <= 0 {
+		res := d.entries
+		d.entries = nil
+		return res, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(d.entries) {
+		n = len(d.entries)
+	}
+	res := d.entries[:n]
+	d.entries = d.entries[n:]
+	return res, nil
+}
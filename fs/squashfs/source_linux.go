@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package squashfs
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// hostStat returns the ownership and identity of a file of the host.
+func hostStat(info fs.FileInfo) fileStat {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileStat{nlink: 1}
+	}
+	return fileStat{
+		uid:   st.Uid,
+		gid:   st.Gid,
+		dev:   st.Dev,
+		ino:   st.Ino,
+		nlink: uint64(st.Nlink),
+		major: unix.Major(st.Rdev),
+		minor: unix.Minor(st.Rdev),
+	}
+}
+
+// readHostXattrs returns the extended attributes of the file of the host at path.
+func readHostXattrs(path string) (map[string][]byte, error) {
+	size, err := unix.Llistxattr(path, nil)
+	if err != nil {
+		if errors.Is(err, unix.ENOTSUP) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if size == 0 {
+		return nil, nil
+	}
+	buf := make([]byte, size)
+	if size, err = unix.Llistxattr(path, buf); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string][]byte)
+	for _, name := range bytes.Split(buf[:size], []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		value, err := lgetxattr(path, string(name))
+		if err != nil {
+			if errors.Is(err, unix.ENODATA) {
+				continue
+			}
+			return nil, err
+		}
+		res[string(name)] = value
+	}
+	return res, nil
+}
+
+func lgetxattr(path, name string) ([]byte, error) {
+	size, err := unix.Lgetxattr(path, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	value := make([]byte, size)
+	if size, err = unix.Lgetxattr(path, name, value); err != nil {
+		return nil, err
+	}
+	return value[:size], nil
+}
@@ -0,0 +1,20 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package squashfs
+
+import (
+	"io/fs"
+)
+
+// hostStat does not determine ownership on this platform, all files are owned by root.
+func hostStat(info fs.FileInfo) fileStat {
+	return fileStat{nlink: 1}
+}
+
+// readHostXattrs does not read extended attributes on this platform.
+func readHostXattrs(path string) (map[string][]byte, error) {
+	return nil, nil
+}
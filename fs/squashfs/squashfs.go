@@ -0,0 +1,1006 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package squashfs implements access to squashfs (version 4) file system images.
+//
+// Images are read via New and written from a directory tree via WriteDir.
+// Supported compressions are gzip and zstd.
+package squashfs
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sync"
+	"time"
+
+	"github.com/ironcore-dev/ironcore-image/fs/internal/inodefs"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	magic = 0x73717368
+
+	superblockSize = 96
+
+	metadataBlockSize = 8192
+
+	noFragment = 0xFFFFFFFF
+
+	noXattr = 0xFFFFFFFF
+
+	invalidTable = 0xFFFFFFFFFFFFFFFF
+)
+
+// Compression is a squashfs compression algorithm.
+type Compression uint16
+
+const (
+	CompressionGzip Compression = 1
+	CompressionLZMA Compression = 2
+	CompressionLZO  Compression = 3
+	CompressionXZ   Compression = 4
+	CompressionLZ4  Compression = 5
+	CompressionZstd Compression = 6
+)
+
+func (c Compression) String() string {
+	switch c {
+	case CompressionGzip:
+		return "gzip"
+	case CompressionLZMA:
+		return "lzma"
+	case CompressionLZO:
+		return "lzo"
+	case CompressionXZ:
+		return "xz"
+	case CompressionLZ4:
+		return "lz4"
+	case CompressionZstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("unknown (%d)", uint16(c))
+	}
+}
+
+// Extended attribute types, determining the prefix of their names.
+const (
+	xattrUser     = 0
+	xattrTrusted  = 1
+	xattrSecurity = 2
+
+	// xattrValueOOL marks values stored out of line, i.e. as reference to another value.
+	xattrValueOOL = 0x100
+)
+
+var xattrPrefixes = map[uint16]string{
+	xattrUser:     "user.",
+	xattrTrusted:  "trusted.",
+	xattrSecurity: "security.",
+}
+
+// Inode types.
+const (
+	typeDir        = 1
+	typeFile       = 2
+	typeSymlink    = 3
+	typeBlockDev   = 4
+	typeCharDev    = 5
+	typeFifo       = 6
+	typeSocket     = 7
+	typeExtDir     = 8
+	typeExtFile    = 9
+	typeExtSymlink = 10
+	typeExtBlock   = 11
+	typeExtChar    = 12
+	typeExtFifo    = 13
+	typeExtSocket  = 14
+)
+
+var (
+	// ErrNotSquashFS is returned by New if the image is not a squashfs file system.
+	ErrNotSquashFS = errors.New("not a squashfs file system")
+	// ErrUnsupportedCompression is returned by New if the compression of the image is not supported.
+	ErrUnsupportedCompression = errors.New("unsupported compression")
+)
+
+// IsSquashFS reports whether r starts with a squashfs superblock.
+func IsSquashFS(r io.ReaderAt) bool {
+	var buf [4]byte
+	if _, err := r.ReadAt(buf[:], 0); err != nil {
+		return false
+	}
+	return binary.LittleEndian.Uint32(buf[:]) == magic
+}
+
+// FS is a read-only squashfs file system. It implements fs.FS, fs.ReadDirFS and fs.ReadFileFS.
+type FS struct {
+	r io.ReaderAt
+
+	blockSize          uint32
+	compression        Compression
+	rootInode          uint64
+	inodeTableStart    int64
+	dirTableStart      int64
+	fragmentTableStart int64
+	fragmentCount      uint32
+	idTableStart       int64
+	idCount            uint16
+	xattrIDTableStart  uint64
+
+	decompress func(dst, src []byte) ([]byte, error)
+
+	fragmentsOnce sync.Once
+	fragments     []fragment
+	fragmentsErr  error
+
+	idsOnce sync.Once
+	ids     []uint32
+	idsErr  error
+
+	xattrsOnce      sync.Once
+	xattrTableStart int64
+	xattrIDs        []xattrID
+	xattrsErr       error
+}
+
+type fragment struct {
+	start int64
+	size  uint32
+}
+
+// xattrID locates the extended attributes of an inode in the xattr table.
+type xattrID struct {
+	ref   uint64
+	count uint32
+}
+
+// New opens the squashfs image r.
+func New(r io.ReaderAt) (*FS, error) {
+	sb := make([]byte, superblockSize)
+	if _, err := r.ReadAt(sb, 0); err != nil {
+		return nil, fmt.Errorf("error reading superblock: %w", err)
+	}
+	if binary.LittleEndian.Uint32(sb[0:]) != magic {
+		return nil, ErrNotSquashFS
+	}
+	if major := binary.LittleEndian.Uint16(sb[28:]); major != 4 {
+		return nil, fmt.Errorf("unsupported squashfs version %d", major)
+	}
+
+	f := &FS{
+		r:                  r,
+		blockSize:          binary.LittleEndian.Uint32(sb[12:]),
+		fragmentCount:      binary.LittleEndian.Uint32(sb[16:]),
+		compression:        Compression(binary.LittleEndian.Uint16(sb[20:])),
+		rootInode:          binary.LittleEndian.Uint64(sb[32:]),
+		inodeTableStart:    int64(binary.LittleEndian.Uint64(sb[64:])),
+		dirTableStart:      int64(binary.LittleEndian.Uint64(sb[72:])),
+		fragmentTableStart: int64(binary.LittleEndian.Uint64(sb[80:])),
+		idTableStart:       int64(binary.LittleEndian.Uint64(sb[48:])),
+		idCount:            binary.LittleEndian.Uint16(sb[26:]),
+		xattrIDTableStart:  binary.LittleEndian.Uint64(sb[56:]),
+	}
+
+	if f.blockSize < minBlockSize || f.blockSize > maxBlockSize || f.blockSize&(f.blockSize-1) != 0 {
+		return nil, fmt.Errorf("invalid block size %d", f.blockSize)
+	}
+
+	switch f.compression {
+	case CompressionGzip:
+		f.decompress = decompressZlib
+	case CompressionZstd:
+		dec, err := zstd.NewReader(nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating zstd decoder: %w", err)
+		}
+		f.decompress = func(dst, src []byte) ([]byte, error) {
+			return dec.DecodeAll(src, dst[:0])
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCompression, f.compression)
+	}
+	return f, nil
+}
+
+// Compression returns the compression of the file system.
+func (f *FS) Compression() Compression {
+	return f.compression
+}
+
+func decompressZlib(dst, src []byte) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = zr.Close() }()
+
+	buf := bytes.NewBuffer(dst[:0])
+	if _, err := io.Copy(buf, zr); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readMetadataBlock reads the metadata block at off and returns its uncompressed data
+// as well as the offset of the next metadata block.
+func (f *FS) readMetadataBlock(off int64) ([]byte, int64, error) {
+	var header [2]byte
+	if _, err := f.r.ReadAt(header[:], off); err != nil {
+		return nil, 0, fmt.Errorf("error reading metadata block header at %d: %w", off, err)
+	}
+	h := binary.LittleEndian.Uint16(header[:])
+	size := int64(h & 0x7FFF)
+
+	data := make([]byte, size)
+	if _, err := f.r.ReadAt(data, off+2); err != nil {
+		return nil, 0, fmt.Errorf("error reading metadata block at %d: %w", off, err)
+	}
+	next := off + 2 + size
+	if h&0x8000 != 0 {
+		return data, next, nil
+	}
+
+	data, err := f.decompress(make([]byte, 0, metadataBlockSize), data)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error decompressing metadata block at %d: %w", off, err)
+	}
+	return data, next, nil
+}
+
+// metadataReader reads a stream of metadata starting at a block and an offset within its uncompressed data.
+type metadataReader struct {
+	f    *FS
+	next int64
+	buf  []byte
+}
+
+func (f *FS) newMetadataReader(block int64, offset int) (*metadataReader, error) {
+	r := &metadataReader{f: f, next: block}
+	if err := r.fill(); err != nil {
+		return nil, err
+	}
+	if offset > len(r.buf) {
+		return nil, fmt.Errorf("invalid metadata offset %d", offset)
+	}
+	r.buf = r.buf[offset:]
+	return r, nil
+}
+
+func (r *metadataReader) fill() error {
+	data, next, err := r.f.readMetadataBlock(r.next)
+	if err != nil {
+		return err
+	}
+	r.buf, r.next = data, next
+	return nil
+}
+
+func (r *metadataReader) Read(p []byte) (int, error) {
+	if len(r.buf) == 0 {
+		if err := r.fill(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *metadataReader) read(n int) ([]byte, error) {
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (r *metadataReader) readUint32() (uint32, error) {
+	data, err := r.read(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(data), nil
+}
+
+// inode is a parsed inode.
+type inode struct {
+	typ   uint16
+	perm  uint16
+	uid   uint32
+	gid   uint32
+	mtime time.Time
+	num   uint32
+	nlink uint32
+	xattr uint32
+
+	// rdev is the device number of device inodes.
+	rdev uint32
+
+	// size is the file size of regular files, the listing size of directories or the target size of symlinks.
+	size int64
+
+	// Regular files.
+	blocksStart   int64
+	fragment      uint32
+	fragmentOff   uint32
+	blockSizes    []uint32
+	symlinkTarget string
+
+	// Directories.
+	dirBlock  uint32
+	dirOffset uint16
+}
+
+func (i *inode) isDir() bool     { return i.typ == typeDir || i.typ == typeExtDir }
+func (i *inode) isFile() bool    { return i.typ == typeFile || i.typ == typeExtFile }
+func (i *inode) isSymlink() bool { return i.typ == typeSymlink || i.typ == typeExtSymlink }
+
+func (i *inode) Mode() fs.FileMode {
+	mode := fs.FileMode(i.perm & 0o777)
+	switch i.typ {
+	case typeDir, typeExtDir:
+		mode |= fs.ModeDir
+	case typeSymlink, typeExtSymlink:
+		mode |= fs.ModeSymlink
+	case typeBlockDev, typeExtBlock:
+		mode |= fs.ModeDevice
+	case typeCharDev, typeExtChar:
+		mode |= fs.ModeDevice | fs.ModeCharDevice
+	case typeFifo, typeExtFifo:
+		mode |= fs.ModeNamedPipe
+	case typeSocket, typeExtSocket:
+		mode |= fs.ModeSocket
+	}
+	if i.perm&0o4000 != 0 {
+		mode |= fs.ModeSetuid
+	}
+	if i.perm&0o2000 != 0 {
+		mode |= fs.ModeSetgid
+	}
+	if i.perm&0o1000 != 0 {
+		mode |= fs.ModeSticky
+	}
+	return mode
+}
+
+func (i *inode) Size() int64 {
+	if i.isFile() || i.isSymlink() {
+		return i.size
+	}
+	return 0
+}
+
+func (i *inode) ModTime() time.Time { return i.mtime }
+
+// Sys returns the *Stat of the inode.
+func (i *inode) Sys() any {
+	major, minor := decodeDev(i.rdev)
+	return &Stat{UID: i.uid, GID: i.gid, Ino: uint64(i.num), Nlink: i.nlink, Major: major, Minor: minor}
+}
+
+// readInode reads the inode at the given inode reference (block << 16 | offset).
+func (f *FS) readInode(ref uint64) (*inode, error) {
+	r, err := f.newMetadataReader(f.inodeTableStart+int64(ref>>16), int(ref&0xFFFF))
+	if err != nil {
+		return nil, fmt.Errorf("error reading inode %x: %w", ref, err)
+	}
+	ino, err := f.parseInode(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading inode %x: %w", ref, err)
+	}
+	return ino, nil
+}
+
+func (f *FS) parseInode(r *metadataReader) (*inode, error) {
+	header, err := r.read(16)
+	if err != nil {
+		return nil, err
+	}
+	ino := &inode{
+		typ:   binary.LittleEndian.Uint16(header[0:]),
+		perm:  binary.LittleEndian.Uint16(header[2:]),
+		mtime: time.Unix(int64(binary.LittleEndian.Uint32(header[8:])), 0),
+		num:   binary.LittleEndian.Uint32(header[12:]),
+		nlink: 1,
+		xattr: noXattr,
+	}
+	if ino.uid, err = f.id(binary.LittleEndian.Uint16(header[4:])); err != nil {
+		return nil, err
+	}
+	if ino.gid, err = f.id(binary.LittleEndian.Uint16(header[6:])); err != nil {
+		return nil, err
+	}
+
+	switch ino.typ {
+	case typeDir:
+		data, err := r.read(16)
+		if err != nil {
+			return nil, err
+		}
+		ino.dirBlock = binary.LittleEndian.Uint32(data[0:])
+		ino.nlink = binary.LittleEndian.Uint32(data[4:])
+		ino.size = int64(binary.LittleEndian.Uint16(data[8:]))
+		ino.dirOffset = binary.LittleEndian.Uint16(data[10:])
+	case typeExtDir:
+		data, err := r.read(24)
+		if err != nil {
+			return nil, err
+		}
+		ino.nlink = binary.LittleEndian.Uint32(data[0:])
+		ino.size = int64(binary.LittleEndian.Uint32(data[4:]))
+		ino.dirBlock = binary.LittleEndian.Uint32(data[8:])
+		ino.dirOffset = binary.LittleEndian.Uint16(data[18:])
+		ino.xattr = binary.LittleEndian.Uint32(data[20:])
+	case typeFile:
+		data, err := r.read(16)
+		if err != nil {
+			return nil, err
+		}
+		ino.blocksStart = int64(binary.LittleEndian.Uint32(data[0:]))
+		ino.fragment = binary.LittleEndian.Uint32(data[4:])
+		ino.fragmentOff = binary.LittleEndian.Uint32(data[8:])
+		ino.size = int64(binary.LittleEndian.Uint32(data[12:]))
+		if ino.blockSizes, err = f.readBlockSizes(r, ino); err != nil {
+			return nil, err
+		}
+	case typeExtFile:
+		data, err := r.read(40)
+		if err != nil {
+			return nil, err
+		}
+		ino.blocksStart = int64(binary.LittleEndian.Uint64(data[0:]))
+		ino.size = int64(binary.LittleEndian.Uint64(data[8:]))
+		ino.nlink = binary.LittleEndian.Uint32(data[24:])
+		ino.fragment = binary.LittleEndian.Uint32(data[28:])
+		ino.fragmentOff = binary.LittleEndian.Uint32(data[32:])
+		ino.xattr = binary.LittleEndian.Uint32(data[36:])
+		if ino.blockSizes, err = f.readBlockSizes(r, ino); err != nil {
+			return nil, err
+		}
+	case typeSymlink, typeExtSymlink:
+		data, err := r.read(8)
+		if err != nil {
+			return nil, err
+		}
+		ino.nlink = binary.LittleEndian.Uint32(data[0:])
+		ino.size = int64(binary.LittleEndian.Uint32(data[4:]))
+		if ino.size > 4096 {
+			return nil, fmt.Errorf("symlink target too long: %d", ino.size)
+		}
+		target, err := r.read(int(ino.size))
+		if err != nil {
+			return nil, err
+		}
+		ino.symlinkTarget = string(target)
+		if ino.typ == typeExtSymlink {
+			if ino.xattr, err = r.readUint32(); err != nil {
+				return nil, err
+			}
+		}
+	case typeBlockDev, typeCharDev, typeExtBlock, typeExtChar:
+		data, err := r.read(8)
+		if err != nil {
+			return nil, err
+		}
+		ino.nlink = binary.LittleEndian.Uint32(data[0:])
+		ino.rdev = binary.LittleEndian.Uint32(data[4:])
+		if ino.typ == typeExtBlock || ino.typ == typeExtChar {
+			if ino.xattr, err = r.readUint32(); err != nil {
+				return nil, err
+			}
+		}
+	case typeFifo, typeSocket, typeExtFifo, typeExtSocket:
+		if ino.nlink, err = r.readUint32(); err != nil {
+			return nil, err
+		}
+		if ino.typ == typeExtFifo || ino.typ == typeExtSocket {
+			if ino.xattr, err = r.readUint32(); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown inode type %d", ino.typ)
+	}
+	return ino, nil
+}
+
+func (f *FS) readBlockSizes(r *metadataReader, ino *inode) ([]uint32, error) {
+	count := ino.size / int64(f.blockSize)
+	if ino.fragment == noFragment && ino.size%int64(f.blockSize) != 0 {
+		count++
+	}
+	if count > 1<<24 {
+		return nil, fmt.Errorf("too many blocks: %d", count)
+	}
+
+	data, err := r.read(int(count) * 4)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]uint32, count)
+	for i := range res {
+		res[i] = binary.LittleEndian.Uint32(data[i*4:])
+	}
+	return res, nil
+}
+
+// readTable reads a table of size bytes stored in metadata blocks, whose locations are listed
+// in the index at start.
+func (f *FS) readTable(start, size int64) ([]byte, error) {
+	blocks := (size + metadataBlockSize - 1) / metadataBlockSize
+	ptrs := make([]byte, blocks*8)
+	if _, err := f.r.ReadAt(ptrs, start); err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	for i := int64(0); i < blocks; i++ {
+		block, _, err := f.readMetadataBlock(int64(binary.LittleEndian.Uint64(ptrs[i*8:])))
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, block...)
+	}
+	if int64(len(data)) < size {
+		return nil, fmt.Errorf("table too short")
+	}
+	return data, nil
+}
+
+func (f *FS) loadFragments() ([]fragment, error) {
+	f.fragmentsOnce.Do(func() {
+		if f.fragmentCount == 0 {
+			return
+		}
+
+		data, err := f.readTable(f.fragmentTableStart, int64(f.fragmentCount)*16)
+		if err != nil {
+			f.fragmentsErr = fmt.Errorf("error reading fragment table: %w", err)
+			return
+		}
+
+		f.fragments = make([]fragment, f.fragmentCount)
+		for i := range f.fragments {
+			entry := data[i*16:]
+			f.fragments[i] = fragment{
+				start: int64(binary.LittleEndian.Uint64(entry[0:])),
+				size:  binary.LittleEndian.Uint32(entry[8:]),
+			}
+		}
+	})
+	return f.fragments, f.fragmentsErr
+}
+
+// id returns the uid or gid with the given index in the id table.
+func (f *FS) id(index uint16) (uint32, error) {
+	f.idsOnce.Do(func() {
+		data, err := f.readTable(f.idTableStart, int64(f.idCount)*4)
+		if err != nil {
+			f.idsErr = fmt.Errorf("error reading id table: %w", err)
+			return
+		}
+
+		f.ids = make([]uint32, f.idCount)
+		for i := range f.ids {
+			f.ids[i] = binary.LittleEndian.Uint32(data[i*4:])
+		}
+	})
+	if f.idsErr != nil {
+		return 0, f.idsErr
+	}
+	if int(index) >= len(f.ids) {
+		return 0, fmt.Errorf("invalid id index %d", index)
+	}
+	return f.ids[index], nil
+}
+
+func (f *FS) loadXattrIDs() ([]xattrID, error) {
+	f.xattrsOnce.Do(func() {
+		if f.xattrIDTableStart == invalidTable {
+			return
+		}
+
+		header := make([]byte, 16)
+		if _, err := f.r.ReadAt(header, int64(f.xattrIDTableStart)); err != nil {
+			f.xattrsErr = fmt.Errorf("error reading xattr id table: %w", err)
+			return
+		}
+		f.xattrTableStart = int64(binary.LittleEndian.Uint64(header[0:]))
+		count := binary.LittleEndian.Uint32(header[8:])
+		if count > 1<<24 {
+			f.xattrsErr = fmt.Errorf("too many xattr ids: %d", count)
+			return
+		}
+
+		data, err := f.readTable(int64(f.xattrIDTableStart)+16, int64(count)*16)
+		if err != nil {
+			f.xattrsErr = fmt.Errorf("error reading xattr id table: %w", err)
+			return
+		}
+
+		f.xattrIDs = make([]xattrID, count)
+		for i := range f.xattrIDs {
+			entry := data[i*16:]
+			f.xattrIDs[i] = xattrID{
+				ref:   binary.LittleEndian.Uint64(entry[0:]),
+				count: binary.LittleEndian.Uint32(entry[8:]),
+			}
+		}
+	})
+	return f.xattrIDs, f.xattrsErr
+}
+
+// readXattrs reads the extended attributes of ino.
+func (f *FS) readXattrs(ino *inode) (map[string][]byte, error) {
+	res := make(map[string][]byte)
+	if ino.xattr == noXattr {
+		return res, nil
+	}
+
+	ids, err := f.loadXattrIDs()
+	if err != nil {
+		return nil, err
+	}
+	if int(ino.xattr) >= len(ids) {
+		return nil, fmt.Errorf("invalid xattr index %d", ino.xattr)
+	}
+	id := ids[ino.xattr]
+
+	r, err := f.newMetadataReader(f.xattrTableStart+int64(id.ref>>16), int(id.ref&0xFFFF))
+	if err != nil {
+		return nil, fmt.Errorf("error reading xattrs: %w", err)
+	}
+	for i := uint32(0); i < id.count; i++ {
+		key, err := r.read(4)
+		if err != nil {
+			return nil, fmt.Errorf("error reading xattr: %w", err)
+		}
+		typ := binary.LittleEndian.Uint16(key[0:])
+		name, err := r.read(int(binary.LittleEndian.Uint16(key[2:])))
+		if err != nil {
+			return nil, fmt.Errorf("error reading xattr name: %w", err)
+		}
+		prefix, ok := xattrPrefixes[typ&^xattrValueOOL]
+		if !ok {
+			return nil, fmt.Errorf("unknown xattr type %d", typ)
+		}
+
+		value, err := f.readXattrValue(r)
+		if err != nil {
+			return nil, err
+		}
+		if typ&xattrValueOOL != 0 {
+			if len(value) != 8 {
+				return nil, fmt.Errorf("invalid out of line xattr value")
+			}
+			ref := binary.LittleEndian.Uint64(value)
+			vr, err := f.newMetadataReader(f.xattrTableStart+int64(ref>>16), int(ref&0xFFFF))
+			if err != nil {
+				return nil, fmt.Errorf("error reading xattr value: %w", err)
+			}
+			if value, err = f.readXattrValue(vr); err != nil {
+				return nil, err
+			}
+		}
+		res[prefix+string(name)] = value
+	}
+	return res, nil
+}
+
+func (f *FS) readXattrValue(r *metadataReader) ([]byte, error) {
+	size, err := r.readUint32()
+	if err != nil {
+		return nil, fmt.Errorf("error reading xattr value: %w", err)
+	}
+	if size > 1<<16 {
+		return nil, fmt.Errorf("xattr value too large: %d", size)
+	}
+	value, err := r.read(int(size))
+	if err != nil {
+		return nil, fmt.Errorf("error reading xattr value: %w", err)
+	}
+	return value, nil
+}
+
+// readDataBlock reads a data or fragment block with the given on-disk size word.
+func (f *FS) readDataBlock(off int64, sizeWord uint32) ([]byte, error) {
+	size := sizeWord &^ (1 << 24)
+	if size == 0 {
+		return make([]byte, f.blockSize), nil
+	}
+	if size > f.blockSize*2 {
+		return nil, fmt.Errorf("invalid block size %d", size)
+	}
+
+	data := make([]byte, size)
+	if _, err := f.r.ReadAt(data, off); err != nil {
+		return nil, fmt.Errorf("error reading block at %d: %w", off, err)
+	}
+	if sizeWord&(1<<24) != 0 {
+		return data, nil
+	}
+	data, err := f.decompress(make([]byte, 0, f.blockSize), data)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing block at %d: %w", off, err)
+	}
+	return data, nil
+}
+
+// fileReader reads the contents of a regular file.
+type fileReader struct {
+	f       *FS
+	ino     *inode
+	offsets []int64
+
+	mu         sync.Mutex
+	cacheIndex int
+	cache      []byte
+}
+
+func (f *FS) newFileReader(ino *inode) *fileReader {
+	offsets := make([]int64, len(ino.blockSizes))
+	off := ino.blocksStart
+	for i, size := range ino.blockSizes {
+		offsets[i] = off
+		off += int64(size &^ (1 << 24))
+	}
+	return &fileReader{f: f, ino: ino, offsets: offsets, cacheIndex: -1}
+}
+
+// block returns the uncompressed data of the i-th block of the file, where the block after
+// the last full block is the tail stored in a fragment.
+func (r *fileReader) block(i int) ([]byte, error) {
+	if i == r.cacheIndex {
+		return r.cache, nil
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if i < len(r.ino.blockSizes) {
+		data, err = r.f.readDataBlock(r.offsets[i], r.ino.blockSizes[i])
+	} else {
+		data, err = r.fragmentData()
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.cacheIndex, r.cache = i, data
+	return data, nil
+}
+
+func (r *fileReader) fragmentData() ([]byte, error) {
+	if r.ino.fragment == noFragment {
+		return nil, fmt.Errorf("block out of range")
+	}
+	fragments, err := r.f.loadFragments()
+	if err != nil {
+		return nil, err
+	}
+	if int(r.ino.fragment) >= len(fragments) {
+		return nil, fmt.Errorf("invalid fragment index %d", r.ino.fragment)
+	}
+
+	frag := fragments[r.ino.fragment]
+	data, err := r.f.readDataBlock(frag.start, frag.size)
+	if err != nil {
+		return nil, err
+	}
+	tail := r.ino.size % int64(r.f.blockSize)
+	start := int64(r.ino.fragmentOff)
+	if start+tail > int64(len(data)) {
+		return nil, fmt.Errorf("invalid fragment offset %d", start)
+	}
+	return data[start : start+tail], nil
+}
+
+func (r *fileReader) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if off >= r.ino.size {
+		return 0, io.EOF
+	}
+
+	var (
+		n   int
+		err error
+	)
+	if remaining := r.ino.size - off; int64(len(p)) > remaining {
+		p = p[:remaining]
+		err = io.EOF
+	}
+
+	for len(p) > 0 {
+		index := int(off / int64(r.f.blockSize))
+		inBlock := off % int64(r.f.blockSize)
+
+		data, berr := r.block(index)
+		if berr != nil {
+			return n, berr
+		}
+		if inBlock >= int64(len(data)) {
+			return n, fmt.Errorf("block %d too short", index)
+		}
+		c := copy(p, data[inBlock:])
+		n += c
+		off += int64(c)
+		p = p[c:]
+	}
+	return n, err
+}
+
+// dirEntry is an entry of a directory listing.
+type dirEntry struct {
+	name     string
+	inodeRef uint64
+}
+
+func (f *FS) readDir(ino *inode) ([]dirEntry, error) {
+	// The listing size includes 3 bytes for the implicit '.' and '..' entries.
+	remaining := ino.size - 3
+	if remaining <= 0 {
+		return nil, nil
+	}
+
+	r, err := f.newMetadataReader(f.dirTableStart+int64(ino.dirBlock), int(ino.dirOffset))
+	if err != nil {
+		return nil, fmt.Errorf("error reading directory: %w", err)
+	}
+
+	var res []dirEntry
+	for remaining > 0 {
+		header, err := r.read(12)
+		if err != nil {
+			return nil, fmt.Errorf("error reading directory header: %w", err)
+		}
+		remaining -= 12
+		count := binary.LittleEndian.Uint32(header[0:]) + 1
+		start := binary.LittleEndian.Uint32(header[4:])
+		if count > 256 {
+			return nil, fmt.Errorf("invalid directory header count %d", count)
+		}
+
+		for i := uint32(0); i < count; i++ {
+			entry, err := r.read(8)
+			if err != nil {
+				return nil, fmt.Errorf("error reading directory entry: %w", err)
+			}
+			offset := binary.LittleEndian.Uint16(entry[0:])
+			nameSize := int(binary.LittleEndian.Uint16(entry[6:])) + 1
+			name, err := r.read(nameSize)
+			if err != nil {
+				return nil, fmt.Errorf("error reading directory entry name: %w", err)
+			}
+			remaining -= int64(8 + nameSize)
+
+			res = append(res, dirEntry{
+				name:     string(name),
+				inodeRef: uint64(start)<<16 | uint64(offset),
+			})
+		}
+	}
+	return res, nil
+}
+
+// inodes implements inodefs.Inodes.
+type inodes struct {
+	f *FS
+}
+
+func (n inodes) Root() (*inode, error) {
+	return n.f.readInode(n.f.rootInode)
+}
+
+func (n inodes) Lookup(dir *inode, name string) (*inode, error) {
+	entries, err := n.f.readDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.name == name {
+			return n.f.readInode(entry.inodeRef)
+		}
+	}
+	return nil, fs.ErrNotExist
+}
+
+func (n inodes) ReadDir(dir *inode) ([]inodefs.Entry[*inode], error) {
+	entries, err := n.f.readDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]inodefs.Entry[*inode], 0, len(entries))
+	for _, entry := range entries {
+		ino, err := n.f.readInode(entry.inodeRef)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, inodefs.Entry[*inode]{Name: entry.name, Inode: ino})
+	}
+	return res, nil
+}
+
+func (n inodes) ReadLink(ino *inode) (string, error) {
+	return ino.symlinkTarget, nil
+}
+
+func (n inodes) Open(ino *inode) (io.ReaderAt, error) {
+	return n.f.newFileReader(ino), nil
+}
+
+// Open implements fs.FS. Symbolic links are followed, where absolute targets are resolved
+// relative to the root of the file system.
+func (f *FS) Open(name string) (fs.File, error) {
+	return inodefs.Open[*inode](inodes{f}, name)
+}
+
+// ReadFile implements fs.ReadFileFS.
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	return inodefs.ReadFile[*inode](inodes{f}, name)
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return inodefs.ReadDir[*inode](inodes{f}, name)
+}
+
+// ReadLink returns the target of the symbolic link name.
+func (f *FS) ReadLink(name string) (string, error) {
+	return inodefs.ReadLink[*inode](inodes{f}, name)
+}
+
+// Lstat returns the file info of name without following a final symbolic link.
+// Its Sys method returns a *Stat.
+func (f *FS) Lstat(name string) (fs.FileInfo, error) {
+	return inodefs.Lstat[*inode](inodes{f}, name)
+}
+
+// Xattrs returns the extended attributes of name by their full name, e.g. "security.capability".
+// A final symbolic link is not followed.
+func (f *FS) Xattrs(name string) (map[string][]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "xattrs", Path: name, Err: fs.ErrInvalid}
+	}
+	ino, err := inodefs.Lookup[*inode](inodes{f}, name, false)
+	if err != nil {
+		return nil, &fs.PathError{Op: "xattrs", Path: name, Err: err}
+	}
+	xattrs, err := f.readXattrs(ino)
+	if err != nil {
+		return nil, &fs.PathError{Op: "xattrs", Path: name, Err: err}
+	}
+	return xattrs, nil
+}
+
+// Stat is the ownership information of a file, returned by the Sys method of its fs.FileInfo.
+type Stat struct {
+	// UID is the user id of the owner.
+	UID uint32
+	// GID is the group id of the owner.
+	GID uint32
+	// Ino is the inode number. Files with more than one link and the same inode number are hard links.
+	Ino uint64
+	// Nlink is the number of hard links.
+	Nlink uint32
+	// Major is the major device number of device files.
+	Major uint32
+	// Minor is the minor device number of device files.
+	Minor uint32
+}
+
+// decodeDev decodes a device number in the encoding of the Linux kernel's new_encode_dev.
+func decodeDev(dev uint32) (major, minor uint32) {
+	return (dev & 0xFFF00) >> 8, (dev & 0xFF) | ((dev >> 12) & 0xFFF00)
+}
+
+// encodeDev encodes a device number like the Linux kernel's new_encode_dev.
+func encodeDev(major, minor uint32) uint32 {
+	return (minor & 0xFF) | (major << 8) | ((minor &^ 0xFF) << 12)
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package squashfs_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing/fstest"
+
+	. "github.com/ironcore-dev/ironcore-image/fs/squashfs"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FS", func() {
+	var srcDir string
+
+	BeforeEach(func() {
+		srcDir = GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(srcDir, "var", "lib", "dpkg"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(srcDir, "var", "lib", "dpkg", "status"), []byte("Package: foo\n"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(srcDir, "small"), []byte("x"), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(srcDir, "big"), bytes.Repeat([]byte("0123456789"), 100_000), 0644)).To(Succeed())
+		Expect(os.Link(filepath.Join(srcDir, "big"), filepath.Join(srcDir, "big-link"))).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(srcDir, "many"), 0755)).To(Succeed())
+		for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+			Expect(os.WriteFile(filepath.Join(srcDir, "many", name), []byte(name), 0644)).To(Succeed())
+		}
+	})
+
+	mksquashfs := func(args ...string) *FS {
+		if _, err := exec.LookPath("mksquashfs"); err != nil {
+			Skip("mksquashfs not found")
+		}
+
+		img := filepath.Join(GinkgoT().TempDir(), "fs.sqfs")
+		cmd := exec.Command("mksquashfs", append([]string{srcDir, img, "-noappend", "-no-progress"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(out))
+
+		f, err := os.Open(img)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(f.Close)
+		Expect(IsSquashFS(f)).To(BeTrue())
+
+		fsys, err := New(f)
+		Expect(err).NotTo(HaveOccurred())
+		return fsys
+	}
+
+	DescribeTable("should read file systems created by mksquashfs",
+		func(args ...string) {
+			fsys := mksquashfs(args...)
+			Expect(fstest.TestFS(fsys, "var/lib/dpkg/status", "small", "big", "big-link", "many/h")).To(Succeed())
+
+			Expect(fs.ReadFile(fsys, "var/lib/dpkg/status")).To(Equal([]byte("Package: foo\n")))
+			Expect(fs.ReadFile(fsys, "big")).To(Equal(bytes.Repeat([]byte("0123456789"), 100_000)))
+
+			info, err := fsys.Lstat("big")
+			Expect(err).NotTo(HaveOccurred())
+			link, err := fsys.Lstat("big-link")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(link.Sys()).To(Equal(info.Sys()))
+			Expect(info.Sys().(*Stat).Nlink).To(Equal(uint32(2)))
+		},
+		Entry("gzip", "-comp", "gzip"),
+		Entry("zstd", "-comp", "zstd"),
+		Entry("small blocks", "-comp", "gzip", "-b", "4096"),
+		Entry("without fragments", "-comp", "gzip", "-no-fragments"),
+	)
+
+	It("should follow symbolic links", func() {
+		Expect(os.Symlink("../../var/lib/dpkg", filepath.Join(srcDir, "many", "rel"))).To(Succeed())
+		Expect(os.Symlink("/var/lib", filepath.Join(srcDir, "abs"))).To(Succeed())
+		Expect(os.Symlink("../../../..", filepath.Join(srcDir, "up"))).To(Succeed())
+		Expect(os.Symlink("loop", filepath.Join(srcDir, "loop"))).To(Succeed())
+
+		fsys := mksquashfs("-comp", "gzip")
+		Expect(fs.ReadFile(fsys, "many/rel/status")).To(Equal([]byte("Package: foo\n")))
+		Expect(fs.ReadFile(fsys, "abs/dpkg/status")).To(Equal([]byte("Package: foo\n")))
+		Expect(fs.ReadFile(fsys, "up/small")).To(Equal([]byte("x")))
+		Expect(fsys.ReadLink("abs")).To(Equal("/var/lib"))
+
+		info, err := fsys.Lstat("abs")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Type()).To(Equal(fs.ModeSymlink))
+
+		_, err = fs.ReadFile(fsys, "loop")
+		Expect(err).To(MatchError(ContainSubstring("too many levels of symbolic links")))
+		_, err = fsys.ReadLink("small")
+		Expect(err).To(MatchError(fs.ErrInvalid))
+		_, err = fs.ReadFile(fsys, "small/x")
+		Expect(err).To(MatchError(fs.ErrNotExist))
+	})
+
+	It("should reject other images", func() {
+		_, err := New(bytes.NewReader(make([]byte, 4096)))
+		Expect(err).To(MatchError(ErrNotSquashFS))
+	})
+
+	It("should reject invalid block sizes", func() {
+		for _, blockSize := range []uint32{0, 4095, 3 << 12, 2 << 20} {
+			sb := make([]byte, 4096)
+			binary.LittleEndian.PutUint32(sb[0:], 0x73717368)
+			binary.LittleEndian.PutUint32(sb[12:], blockSize)
+			binary.LittleEndian.PutUint16(sb[20:], uint16(CompressionGzip))
+			binary.LittleEndian.PutUint16(sb[28:], 4)
+
+			_, err := New(bytes.NewReader(sb))
+			Expect(err).To(MatchError(ContainSubstring("invalid block size")), "block size %d", blockSize)
+		}
+	})
+})
@@ -0,0 +1,970 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package squashfs
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// DefaultBlockSize is the data block size used by WriteDir if none is given.
+	DefaultBlockSize = 128 << 10
+
+	minBlockSize = 4 << 10
+	maxBlockSize = 1 << 20
+
+	// deviceBlockSize is the size the image is padded to, as done by mksquashfs.
+	deviceBlockSize = 4096
+
+	maxNameLen     = 256
+	maxDirCount    = 256
+	maxSymlinkSize = 4096
+
+	flagNoXattrs = 0x0200
+
+	blockUncompressed    = 1 << 24
+	metadataUncompressed = 0x8000
+)
+
+// IDMap maps a range of host user or group ids to ids in the image, like the id maps of user namespaces.
+type IDMap struct {
+	// ID is the first id in the image.
+	ID uint32
+	// HostID is the first id on the host.
+	HostID uint32
+	// Size is the number of mapped ids.
+	Size uint32
+}
+
+func mapID(maps []IDMap, id uint32) uint32 {
+	for _, m := range maps {
+		if id >= m.HostID && uint64(id) < uint64(m.HostID)+uint64(m.Size) {
+			return m.ID + (id - m.HostID)
+		}
+	}
+	return id
+}
+
+// WriteOptions are options for writing a squashfs image.
+type WriteOptions struct {
+	// Compression is the compression of the image. Defaults to CompressionGzip.
+	Compression Compression
+	// BlockSize is the data block size, a power of two between 4 KiB and 1 MiB. Defaults to DefaultBlockSize.
+	BlockSize uint32
+	// ModTime overrides the modification times of all files if set, e.g. to SOURCE_DATE_EPOCH.
+	ModTime *time.Time
+	// UIDMap maps the user ids of the files. Unmapped ids are kept.
+	UIDMap []IDMap
+	// GIDMap maps the group ids of the files. Unmapped ids are kept.
+	GIDMap []IDMap
+}
+
+// XattrFS is a file system providing the extended attributes of its files.
+type XattrFS interface {
+	fs.FS
+	// Xattrs returns the extended attributes of name by their full name without following a final symbolic link.
+	Xattrs(name string) (map[string][]byte, error)
+}
+
+// WriteDir writes a squashfs image of the directory tree at dir to w, see WriteFS.
+func WriteDir(w io.WriteSeeker, dir string, opts WriteOptions) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return WriteFS(w, &dirFS{ReadLinkFS: os.DirFS(dir).(fs.ReadLinkFS), dir: dir}, opts)
+}
+
+// dirFS is a directory tree of the host.
+type dirFS struct {
+	fs.ReadLinkFS
+	dir string
+}
+
+func (d *dirFS) Xattrs(name string) (map[string][]byte, error) {
+	return readHostXattrs(filepath.Join(d.dir, filepath.FromSlash(name)))
+}
+
+// WriteFS writes a squashfs image of fsys to w.
+//
+// The image only depends on the contents, names, modes, ownership, modification times and extended
+// attributes of the files in fsys, so packing the same tree twice yields identical images. Entries are
+// stored in byte-wise order of their names.
+//
+// Symbolic links are stored if fsys implements fs.ReadLinkFS, extended attributes if it implements XattrFS.
+// Only extended attributes in the user, trusted and security namespaces are stored, others (e.g. POSIX ACLs)
+// are skipped. Ownership, device numbers and hard links are taken from the Sys value of the file infos,
+// which may be a *Stat or, on Linux, a *syscall.Stat_t. Files without either are owned by root.
+func WriteFS(w io.WriteSeeker, fsys fs.FS, opts WriteOptions) error {
+	if opts.Compression == 0 {
+		opts.Compression = CompressionGzip
+	}
+	if opts.BlockSize == 0 {
+		opts.BlockSize = DefaultBlockSize
+	}
+	if opts.BlockSize < minBlockSize || opts.BlockSize > maxBlockSize || opts.BlockSize&(opts.BlockSize-1) != 0 {
+		return fmt.Errorf("invalid block size %d", opts.BlockSize)
+	}
+
+	compress, err := newCompressor(opts.Compression)
+	if err != nil {
+		return err
+	}
+
+	sw := &writer{
+		w:        w,
+		fsys:     fsys,
+		opts:     opts,
+		compress: compress,
+		idIndex:  make(map[uint32]uint16),
+		xattrIDs: make(map[string]uint32),
+	}
+
+	s := &scanner{fsys: fsys, opts: opts, links: make(map[fileID]*inodeState)}
+	root, err := s.scan(".", "")
+	if err != nil {
+		return err
+	}
+	if !root.mode.IsDir() {
+		return fmt.Errorf("root is not a directory")
+	}
+	sw.inodeCount = s.inodeCount
+	sw.modTime = s.modTime
+	for _, id := range s.ids() {
+		sw.idIndex[id] = uint16(len(sw.ids))
+		sw.ids = append(sw.ids, id)
+	}
+	if len(sw.ids) > math.MaxUint16 {
+		return fmt.Errorf("too many distinct uids and gids: %d", len(sw.ids))
+	}
+
+	return sw.write(root)
+}
+
+// fileStat is the ownership and identity of a file.
+type fileStat struct {
+	uid, gid     uint32
+	dev, ino     uint64
+	nlink        uint64
+	major, minor uint32
+}
+
+func statOf(info fs.FileInfo) fileStat {
+	if st, ok := info.Sys().(*Stat); ok {
+		return fileStat{
+			uid:   st.UID,
+			gid:   st.GID,
+			ino:   st.Ino,
+			nlink: uint64(st.Nlink),
+			major: st.Major,
+			minor: st.Minor,
+		}
+	}
+	return hostStat(info)
+}
+
+// fileID identifies a file to detect hard links.
+type fileID struct {
+	dev, ino uint64
+}
+
+// inodeState is the inode of one or more (hard linked) nodes.
+type inodeState struct {
+	number  uint32
+	nlink   uint32
+	written bool
+	ref     uint64
+}
+
+// node is a file of the tree to write.
+type node struct {
+	name     string
+	path     string
+	mode     fs.FileMode
+	uid, gid uint32
+	mtime    uint32
+	rdev     uint32
+	target   string
+	xattrs   []xattr
+	children []*node
+	inode    *inodeState
+}
+
+type xattr struct {
+	name  string
+	value []byte
+}
+
+// scanner reads the tree to write.
+type scanner struct {
+	fsys       fs.FS
+	opts       WriteOptions
+	links      map[fileID]*inodeState
+	inodeCount uint32
+	modTime    uint32
+	idSet      map[uint32]struct{}
+}
+
+func (s *scanner) scan(name, base string) (*node, error) {
+	info, err := fs.Lstat(s.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	st := statOf(info)
+
+	mtime := info.ModTime()
+	if s.opts.ModTime != nil {
+		mtime = *s.opts.ModTime
+	}
+	n := &node{
+		name:  base,
+		path:  name,
+		mode:  info.Mode(),
+		uid:   mapID(s.opts.UIDMap, st.uid),
+		gid:   mapID(s.opts.GIDMap, st.gid),
+		mtime: clampTime(mtime),
+		rdev:  encodeDev(st.major, st.minor),
+	}
+	s.addID(n.uid)
+	s.addID(n.gid)
+	s.modTime = max(s.modTime, n.mtime)
+
+	if xfs, ok := s.fsys.(XattrFS); ok {
+		xattrs, err := xfs.Xattrs(name)
+		if err != nil {
+			return nil, fmt.Errorf("error reading xattrs of %s: %w", name, err)
+		}
+		n.xattrs = supportedXattrs(xattrs)
+	}
+
+	if !n.mode.IsDir() && st.nlink > 1 {
+		id := fileID{st.dev, st.ino}
+		if ino, ok := s.links[id]; ok {
+			ino.nlink++
+			n.inode = ino
+		} else {
+			n.inode = s.newInode()
+			s.links[id] = n.inode
+		}
+	} else {
+		n.inode = s.newInode()
+	}
+
+	switch {
+	case n.mode.IsDir():
+		entries, err := fs.ReadDir(s.fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if len(entry.Name()) > maxNameLen {
+				return nil, fmt.Errorf("name of %s too long", path.Join(name, entry.Name()))
+			}
+			child, err := s.scan(path.Join(name, entry.Name()), entry.Name())
+			if err != nil {
+				return nil, err
+			}
+			n.children = append(n.children, child)
+		}
+		slices.SortFunc(n.children, func(a, b *node) int { return strings.Compare(a.name, b.name) })
+	case n.mode&fs.ModeSymlink != 0:
+		if n.target, err = fs.ReadLink(s.fsys, name); err != nil {
+			return nil, err
+		}
+		if len(n.target) > maxSymlinkSize {
+			return nil, fmt.Errorf("target of symlink %s too long", name)
+		}
+	}
+	return n, nil
+}
+
+func (s *scanner) newInode() *inodeState {
+	s.inodeCount++
+	return &inodeState{number: s.inodeCount, nlink: 1}
+}
+
+func (s *scanner) addID(id uint32) {
+	if s.idSet == nil {
+		s.idSet = make(map[uint32]struct{})
+	}
+	s.idSet[id] = struct{}{}
+}
+
+// ids returns the sorted distinct uids and gids of the tree.
+func (s *scanner) ids() []uint32 {
+	res := make([]uint32, 0, len(s.idSet))
+	for id := range s.idSet {
+		res = append(res, id)
+	}
+	slices.Sort(res)
+	return res
+}
+
+func clampTime(t time.Time) uint32 {
+	switch sec := t.Unix(); {
+	case sec < 0:
+		return 0
+	case sec > math.MaxUint32:
+		return math.MaxUint32
+	default:
+		return uint32(sec)
+	}
+}
+
+// compressor compresses a block. It returns nil if the block is not compressible.
+type compressor func(src []byte) ([]byte, error)
+
+func newCompressor(c Compression) (compressor, error) {
+	switch c {
+	case CompressionGzip:
+		var buf bytes.Buffer
+		zw, err := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+		return func(src []byte) ([]byte, error) {
+			buf.Reset()
+			zw.Reset(&buf)
+			if _, err := zw.Write(src); err != nil {
+				return nil, err
+			}
+			if err := zw.Close(); err != nil {
+				return nil, err
+			}
+			if buf.Len() >= len(src) {
+				return nil, nil
+			}
+			return bytes.Clone(buf.Bytes()), nil
+		}, nil
+	case CompressionZstd:
+		// Like mksquashfs, frames are written without checksum: the kernel stops decompressing once
+		// a block's pages are filled and fails if the frame has trailing data.
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1), zstd.WithEncoderLevel(zstd.SpeedBetterCompression), zstd.WithEncoderCRC(false))
+		if err != nil {
+			return nil, fmt.Errorf("error creating zstd encoder: %w", err)
+		}
+		return func(src []byte) ([]byte, error) {
+			res := enc.EncodeAll(src, nil)
+			if len(res) >= len(src) {
+				return nil, nil
+			}
+			return res, nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCompression, c)
+	}
+}
+
+// metadataWriter buffers a stream of metadata in metadata blocks.
+type metadataWriter struct {
+	compress compressor
+	out      bytes.Buffer
+	buf      []byte
+	// blocks are the offsets of the metadata blocks in out.
+	blocks []int64
+}
+
+// ref returns the reference of the current position, i.e. the offset of the current
+// block shifted by 16 bits plus the offset within its uncompressed data.
+func (m *metadataWriter) ref() uint64 {
+	return uint64(m.out.Len())<<16 | uint64(len(m.buf))
+}
+
+func (m *metadataWriter) Write(p []byte) (int, error) {
+	m.buf = append(m.buf, p...)
+	for len(m.buf) >= metadataBlockSize {
+		if err := m.flush(metadataBlockSize); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (m *metadataWriter) flush(n int) error {
+	block := m.buf[:n]
+	data, err := m.compress(block)
+	if err != nil {
+		return fmt.Errorf("error compressing metadata: %w", err)
+	}
+	header := uint16(len(data))
+	if data == nil {
+		data, header = block, uint16(len(block))|metadataUncompressed
+	}
+
+	m.blocks = append(m.blocks, int64(m.out.Len()))
+	_ = binary.Write(&m.out, binary.LittleEndian, header)
+	m.out.Write(data)
+	m.buf = append(m.buf[:0], m.buf[n:]...)
+	return nil
+}
+
+// finish flushes the last block and returns the metadata.
+func (m *metadataWriter) finish() ([]byte, error) {
+	if len(m.buf) > 0 {
+		if err := m.flush(len(m.buf)); err != nil {
+			return nil, err
+		}
+	}
+	return m.out.Bytes(), nil
+}
+
+// writer writes the squashfs image.
+type writer struct {
+	w        io.WriteSeeker
+	fsys     fs.FS
+	off      int64
+	opts     WriteOptions
+	compress compressor
+
+	inodeCount uint32
+	modTime    uint32
+	ids        []uint32
+	idIndex    map[uint32]uint16
+
+	inodes metadataWriter
+	dirs   metadataWriter
+
+	fragment  []byte
+	fragments []fragment
+
+	xattrs   metadataWriter
+	xattrIDs map[string]uint32
+	// xattrTable holds the entries of the xattr id table.
+	xattrTable []byte
+}
+
+func (w *writer) writeRaw(p []byte) error {
+	n, err := w.w.Write(p)
+	w.off += int64(n)
+	return err
+}
+
+func (w *writer) write(root *node) error {
+	w.inodes.compress, w.dirs.compress, w.xattrs.compress = w.compress, w.compress, w.compress
+
+	if err := w.writeRaw(make([]byte, superblockSize)); err != nil {
+		return fmt.Errorf("error writing superblock: %w", err)
+	}
+
+	rootRef, _, err := w.writeNode(root, w.inodeCount+1)
+	if err != nil {
+		return err
+	}
+	if err := w.flushFragment(); err != nil {
+		return err
+	}
+
+	var sb [superblockSize]byte
+	le := binary.LittleEndian
+	le.PutUint32(sb[0:], magic)
+	le.PutUint32(sb[4:], w.inodeCount)
+	le.PutUint32(sb[8:], w.modTime)
+	le.PutUint32(sb[12:], w.opts.BlockSize)
+	le.PutUint32(sb[16:], uint32(len(w.fragments)))
+	le.PutUint16(sb[20:], uint16(w.opts.Compression))
+	le.PutUint16(sb[22:], uint16(bitLen(w.opts.BlockSize)))
+	le.PutUint16(sb[26:], uint16(len(w.ids)))
+	le.PutUint16(sb[28:], 4)
+	le.PutUint64(sb[32:], rootRef)
+	le.PutUint64(sb[88:], invalidTable)
+
+	inodeTable, err := w.inodes.finish()
+	if err != nil {
+		return err
+	}
+	le.PutUint64(sb[64:], uint64(w.off))
+	if err := w.writeRaw(inodeTable); err != nil {
+		return fmt.Errorf("error writing inode table: %w", err)
+	}
+
+	dirTable, err := w.dirs.finish()
+	if err != nil {
+		return err
+	}
+	le.PutUint64(sb[72:], uint64(w.off))
+	if err := w.writeRaw(dirTable); err != nil {
+		return fmt.Errorf("error writing directory table: %w", err)
+	}
+
+	fragmentTable := make([]byte, 0, len(w.fragments)*16)
+	for _, frag := range w.fragments {
+		fragmentTable = le.AppendUint64(fragmentTable, uint64(frag.start))
+		fragmentTable = le.AppendUint32(fragmentTable, frag.size)
+		fragmentTable = le.AppendUint32(fragmentTable, 0)
+	}
+	fragmentTableStart, err := w.writeTable(fragmentTable)
+	if err != nil {
+		return fmt.Errorf("error writing fragment table: %w", err)
+	}
+	le.PutUint64(sb[80:], uint64(fragmentTableStart))
+
+	idTable := make([]byte, 0, len(w.ids)*4)
+	for _, id := range w.ids {
+		idTable = le.AppendUint32(idTable, id)
+	}
+	idTableStart, err := w.writeTable(idTable)
+	if err != nil {
+		return fmt.Errorf("error writing id table: %w", err)
+	}
+	le.PutUint64(sb[48:], uint64(idTableStart))
+
+	if len(w.xattrIDs) == 0 {
+		le.PutUint16(sb[24:], flagNoXattrs)
+		le.PutUint64(sb[56:], invalidTable)
+	} else {
+		xattrIDTableStart, err := w.writeXattrTables()
+		if err != nil {
+			return fmt.Errorf("error writing xattr tables: %w", err)
+		}
+		le.PutUint64(sb[56:], uint64(xattrIDTableStart))
+	}
+
+	le.PutUint64(sb[40:], uint64(w.off))
+	if pad := w.off % deviceBlockSize; pad != 0 {
+		if err := w.writeRaw(make([]byte, deviceBlockSize-pad)); err != nil {
+			return fmt.Errorf("error writing padding: %w", err)
+		}
+	}
+
+	if _, err := w.w.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking to superblock: %w", err)
+	}
+	if _, err := w.w.Write(sb[:]); err != nil {
+		return fmt.Errorf("error writing superblock: %w", err)
+	}
+	return nil
+}
+
+func bitLen(v uint32) int {
+	n := 0
+	for v > 1 {
+		v >>= 1
+		n++
+	}
+	return n
+}
+
+// writeTable writes data in metadata blocks followed by their index and returns the offset of the index.
+func (w *writer) writeTable(data []byte) (int64, error) {
+	m := metadataWriter{compress: w.compress}
+	_, _ = m.Write(data)
+	blocks, err := m.finish()
+	if err != nil {
+		return 0, err
+	}
+
+	start := w.off
+	if err := w.writeRaw(blocks); err != nil {
+		return 0, err
+	}
+	index := make([]byte, 0, len(m.blocks)*8)
+	for _, block := range m.blocks {
+		index = binary.LittleEndian.AppendUint64(index, uint64(start+block))
+	}
+	indexStart := w.off
+	return indexStart, w.writeRaw(index)
+}
+
+// writeXattrTables writes the xattr key value pairs and the xattr id table and returns the offset of the latter.
+func (w *writer) writeXattrTables() (int64, error) {
+	kv, err := w.xattrs.finish()
+	if err != nil {
+		return 0, err
+	}
+	kvStart := w.off
+	if err := w.writeRaw(kv); err != nil {
+		return 0, err
+	}
+
+	m := metadataWriter{compress: w.compress}
+	_, _ = m.Write(w.xattrTable)
+	ids, err := m.finish()
+	if err != nil {
+		return 0, err
+	}
+	idsStart := w.off
+	if err := w.writeRaw(ids); err != nil {
+		return 0, err
+	}
+
+	header := binary.LittleEndian.AppendUint64(nil, uint64(kvStart))
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(w.xattrIDs)))
+	header = binary.LittleEndian.AppendUint32(header, 0)
+	for _, block := range m.blocks {
+		header = binary.LittleEndian.AppendUint64(header, uint64(idsStart+block))
+	}
+	start := w.off
+	return start, w.writeRaw(header)
+}
+
+// xattrIndex returns the index of the xattr set xattrs in the xattr id table, adding it if necessary.
+func (w *writer) xattrIndex(xattrs []xattr) (uint32, error) {
+	if len(xattrs) == 0 {
+		return noXattr, nil
+	}
+
+	var (
+		data []byte
+		size uint32
+	)
+	for _, x := range xattrs {
+		typ, name := xattrType(x.name)
+		data = binary.LittleEndian.AppendUint16(data, typ)
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(name)))
+		data = append(data, name...)
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(x.value)))
+		data = append(data, x.value...)
+		size += uint32(len(x.name) + len(x.value))
+	}
+	key := string(data)
+	if index, ok := w.xattrIDs[key]; ok {
+		return index, nil
+	}
+	index := uint32(len(w.xattrIDs))
+	w.xattrIDs[key] = index
+
+	ref := w.xattrs.ref()
+	if _, err := w.xattrs.Write(data); err != nil {
+		return 0, err
+	}
+	w.xattrTable = binary.LittleEndian.AppendUint64(w.xattrTable, ref)
+	w.xattrTable = binary.LittleEndian.AppendUint32(w.xattrTable, uint32(len(xattrs)))
+	w.xattrTable = binary.LittleEndian.AppendUint32(w.xattrTable, size)
+	return index, nil
+}
+
+// xattrType returns the type and the name without prefix of the extended attribute name.
+// name must have a supported prefix.
+func xattrType(name string) (uint16, string) {
+	for typ, prefix := range xattrPrefixes {
+		if rest, ok := strings.CutPrefix(name, prefix); ok {
+			return typ, rest
+		}
+	}
+	panic(fmt.Sprintf("unsupported xattr %s", name))
+}
+
+// supportedXattrs returns the extended attributes of xattrs that can be stored, sorted by name.
+func supportedXattrs(xattrs map[string][]byte) []xattr {
+	var res []xattr
+	for name, value := range xattrs {
+		for _, prefix := range xattrPrefixes {
+			if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+				res = append(res, xattr{name: name, value: value})
+				break
+			}
+		}
+	}
+	slices.SortFunc(res, func(a, b xattr) int { return strings.Compare(a.name, b.name) })
+	return res
+}
+
+// writeNode writes the inode of n and, for directories, its children and listing.
+// It returns the inode reference and basic inode type of n.
+func (w *writer) writeNode(n *node, parent uint32) (uint64, uint16, error) {
+	if n.inode.written {
+		return n.inode.ref, basicType(n.mode), nil
+	}
+
+	xattrIdx, err := w.xattrIndex(n.xattrs)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var body []byte
+	typ := basicType(n.mode)
+	le := binary.LittleEndian
+	switch {
+	case n.mode.IsDir():
+		if body, typ, err = w.writeDir(n, parent, xattrIdx); err != nil {
+			return 0, 0, err
+		}
+	case n.mode.IsRegular():
+		if body, typ, err = w.writeFile(n, xattrIdx); err != nil {
+			return 0, 0, fmt.Errorf("error writing %s: %w", n.path, err)
+		}
+	case n.mode&fs.ModeSymlink != 0:
+		body = le.AppendUint32(body, n.inode.nlink)
+		body = le.AppendUint32(body, uint32(len(n.target)))
+		body = append(body, n.target...)
+		if xattrIdx != noXattr {
+			typ = typeExtSymlink
+			body = le.AppendUint32(body, xattrIdx)
+		}
+	case n.mode&fs.ModeDevice != 0:
+		body = le.AppendUint32(body, n.inode.nlink)
+		body = le.AppendUint32(body, n.rdev)
+		if xattrIdx != noXattr {
+			typ += typeExtDir - typeDir
+			body = le.AppendUint32(body, xattrIdx)
+		}
+	default:
+		body = le.AppendUint32(body, n.inode.nlink)
+		if xattrIdx != noXattr {
+			typ += typeExtDir - typeDir
+			body = le.AppendUint32(body, xattrIdx)
+		}
+	}
+
+	header := make([]byte, 16)
+	le.PutUint16(header[0:], typ)
+	le.PutUint16(header[2:], permissions(n.mode))
+	le.PutUint16(header[4:], w.idIndex[n.uid])
+	le.PutUint16(header[6:], w.idIndex[n.gid])
+	le.PutUint32(header[8:], n.mtime)
+	le.PutUint32(header[12:], n.inode.number)
+
+	ref := w.inodes.ref()
+	_, _ = w.inodes.Write(header)
+	_, _ = w.inodes.Write(body)
+
+	n.inode.written, n.inode.ref = true, ref
+	return ref, basicType(n.mode), nil
+}
+
+func basicType(mode fs.FileMode) uint16 {
+	switch {
+	case mode.IsDir():
+		return typeDir
+	case mode.IsRegular():
+		return typeFile
+	case mode&fs.ModeSymlink != 0:
+		return typeSymlink
+	case mode&fs.ModeCharDevice != 0:
+		return typeCharDev
+	case mode&fs.ModeDevice != 0:
+		return typeBlockDev
+	case mode&fs.ModeNamedPipe != 0:
+		return typeFifo
+	default:
+		return typeSocket
+	}
+}
+
+func permissions(mode fs.FileMode) uint16 {
+	perm := uint16(mode.Perm())
+	if mode&fs.ModeSetuid != 0 {
+		perm |= 0o4000
+	}
+	if mode&fs.ModeSetgid != 0 {
+		perm |= 0o2000
+	}
+	if mode&fs.ModeSticky != 0 {
+		perm |= 0o1000
+	}
+	return perm
+}
+
+// writeDir writes the children and the listing of the directory n and returns its inode body and type.
+func (w *writer) writeDir(n *node, parent, xattrIdx uint32) ([]byte, uint16, error) {
+	type entry struct {
+		name string
+		ref  uint64
+		num  uint32
+		typ  uint16
+	}
+	entries := make([]entry, 0, len(n.children))
+	nlink := uint32(2)
+	for _, child := range n.children {
+		ref, typ, err := w.writeNode(child, n.inode.number)
+		if err != nil {
+			return nil, 0, err
+		}
+		if typ == typeDir {
+			nlink++
+		}
+		entries = append(entries, entry{name: child.name, ref: ref, num: child.inode.number, typ: typ})
+	}
+
+	start := w.dirs.ref()
+	var listing []byte
+	le := binary.LittleEndian
+	for i := 0; i < len(entries); {
+		block, base := uint32(entries[i].ref>>16), entries[i].num
+		j := i
+		for j < len(entries) && j-i < maxDirCount &&
+			uint32(entries[j].ref>>16) == block &&
+			int64(entries[j].num)-int64(base) >= math.MinInt16 && int64(entries[j].num)-int64(base) <= math.MaxInt16 {
+			j++
+		}
+
+		listing = le.AppendUint32(listing, uint32(j-i-1))
+		listing = le.AppendUint32(listing, block)
+		listing = le.AppendUint32(listing, base)
+		for _, e := range entries[i:j] {
+			listing = le.AppendUint16(listing, uint16(e.ref&0xFFFF))
+			listing = le.AppendUint16(listing, uint16(int16(int64(e.num)-int64(base))))
+			listing = le.AppendUint16(listing, e.typ)
+			listing = le.AppendUint16(listing, uint16(len(e.name)-1))
+			listing = append(listing, e.name...)
+		}
+		i = j
+	}
+	_, _ = w.dirs.Write(listing)
+
+	// The listing size includes 3 bytes for the implicit '.' and '..' entries.
+	size := uint64(len(listing)) + 3
+	block, offset := uint32(start>>16), uint16(start&0xFFFF)
+
+	var body []byte
+	if size <= math.MaxUint16 && xattrIdx == noXattr {
+		body = le.AppendUint32(body, block)
+		body = le.AppendUint32(body, nlink)
+		body = le.AppendUint16(body, uint16(size))
+		body = le.AppendUint16(body, offset)
+		body = le.AppendUint32(body, parent)
+		return body, typeDir, nil
+	}
+	if size > math.MaxUint32 {
+		return nil, 0, errors.New("directory listing too large")
+	}
+	body = le.AppendUint32(body, nlink)
+	body = le.AppendUint32(body, uint32(size))
+	body = le.AppendUint32(body, block)
+	body = le.AppendUint32(body, parent)
+	body = le.AppendUint16(body, 0)
+	body = le.AppendUint16(body, offset)
+	body = le.AppendUint32(body, xattrIdx)
+	return body, typeExtDir, nil
+}
+
+// writeFile writes the data of the regular file n and returns its inode body and type.
+// Full blocks are stored as data blocks, the tail in a fragment.
+func (w *writer) writeFile(n *node, xattrIdx uint32) ([]byte, uint16, error) {
+	f, err := w.fsys.Open(n.path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var (
+		blocksStart = w.off
+		blockSizes  []uint32
+		size        uint64
+		sparse      uint64
+		fragIndex   = uint32(noFragment)
+		fragOffset  uint32
+	)
+	buf := make([]byte, w.opts.BlockSize)
+	for {
+		m, err := io.ReadFull(f, buf)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, 0, err
+		}
+		size += uint64(m)
+		if m < len(buf) {
+			if m > 0 {
+				fragIndex, fragOffset, err = w.addFragment(buf[:m])
+				if err != nil {
+					return nil, 0, err
+				}
+			}
+			break
+		}
+
+		if isZero(buf) {
+			blockSizes = append(blockSizes, 0)
+			sparse += uint64(m)
+			continue
+		}
+		sizeWord, err := w.writeBlock(buf)
+		if err != nil {
+			return nil, 0, err
+		}
+		blockSizes = append(blockSizes, sizeWord)
+	}
+
+	var body []byte
+	le := binary.LittleEndian
+	if blocksStart <= math.MaxUint32 && size <= math.MaxUint32 && n.inode.nlink == 1 && xattrIdx == noXattr {
+		body = le.AppendUint32(body, uint32(blocksStart))
+		body = le.AppendUint32(body, fragIndex)
+		body = le.AppendUint32(body, fragOffset)
+		body = le.AppendUint32(body, uint32(size))
+		for _, s := range blockSizes {
+			body = le.AppendUint32(body, s)
+		}
+		return body, typeFile, nil
+	}
+	body = le.AppendUint64(body, uint64(blocksStart))
+	body = le.AppendUint64(body, size)
+	body = le.AppendUint64(body, sparse)
+	body = le.AppendUint32(body, n.inode.nlink)
+	body = le.AppendUint32(body, fragIndex)
+	body = le.AppendUint32(body, fragOffset)
+	body = le.AppendUint32(body, xattrIdx)
+	for _, s := range blockSizes {
+		body = le.AppendUint32(body, s)
+	}
+	return body, typeExtFile, nil
+}
+
+func isZero(p []byte) bool {
+	for _, b := range p {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// writeBlock writes a data block and returns its on-disk size word.
+func (w *writer) writeBlock(block []byte) (uint32, error) {
+	data, err := w.compress(block)
+	if err != nil {
+		return 0, fmt.Errorf("error compressing block: %w", err)
+	}
+	sizeWord := uint32(len(data))
+	if data == nil {
+		data, sizeWord = block, uint32(len(block))|blockUncompressed
+	}
+	if err := w.writeRaw(data); err != nil {
+		return 0, fmt.Errorf("error writing block: %w", err)
+	}
+	return sizeWord, nil
+}
+
+// addFragment adds the tail of a file to the current fragment block and returns its index and offset.
+func (w *writer) addFragment(tail []byte) (uint32, uint32, error) {
+	if len(w.fragment)+len(tail) > int(w.opts.BlockSize) {
+		if err := w.flushFragment(); err != nil {
+			return 0, 0, err
+		}
+	}
+	offset := uint32(len(w.fragment))
+	w.fragment = append(w.fragment, tail...)
+	return uint32(len(w.fragments)), offset, nil
+}
+
+func (w *writer) flushFragment() error {
+	if len(w.fragment) == 0 {
+		return nil
+	}
+	start := w.off
+	sizeWord, err := w.writeBlock(w.fragment)
+	if err != nil {
+		return fmt.Errorf("error writing fragment: %w", err)
+	}
+	w.fragments = append(w.fragments, fragment{start: start, size: sizeWord})
+	w.fragment = w.fragment[:0]
+	return nil
+}
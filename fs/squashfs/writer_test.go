@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package squashfs_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing/fstest"
+	"time"
+
+	. "github.com/ironcore-dev/ironcore-image/fs/squashfs"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"golang.org/x/sys/unix"
+)
+
+var _ = Describe("WriteDir", func() {
+	var srcDir string
+
+	BeforeEach(func() {
+		srcDir = GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(srcDir, "etc"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(srcDir, "etc", "os-release"), []byte("ID=test\n"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(srcDir, "empty"), nil, 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(srcDir, "big"), bytes.Repeat([]byte("0123456789"), 100_000), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(srcDir, "sparse"), make([]byte, 3*DefaultBlockSize+7), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(srcDir, "usr", "bin"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(srcDir, "usr", "bin", "tool"), []byte("#!/bin/sh\n"), 0755)).To(Succeed())
+		Expect(os.Chmod(filepath.Join(srcDir, "usr", "bin", "tool"), 0755|fs.ModeSetuid)).To(Succeed())
+		Expect(os.Link(filepath.Join(srcDir, "usr", "bin", "tool"), filepath.Join(srcDir, "usr", "bin", "alias"))).To(Succeed())
+		Expect(os.Symlink("usr/bin", filepath.Join(srcDir, "bin"))).To(Succeed())
+		Expect(syscall.Mkfifo(filepath.Join(srcDir, "fifo"), 0600)).To(Succeed())
+		Expect(os.Mkdir(filepath.Join(srcDir, "tmp"), 0777|fs.ModeSticky)).To(Succeed())
+		Expect(os.Chmod(filepath.Join(srcDir, "tmp"), 0777|fs.ModeSticky)).To(Succeed())
+	})
+
+	write := func(opts WriteOptions) (*FS, []byte) {
+		path := filepath.Join(GinkgoT().TempDir(), "fs.sqfs")
+		f, err := os.Create(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(WriteDir(f, srcDir, opts)).To(Succeed())
+		Expect(f.Close()).To(Succeed())
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(data) % 4096).To(BeZero())
+		Expect(IsSquashFS(bytes.NewReader(data))).To(BeTrue())
+
+		fsys, err := New(bytes.NewReader(data))
+		Expect(err).NotTo(HaveOccurred())
+		return fsys, data
+	}
+
+	DescribeTable("should write images readable by the reader",
+		func(opts WriteOptions) {
+			fsys, _ := write(opts)
+			if opts.Compression != 0 {
+				Expect(fsys.Compression()).To(Equal(opts.Compression))
+			}
+			Expect(fstest.TestFS(fsys, "etc/os-release", "empty", "big", "sparse", "usr/bin/tool", "usr/bin/alias", "tmp", "fifo")).To(Succeed())
+
+			Expect(fs.ReadFile(fsys, "etc/os-release")).To(Equal([]byte("ID=test\n")))
+			Expect(fs.ReadFile(fsys, "empty")).To(BeEmpty())
+			Expect(fs.ReadFile(fsys, "big")).To(Equal(bytes.Repeat([]byte("0123456789"), 100_000)))
+			Expect(fs.ReadFile(fsys, "sparse")).To(Equal(make([]byte, 3*DefaultBlockSize+7)))
+			Expect(fs.ReadFile(fsys, "bin/tool")).To(Equal([]byte("#!/bin/sh\n")))
+			Expect(fsys.ReadLink("bin")).To(Equal("usr/bin"))
+
+			info, err := fs.Stat(fsys, "usr/bin/tool")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode()).To(Equal(0755 | fs.ModeSetuid))
+			Expect(info.Sys().(*Stat).Nlink).To(Equal(uint32(2)))
+
+			info, err = fs.Stat(fsys, "tmp")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode()).To(Equal(0777 | fs.ModeDir | fs.ModeSticky))
+
+			info, err = fsys.Lstat("fifo")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode()).To(Equal(0600 | fs.ModeNamedPipe))
+
+			info, err = fs.Stat(fsys, ".")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Sys().(*Stat).Nlink).To(Equal(uint32(5)))
+		},
+		Entry("gzip", WriteOptions{}),
+		Entry("zstd", WriteOptions{Compression: CompressionZstd}),
+		Entry("small blocks", WriteOptions{BlockSize: 4096}),
+	)
+
+	It("should write deterministic images", func() {
+		modTime := time.Unix(1700000000, 0)
+		_, first := write(WriteOptions{ModTime: &modTime})
+
+		for _, path := range []string{"etc/os-release", "big", "etc", "."} {
+			Expect(os.Chtimes(filepath.Join(srcDir, path), time.Now(), time.Now())).To(Succeed())
+		}
+		fsys, second := write(WriteOptions{ModTime: &modTime})
+		Expect(second).To(Equal(first))
+
+		info, err := fs.Stat(fsys, "etc/os-release")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.ModTime()).To(Equal(modTime))
+	})
+
+	It("should keep modification times", func() {
+		modTime := time.Unix(1600000000, 0)
+		Expect(os.Chtimes(filepath.Join(srcDir, "big"), modTime, modTime)).To(Succeed())
+
+		fsys, _ := write(WriteOptions{})
+		info, err := fs.Stat(fsys, "big")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.ModTime()).To(Equal(modTime))
+	})
+
+	It("should map the ownership of files", func() {
+		uid, gid := uint32(os.Getuid()), uint32(os.Getgid())
+		fsys, _ := write(WriteOptions{
+			UIDMap: []IDMap{{ID: 1000, HostID: uid, Size: 1}},
+			GIDMap: []IDMap{{ID: 2000, HostID: gid, Size: 1}},
+		})
+
+		for _, name := range []string{".", "etc/os-release", "bin", "usr/bin/alias"} {
+			info, err := fsys.Lstat(name)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Sys()).To(PointTo(MatchFields(IgnoreExtras, Fields{"UID": Equal(uint32(1000)), "GID": Equal(uint32(2000))})), name)
+		}
+	})
+
+	It("should store extended attributes", func() {
+		for path, value := range map[string]string{"etc/os-release": "a", "etc": "b", "big": "a"} {
+			err := unix.Lsetxattr(filepath.Join(srcDir, path), "user.test", []byte(value), 0)
+			if errors.Is(err, unix.ENOTSUP) || errors.Is(err, unix.EPERM) {
+				Skip("extended attributes not supported")
+			}
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(unix.Lsetxattr(filepath.Join(srcDir, "big"), "user.other", []byte("value"), 0)).To(Succeed())
+
+		fsys, _ := write(WriteOptions{})
+		Expect(fsys.Xattrs("etc/os-release")).To(Equal(map[string][]byte{"user.test": []byte("a")}))
+		Expect(fsys.Xattrs("etc")).To(Equal(map[string][]byte{"user.test": []byte("b")}))
+		Expect(fsys.Xattrs("big")).To(Equal(map[string][]byte{"user.test": []byte("a"), "user.other": []byte("value")}))
+		Expect(fsys.Xattrs("empty")).To(BeEmpty())
+
+		Expect(fs.ReadFile(fsys, "etc/os-release")).To(Equal([]byte("ID=test\n")))
+		Expect(fs.ReadFile(fsys, "big")).To(Equal(bytes.Repeat([]byte("0123456789"), 100_000)))
+	})
+
+	It("should copy squashfs images", func() {
+		err := unix.Lsetxattr(filepath.Join(srcDir, "etc"), "user.test", []byte("value"), 0)
+		if errors.Is(err, unix.ENOTSUP) || errors.Is(err, unix.EPERM) {
+			Skip("extended attributes not supported")
+		}
+		Expect(err).NotTo(HaveOccurred())
+		src, data := write(WriteOptions{UIDMap: []IDMap{{ID: 1000, HostID: uint32(os.Getuid()), Size: 1}}})
+
+		f, err := os.Create(filepath.Join(GinkgoT().TempDir(), "copy.sqfs"))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(f.Close)
+		Expect(WriteFS(f, src, WriteOptions{})).To(Succeed())
+		copied, err := os.ReadFile(f.Name())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(copied).To(Equal(data))
+	})
+
+	It("should write large directories", func() {
+		dir := filepath.Join(srcDir, "many")
+		Expect(os.Mkdir(dir, 0755)).To(Succeed())
+		for i := range 2500 {
+			name := fmt.Sprintf("file-with-a-rather-long-name-%05d", i)
+			Expect(os.WriteFile(filepath.Join(dir, name), []byte(name), 0644)).To(Succeed())
+		}
+
+		fsys, _ := write(WriteOptions{})
+		entries, err := fsys.ReadDir("many")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(2500))
+		Expect(entries[1234].Name()).To(Equal("file-with-a-rather-long-name-01234"))
+		Expect(fs.ReadFile(fsys, "many/file-with-a-rather-long-name-00000")).To(Equal([]byte("file-with-a-rather-long-name-00000")))
+		Expect(fs.ReadFile(fsys, "many/file-with-a-rather-long-name-02499")).To(Equal([]byte("file-with-a-rather-long-name-02499")))
+	})
+
+	It("should write images readable by unsquashfs", func() {
+		if _, err := exec.LookPath("unsquashfs"); err != nil {
+			Skip("unsquashfs not found")
+		}
+
+		for _, opts := range []WriteOptions{{}, {Compression: CompressionZstd, BlockSize: 4096}} {
+			_, data := write(opts)
+			img := filepath.Join(GinkgoT().TempDir(), "fs.sqfs")
+			Expect(os.WriteFile(img, data, 0644)).To(Succeed())
+
+			out, err := exec.Command("unsquashfs", "-lls", img).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(out))
+			Expect(string(out)).To(SatisfyAll(
+				ContainSubstring("squashfs-root/etc/os-release"),
+				ContainSubstring("squashfs-root/bin -> usr/bin"),
+				ContainSubstring("squashfs-root/usr/bin/alias"),
+				MatchRegexp(`prw------- .* squashfs-root/fifo`),
+				MatchRegexp(`drwxrwxrwt .* squashfs-root/tmp`),
+			))
+
+			dest := filepath.Join(GinkgoT().TempDir(), "root")
+			out, err = exec.Command("unsquashfs", "-no-progress", "-d", dest, img).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(out))
+			Expect(os.ReadFile(filepath.Join(dest, "etc", "os-release"))).To(Equal([]byte("ID=test\n")))
+			Expect(os.ReadFile(filepath.Join(dest, "big"))).To(Equal(bytes.Repeat([]byte("0123456789"), 100_000)))
+			Expect(os.ReadFile(filepath.Join(dest, "sparse"))).To(Equal(make([]byte, 3*DefaultBlockSize+7)))
+			Expect(os.ReadFile(filepath.Join(dest, "empty"))).To(BeEmpty())
+			Expect(os.Readlink(filepath.Join(dest, "bin"))).To(Equal("usr/bin"))
+
+			tool, err := os.Stat(filepath.Join(dest, "usr", "bin", "tool"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tool.Mode()).To(Equal(0755 | fs.ModeSetuid))
+			alias, err := os.Stat(filepath.Join(dest, "usr", "bin", "alias"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.SameFile(tool, alias)).To(BeTrue())
+		}
+	})
+
+	It("should fail for files", func() {
+		f, err := os.Create(filepath.Join(GinkgoT().TempDir(), "fs.sqfs"))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(f.Close)
+		Expect(WriteDir(f, filepath.Join(srcDir, "big"), WriteOptions{})).To(MatchError(ContainSubstring("not a directory")))
+	})
+})
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/containerd/containerd/remotes"
 	"github.com/ironcore-dev/ironcore-image/oci/image"
@@ -30,6 +31,61 @@ const (
 	LegacySquashFSLayerMediaType  = "application/vnd.ironcore.image.squashfs.v1alpha1.squashfs"
 )
 
+// LayerType is the short, media type independent name of an ironcore image layer.
+type LayerType string
+
+const (
+	KernelLayerType    LayerType = "kernel"
+	RootFSLayerType    LayerType = "rootfs"
+	InitRAMFSLayerType LayerType = "initramfs"
+	SquashFSLayerType  LayerType = "squashfs"
+	UKILayerType       LayerType = "uki"
+	ISOLayerType       LayerType = "iso"
+)
+
+// LayerTypes are all known layer types.
+var LayerTypes = []LayerType{
+	KernelLayerType,
+	InitRAMFSLayerType,
+	RootFSLayerType,
+	SquashFSLayerType,
+	UKILayerType,
+	ISOLayerType,
+}
+
+// layerMediaTypes are the (non-legacy) layer media types. Only these may be compressed.
+var layerMediaTypes = []string{
+	KernelLayerMediaType,
+	RootFSLayerMediaType,
+	InitRAMFSLayerMediaType,
+	SquashFSLayerMediaType,
+	UKILayerMediaType,
+	ISOLayerMediaType,
+}
+
+var mediaTypeToLayerType = map[string]LayerType{
+	KernelLayerMediaType:          KernelLayerType,
+	RootFSLayerMediaType:          RootFSLayerType,
+	InitRAMFSLayerMediaType:       InitRAMFSLayerType,
+	SquashFSLayerMediaType:        SquashFSLayerType,
+	UKILayerMediaType:             UKILayerType,
+	ISOLayerMediaType:             ISOLayerType,
+	LegacyKernelLayerMediaType:    KernelLayerType,
+	LegacyRootFSLayerMediaType:    RootFSLayerType,
+	LegacyInitRAMFSLayerMediaType: InitRAMFSLayerType,
+	LegacySquashFSLayerMediaType:  SquashFSLayerType,
+}
+
+// LayerTypeForMediaType returns the LayerType of the given (possibly legacy or compressed) layer media type.
+func LayerTypeForMediaType(mediaType string) (LayerType, bool) {
+	mediaType, compression := SplitMediaType(mediaType)
+	if compression != CompressionNone && !slices.Contains(layerMediaTypes, mediaType) {
+		return "", false
+	}
+	layerType, ok := mediaTypeToLayerType[mediaType]
+	return layerType, ok
+}
+
 type Config struct {
 	CommandLine string `json:"commandLine,omitempty"`
 }
@@ -63,6 +119,11 @@ func SetupContext(ctx context.Context) context.Context {
 	ctx = remotes.WithMediaTypeKeyPrefix(ctx, SquashFSLayerMediaType, "layer-")
 	ctx = remotes.WithMediaTypeKeyPrefix(ctx, UKILayerMediaType, "layer-")
 	ctx = remotes.WithMediaTypeKeyPrefix(ctx, ISOLayerMediaType, "layer-")
+	for _, mediaType := range layerMediaTypes {
+		for _, c := range Compressions {
+			ctx = remotes.WithMediaTypeKeyPrefix(ctx, CompressedMediaType(mediaType, c), "layer-")
+		}
+	}
 	ctx = remotes.WithMediaTypeKeyPrefix(ctx, LegacyConfigMediaType, "config-")
 	ctx = remotes.WithMediaTypeKeyPrefix(ctx, LegacyRootFSLayerMediaType, "layer-")
 	ctx = remotes.WithMediaTypeKeyPrefix(ctx, LegacyInitRAMFSLayerMediaType, "layer-")
@@ -71,10 +132,42 @@ func SetupContext(ctx context.Context) context.Context {
 	return ctx
 }
 
+// ResolveOptions are options for ResolveImage.
+type ResolveOptions struct {
+	// Verifier, if set, verifies the image before it is resolved.
+	Verifier image.Verifier
+	// ReferrersSource is used by the Verifier to look up the artifacts referring to the image.
+	ReferrersSource image.ReferrersSource
+	// Ref is the reference the image was obtained by.
+	Ref string
+}
+
+// ResolveOption is an option for ResolveImage.
+type ResolveOption func(o *ResolveOptions)
+
+// WithVerifier makes ResolveImage verify the image with v, looking up its signatures in src.
+func WithVerifier(v image.Verifier, src image.ReferrersSource, ref string) ResolveOption {
+	return func(o *ResolveOptions) {
+		o.Verifier = v
+		o.ReferrersSource = src
+		o.Ref = ref
+	}
+}
+
 // ResolveImage resolves an oci image to an ironcore Image.
-func ResolveImage(ctx context.Context, ociImg image.Image) (*Image, error) {
+func ResolveImage(ctx context.Context, ociImg image.Image, opts ...ResolveOption) (*Image, error) {
 	ctx = SetupContext(ctx)
 
+	o := &ResolveOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.Verifier != nil {
+		if err := o.Verifier.Verify(ctx, o.ReferrersSource, o.Ref, ociImg.Descriptor()); err != nil {
+			return nil, fmt.Errorf("error verifying image: %w", err)
+		}
+	}
+
 	config, err := readImageConfig(ctx, ociImg)
 	if err != nil {
 		return nil, err
@@ -87,7 +180,15 @@ func ResolveImage(ctx context.Context, ociImg image.Image) (*Image, error) {
 
 	img := Image{Config: *config}
 	for _, layer := range layers {
-		switch layer.Descriptor().MediaType {
+		mediaType, compression := SplitMediaType(layer.Descriptor().MediaType)
+		if compression != CompressionNone {
+			if !slices.Contains(layerMediaTypes, mediaType) {
+				return nil, fmt.Errorf("unknown layer type %q", layer.Descriptor().MediaType)
+			}
+			layer = &decompressedLayer{Layer: layer, compression: compression}
+		}
+
+		switch mediaType {
 		case InitRAMFSLayerMediaType:
 			img.InitRAMFs = layer
 		case KernelLayerMediaType:
@@ -140,3 +241,23 @@ type Image struct {
 	// ISO is a layer containing a bootable ISO image.
 	ISO image.Layer
 }
+
+// Layer returns the layer of the given type or nil if the image does not contain such a layer.
+func (i *Image) Layer(layerType LayerType) image.Layer {
+	switch layerType {
+	case KernelLayerType:
+		return i.Kernel
+	case RootFSLayerType:
+		return i.RootFS
+	case InitRAMFSLayerType:
+		return i.InitRAMFs
+	case SquashFSLayerType:
+		return i.SquashFS
+	case UKILayerType:
+		return i.UKI
+	case ISOLayerType:
+		return i.ISO
+	default:
+		return nil
+	}
+}
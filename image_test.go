@@ -4,13 +4,19 @@
 package ironcoreimage_test
 
 import (
+	"bytes"
 	"context"
+	"maps"
 
 	. "github.com/ironcore-dev/ironcore-image"
+	ocicontent "github.com/ironcore-dev/ironcore-image/oci/content"
 	"github.com/ironcore-dev/ironcore-image/oci/image"
 	"github.com/ironcore-dev/ironcore-image/oci/imageutil"
+	"github.com/ironcore-dev/ironcore-image/seekable"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 var _ = Describe("Image", func() {
@@ -95,6 +101,113 @@ var _ = Describe("Image", func() {
 			Expect(imageutil.ReadLayerContent(ctx, res.SquashFS)).To(Equal(squashfsData))
 		})
 
+		It("should transparently decompress compressed layers", func() {
+			By("creating an image with a zstd and a gzip compressed layer")
+			compressedLayer := func(mediaType string, c Compression) image.Layer {
+				var buf bytes.Buffer
+				annotations, err := Compress(&buf, bytes.NewReader(rootfsData), c)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(annotations).To(HaveKeyWithValue(UncompressedDigestAnnotation, digest.FromBytes(rootfsData).String()))
+				return imageutil.BytesLayer(buf.Bytes(),
+					imageutil.WithMediaType(CompressedMediaType(mediaType, c)),
+					imageutil.WithAnnotations(annotations),
+				)
+			}
+			layers := []image.Layer{
+				compressedLayer(RootFSLayerMediaType, CompressionZstd),
+				compressedLayer(ISOLayerMediaType, CompressionGzip),
+			}
+			img, err := imageutil.NewBuilder(configLayer).Layers(layers...).Complete()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("resolving the image")
+			res, err := ResolveImage(ctx, img)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.RootFS.Descriptor().MediaType).To(Equal(RootFSLayerMediaType + "+zstd"))
+			Expect(UncompressedDigest(res.RootFS.Descriptor())).To(Equal(digest.FromBytes(rootfsData)))
+
+			By("reading the decompressed layers")
+			Expect(imageutil.ReadLayerContent(ctx, res.RootFS)).To(Equal(rootfsData))
+			Expect(imageutil.ReadLayerContent(ctx, res.ISO)).To(Equal(rootfsData))
+
+			By("rejecting random access to the non-seekable layer")
+			_, err = image.LayerReaderAt(ctx, res.ISO)
+			Expect(err).To(MatchError(ErrNotSeekable))
+		})
+
+		It("should provide random access to seekable layers", func() {
+			data := bytes.Repeat([]byte("rootfs"), 10000)
+			var buf bytes.Buffer
+			annotations, err := CompressSeekable(&buf, bytes.NewReader(data), 1024)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(annotations).To(HaveKeyWithValue(SeekableAnnotation, "zstd"))
+			Expect(annotations).To(HaveKey(SeekableTableDigestAnnotation))
+			layer := imageutil.BytesLayer(buf.Bytes(),
+				imageutil.WithMediaType(CompressedMediaType(RootFSLayerMediaType, CompressionZstd)),
+				imageutil.WithAnnotations(annotations),
+			)
+
+			By("reading the layer sequentially")
+			img, err := imageutil.NewBuilder(configLayer).Layers(layer).Complete()
+			Expect(err).NotTo(HaveOccurred())
+			res, err := ResolveImage(ctx, img)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(imageutil.ReadLayerContent(ctx, res.RootFS)).To(Equal(data))
+
+			By("reading a range of the layer")
+			r, err := UncompressedReaderAt(bytes.NewReader(buf.Bytes()), layer.Descriptor())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Size()).To(Equal(int64(len(data))))
+			p := make([]byte, 3000)
+			_, err = r.ReadAt(p, 5000)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(Equal(data[5000:8000]))
+
+			By("reading a range of the resolved layer")
+			ra, err := image.LayerReaderAt(ctx, res.RootFS)
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = ra.Close() }()
+			Expect(ra.Size()).To(Equal(int64(len(data))))
+			_, err = ra.ReadAt(p, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(Equal(data[100:3100]))
+
+			By("rejecting a different table digest")
+			desc := layer.Descriptor()
+			desc.Annotations = maps.Clone(desc.Annotations)
+			desc.Annotations[SeekableTableDigestAnnotation] = digest.FromString("other").String()
+			_, err = UncompressedReaderAt(bytes.NewReader(buf.Bytes()), desc)
+			Expect(err).To(MatchError(seekable.ErrDigestMismatch))
+
+			By("rejecting seekable layers without table digest")
+			delete(desc.Annotations, SeekableTableDigestAnnotation)
+			_, err = UncompressedReaderAt(bytes.NewReader(buf.Bytes()), desc)
+			Expect(err).To(MatchError(ErrNotSeekable))
+
+			By("rejecting non-seekable compressed layers")
+			_, err = UncompressedReaderAt(bytes.NewReader(buf.Bytes()), ocispec.Descriptor{
+				MediaType: CompressedMediaType(RootFSLayerMediaType, CompressionGzip),
+			})
+			Expect(err).To(MatchError(ErrNotSeekable))
+		})
+
+		It("should error if the uncompressed digest does not match", func() {
+			var buf bytes.Buffer
+			_, err := Compress(&buf, bytes.NewReader(rootfsData), CompressionGzip)
+			Expect(err).NotTo(HaveOccurred())
+			layer := imageutil.BytesLayer(buf.Bytes(),
+				imageutil.WithMediaType(CompressedMediaType(RootFSLayerMediaType, CompressionGzip)),
+				imageutil.WithAnnotations(map[string]string{UncompressedDigestAnnotation: digest.FromString("other").String()}),
+			)
+			img, err := imageutil.NewBuilder(configLayer).Layers(layer).Complete()
+			Expect(err).NotTo(HaveOccurred())
+
+			res, err := ResolveImage(ctx, img)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = imageutil.ReadLayerContent(ctx, res.RootFS)
+			Expect(err).To(MatchError(ocicontent.ErrDigestMismatch))
+		})
+
 		It("should error if the image contains invalid layers", func() {
 			By("creating an image with an additional invalid layer")
 			invalidLayer := imageutil.BytesLayer([]byte("invalid"))
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ironcoreimage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	ocicontent "github.com/ironcore-dev/ironcore-image/oci/content"
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/ironcore-dev/ironcore-image/utils/fsutil"
+)
+
+// MaterializeLayer writes the content of the layer to the file at dst, replacing any existing file.
+//
+// If the layer is an image.LocalLayer, a reflink or hardlink of the backing file is attempted first.
+// Hardlinked files share their data with the backing store and must not be modified.
+// If linking is not possible or digest verification is enabled via ocicontent.WithVerifyDigests,
+// the layer content is copied.
+func MaterializeLayer(ctx context.Context, layer image.Layer, dst string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+"-*")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	_ = tmp.Close()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if err := materializeLayer(ctx, layer, tmpPath); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, dst); err != nil {
+		return fmt.Errorf("error moving layer to %s: %w", dst, err)
+	}
+	return nil
+}
+
+func materializeLayer(ctx context.Context, layer image.Layer, dst string) error {
+	if localLayer, ok := layer.(image.LocalLayer); ok && !ocicontent.VerifyDigests(ctx, false) {
+		if src, err := localLayer.LocalPath(); err == nil {
+			if err := os.Remove(dst); err != nil {
+				return fmt.Errorf("error removing temporary file: %w", err)
+			}
+			if err := fsutil.Clone(src, dst); err == nil {
+				return nil
+			}
+		}
+	}
+
+	rc, err := layer.Content(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting layer content: %w", err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", dst, err)
+	}
+
+	if _, err := io.Copy(f, rc); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("error copying layer content: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error closing %s: %w", dst, err)
+	}
+	return nil
+}
+
+// Materialize writes the layer of the given type to the file at dst.
+// See MaterializeLayer for details on how the file is created.
+func (i *Image) Materialize(ctx context.Context, layerType LayerType, dst string) error {
+	layer := i.Layer(layerType)
+	if layer == nil {
+		return fmt.Errorf("image has no %s layer", layerType)
+	}
+	return MaterializeLayer(ctx, layer, dst)
+}
+
+// MaterializeAll writes all layers of the image into dir, naming each file after its LayerType.
+// It returns the paths of the created files by layer type.
+func (i *Image) MaterializeAll(ctx context.Context, dir string) (map[LayerType]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating directory %s: %w", dir, err)
+	}
+
+	paths := make(map[LayerType]string)
+	for _, layerType := range LayerTypes {
+		layer := i.Layer(layerType)
+		if layer == nil {
+			continue
+		}
+
+		dst := filepath.Join(dir, string(layerType))
+		if err := MaterializeLayer(ctx, layer, dst); err != nil {
+			return nil, fmt.Errorf("error materializing %s layer: %w", layerType, err)
+		}
+		paths[layerType] = dst
+	}
+	return paths, nil
+}
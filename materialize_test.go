@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ironcoreimage_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/ironcore-dev/ironcore-image"
+	ocicontent "github.com/ironcore-dev/ironcore-image/oci/content"
+	"github.com/ironcore-dev/ironcore-image/oci/imageutil"
+	"github.com/ironcore-dev/ironcore-image/utils/fsutil"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Materialize", func() {
+	var (
+		ctx    context.Context
+		tmpDir string
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		tmpDir = GinkgoT().TempDir()
+	})
+
+	It("should copy in-memory layers", func() {
+		img := &Image{Kernel: imageutil.BytesLayer([]byte("kernel"), imageutil.WithMediaType(KernelLayerMediaType))}
+
+		dst := filepath.Join(tmpDir, "vmlinuz")
+		Expect(img.Materialize(ctx, KernelLayerType, dst)).To(Succeed())
+		Expect(os.ReadFile(dst)).To(Equal([]byte("kernel")))
+	})
+
+	It("should link file backed layers", func() {
+		src := filepath.Join(tmpDir, "src")
+		Expect(os.WriteFile(src, []byte("initramfs"), 0644)).To(Succeed())
+		layer, err := imageutil.FileLayer(src, imageutil.WithMediaType(InitRAMFSLayerMediaType))
+		Expect(err).NotTo(HaveOccurred())
+		img := &Image{InitRAMFs: layer}
+
+		paths, err := img.MaterializeAll(ctx, filepath.Join(tmpDir, "out"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(paths).To(Equal(map[LayerType]string{
+			InitRAMFSLayerType: filepath.Join(tmpDir, "out", "initramfs"),
+		}))
+		Expect(os.ReadFile(paths[InitRAMFSLayerType])).To(Equal([]byte("initramfs")))
+
+		// Reflinks are preferred and are separate files sharing their data.
+		if fsutil.Reflink(src, filepath.Join(tmpDir, "reflink")) == nil {
+			Skip("reflinks are supported, the layer is not hardlinked")
+		}
+		srcInfo, err := os.Stat(src)
+		Expect(err).NotTo(HaveOccurred())
+		dstInfo, err := os.Stat(paths[InitRAMFSLayerType])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.SameFile(srcInfo, dstInfo)).To(BeTrue())
+	})
+
+	It("should copy file backed layers if digests are verified", func() {
+		src := filepath.Join(tmpDir, "src")
+		Expect(os.WriteFile(src, []byte("initramfs"), 0644)).To(Succeed())
+		layer, err := imageutil.FileLayer(src, imageutil.WithMediaType(InitRAMFSLayerMediaType))
+		Expect(err).NotTo(HaveOccurred())
+		img := &Image{InitRAMFs: layer}
+
+		dst := filepath.Join(tmpDir, "initramfs")
+		Expect(img.Materialize(ocicontent.WithVerifyDigests(ctx, true), InitRAMFSLayerType, dst)).To(Succeed())
+		Expect(os.ReadFile(dst)).To(Equal([]byte("initramfs")))
+
+		srcInfo, err := os.Stat(src)
+		Expect(err).NotTo(HaveOccurred())
+		dstInfo, err := os.Stat(dst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.SameFile(srcInfo, dstInfo)).To(BeFalse())
+	})
+
+	It("should error if the layer is missing", func() {
+		img := &Image{}
+		Expect(img.Materialize(ctx, UKILayerType, filepath.Join(tmpDir, "uki"))).NotTo(Succeed())
+	})
+})
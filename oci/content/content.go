@@ -12,6 +12,7 @@ import (
 	"github.com/containerd/containerd/content"
 	"github.com/containerd/containerd/remotes"
 	ociimage "github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
@@ -70,16 +71,51 @@ func (o *layer) Descriptor() ocispec.Descriptor {
 	return o.descriptor
 }
 
+// Content returns the layer content. It is only verified against the layer digest if enabled via WithVerifyDigests.
 func (o *layer) Content(ctx context.Context) (io.ReadCloser, error) {
 	readerAt, err := o.provider.ReaderAt(ctx, o.descriptor)
 	if err != nil {
 		return nil, err
 	}
 
-	return ReaderAtReadCloser(readerAt), nil
+	rc := ReaderAtReadCloser(readerAt)
+	if !VerifyDigests(ctx, false) {
+		return rc, nil
+	}
+
+	vr, err := NewDescriptorVerifyingReader(rc, o.descriptor)
+	if err != nil {
+		_ = rc.Close()
+		return nil, err
+	}
+	return vr, nil
+}
+
+func (o *layer) ReaderAt(ctx context.Context) (ociimage.ReaderAt, error) {
+	return o.provider.ReaderAt(ctx, o.descriptor)
+}
+
+// BlobPather is a content.Provider that can report the local file system path of its blobs.
+type BlobPather interface {
+	content.Provider
+	BlobPath(dgst digest.Digest) (string, error)
 }
 
+type localLayer struct {
+	layer
+	pather BlobPather
+}
+
+func (l *localLayer) LocalPath() (string, error) {
+	return l.pather.BlobPath(l.descriptor.Digest)
+}
+
+// Layer returns an ociimage.RangeLayer for the given descriptor served by the provider.
+// If the provider is a BlobPather, the returned layer additionally implements ociimage.LocalLayer.
 func Layer(provider content.Provider, descriptor ocispec.Descriptor) ociimage.Layer {
+	if pather, ok := provider.(BlobPather); ok {
+		return &localLayer{layer{provider, descriptor}, pather}
+	}
 	return &layer{provider, descriptor}
 }
 
@@ -161,8 +197,13 @@ func (i *indexImage) Config(ctx context.Context) (ociimage.Layer, error) {
 	return nil, fmt.Errorf("index manifests do not have a config layer")
 }
 
+// IndexManifester is implemented by images that are image indices.
+type IndexManifester interface {
+	IndexManifest(ctx context.Context) (*ocispec.Index, error)
+}
+
 func GetIndexManifest(ctx context.Context, img ociimage.Image) (*ocispec.Index, error) {
-	indexImg, ok := img.(*indexImage)
+	indexImg, ok := img.(IndexManifester)
 	if !ok {
 		return nil, fmt.Errorf("image is not an index image")
 	}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package content
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+var (
+	// ErrDigestMismatch is returned by a VerifyingReader if the content does not match the expected digest.
+	ErrDigestMismatch = errors.New("digest mismatch")
+	// ErrSizeMismatch is returned by a VerifyingReader if the content does not match the expected size.
+	ErrSizeMismatch = errors.New("size mismatch")
+)
+
+// VerifyingReader hashes content while it is read and returns an error at EOF if the content
+// does not match the expected digest or size. Reading more than the expected size fails immediately.
+type VerifyingReader struct {
+	r        io.Reader
+	closer   io.Closer
+	expected digest.Digest
+	hash     hash.Hash
+	size     int64
+	n        int64
+	err      error
+}
+
+// NewVerifyingReader returns a VerifyingReader of rc expecting content with the given digest and size.
+// A negative size disables the size check.
+func NewVerifyingReader(rc io.ReadCloser, expected digest.Digest, size int64) (*VerifyingReader, error) {
+	if err := expected.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid digest %q: %w", expected, err)
+	}
+	return &VerifyingReader{
+		r:        rc,
+		closer:   rc,
+		expected: expected,
+		hash:     expected.Algorithm().Hash(),
+		size:     size,
+	}, nil
+}
+
+// NewDescriptorVerifyingReader returns a VerifyingReader of rc expecting the digest and size of desc.
+func NewDescriptorVerifyingReader(rc io.ReadCloser, desc ocispec.Descriptor) (*VerifyingReader, error) {
+	return NewVerifyingReader(rc, desc.Digest, desc.Size)
+}
+
+func (v *VerifyingReader) Read(p []byte) (int, error) {
+	if v.err != nil {
+		return 0, v.err
+	}
+
+	n, err := v.r.Read(p)
+	v.n += int64(n)
+	_, _ = v.hash.Write(p[:n])
+
+	if v.size >= 0 && v.n > v.size {
+		v.err = fmt.Errorf("%w: content of %s exceeds %d bytes", ErrSizeMismatch, v.expected, v.size)
+		return n, v.err
+	}
+	if err == io.EOF {
+		if v.size >= 0 && v.n != v.size {
+			v.err = fmt.Errorf("%w: content of %s has %d bytes, expected %d", ErrSizeMismatch, v.expected, v.n, v.size)
+			return n, v.err
+		}
+		if actual := digest.NewDigest(v.expected.Algorithm(), v.hash); actual != v.expected {
+			v.err = fmt.Errorf("%w: content has digest %s, expected %s", ErrDigestMismatch, actual, v.expected)
+			return n, v.err
+		}
+	}
+	return n, err
+}
+
+func (v *VerifyingReader) Close() error {
+	return v.closer.Close()
+}
+
+type verifyDigestsKey struct{}
+
+// WithVerifyDigests returns a context that enables or disables digest verification of layer content
+// read with it. By default, remote content is verified while local content is trusted.
+// Random access reads via ociimage.RangeLayer are never verified.
+func WithVerifyDigests(ctx context.Context, verify bool) context.Context {
+	return context.WithValue(ctx, verifyDigestsKey{}, verify)
+}
+
+// VerifyDigests reports whether content read with ctx should be verified.
+// If not configured via WithVerifyDigests, defaultValue is returned.
+func VerifyDigests(ctx context.Context, defaultValue bool) bool {
+	if verify, ok := ctx.Value(verifyDigestsKey{}).(bool); ok {
+		return verify
+	}
+	return defaultValue
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package content_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	. "github.com/ironcore-dev/ironcore-image/oci/content"
+	"github.com/ironcore-dev/ironcore-image/oci/imageutil"
+	"github.com/ironcore-dev/ironcore-image/oci/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
+)
+
+var _ = Describe("VerifyingReader", func() {
+	data := []byte("content")
+
+	read := func(content []byte, dgst digest.Digest, size int64) ([]byte, error) {
+		vr, err := NewVerifyingReader(io.NopCloser(bytes.NewReader(content)), dgst, size)
+		Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = vr.Close() }()
+		return io.ReadAll(vr)
+	}
+
+	It("should read matching content", func() {
+		Expect(read(data, digest.FromBytes(data), int64(len(data)))).To(Equal(data))
+		Expect(read(data, digest.FromBytes(data), -1)).To(Equal(data))
+	})
+
+	It("should error on a digest mismatch", func() {
+		_, err := read([]byte("CONTENT"), digest.FromBytes(data), int64(len(data)))
+		Expect(err).To(MatchError(ErrDigestMismatch))
+	})
+
+	It("should error on a size mismatch", func() {
+		_, err := read(append(data, '!'), digest.FromBytes(data), int64(len(data)))
+		Expect(err).To(MatchError(ErrSizeMismatch))
+
+		_, err = read(data[:3], digest.FromBytes(data), int64(len(data)))
+		Expect(err).To(MatchError(ErrSizeMismatch))
+	})
+
+	It("should reject invalid digests", func() {
+		_, err := NewVerifyingReader(io.NopCloser(bytes.NewReader(data)), "invalid", 0)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should verify local content if enabled", func() {
+		ctx := context.Background()
+		s, err := store.NewMemory()
+		Expect(err).NotTo(HaveOccurred())
+
+		img, err := imageutil.NewBytesConfigBuilder([]byte("{}")).BytesLayer(data).Complete()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Push(ctx, "example.org/foo:bar", img)).To(Succeed())
+
+		res, err := s.Resolve(ctx, "example.org/foo:bar")
+		Expect(err).NotTo(HaveOccurred())
+		layers, err := res.Layers(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("reading unverified content by default")
+		rc, err := layers[0].Content(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rc).NotTo(BeAssignableToTypeOf(&VerifyingReader{}))
+		_ = rc.Close()
+
+		By("reading verified content")
+		rc, err = layers[0].Content(WithVerifyDigests(ctx, true))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rc).To(BeAssignableToTypeOf(&VerifyingReader{}))
+		Expect(io.ReadAll(rc)).To(Equal(data))
+		_ = rc.Close()
+	})
+})
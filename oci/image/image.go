@@ -5,9 +5,13 @@ package image
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 
+	"github.com/containerd/containerd/remotes"
+
+	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
@@ -16,6 +20,43 @@ type Layer interface {
 	Content(ctx context.Context) (io.ReadCloser, error)
 }
 
+// LocalLayer is a Layer whose content is available as a file on the local file system.
+type LocalLayer interface {
+	Layer
+	// LocalPath returns the path of the file holding the layer content.
+	// The file must not be modified by the caller.
+	LocalPath() (string, error)
+}
+
+// ReaderAt is random access to the content of a layer.
+type ReaderAt interface {
+	io.ReaderAt
+	io.Closer
+	// Size returns the size of the content.
+	Size() int64
+}
+
+// RangeLayer is a Layer supporting random access to its content, e.g. to read headers or
+// partition tables without streaming the whole blob.
+type RangeLayer interface {
+	Layer
+	// ReaderAt returns random access to the layer content. The ReaderAt has to be closed after use.
+	ReaderAt(ctx context.Context) (ReaderAt, error)
+}
+
+// ErrRangeNotSupported is returned for layers that do not support random access to their content.
+var ErrRangeNotSupported = errors.New("layer does not support random access")
+
+// LayerReaderAt returns random access to the content of layer if it is a RangeLayer.
+// Otherwise, it returns ErrRangeNotSupported.
+func LayerReaderAt(ctx context.Context, layer Layer) (ReaderAt, error) {
+	rangeLayer, ok := layer.(RangeLayer)
+	if !ok {
+		return nil, fmt.Errorf("%w: layer %s", ErrRangeNotSupported, layer.Descriptor().Digest)
+	}
+	return rangeLayer.ReaderAt(ctx)
+}
+
 type Image interface {
 	Layer
 	Manifest(ctx context.Context) (*ocispec.Manifest, error)
@@ -58,3 +99,144 @@ func Copy(ctx context.Context, dst Sink, src Source, ref string) (Image, error)
 	}
 	return img, nil
 }
+
+// IndexSource resolves references without selecting a platform.
+type IndexSource interface {
+	// Get returns the image manifest or image index referenced by ref.
+	Get(ctx context.Context, ref string) (Image, error)
+}
+
+// ReferrersCopySource is a Source that can also resolve image indexes and list referrers.
+type ReferrersCopySource interface {
+	Source
+	IndexSource
+	ReferrersSource
+}
+
+// ReferrersCopySink is a Sink that can also store referrers.
+type ReferrersCopySink interface {
+	Sink
+	ReferrersSink
+}
+
+// CopyWithReferrers copies the image referenced by ref like Copy along with the artifacts referring to it.
+// If ref references an index, the referrers of the index (e.g. its provenance or signatures) are copied
+// besides those of the manifest selected from it. The copied referrers are returned.
+func CopyWithReferrers(ctx context.Context, dst ReferrersCopySink, src ReferrersCopySource, ref string) (Image, []ocispec.Descriptor, error) {
+	top, err := src.Get(ctx, ref)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting ref %s: %w", ref, err)
+	}
+
+	img, err := Copy(ctx, dst, src, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subjects := []digest.Digest{img.Descriptor().Digest}
+	if top.Descriptor().Digest != img.Descriptor().Digest {
+		subjects = append(subjects, top.Descriptor().Digest)
+	}
+	referrers, err := CopyReferrers(ctx, dst, src, ref, subjects...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return img, referrers, nil
+}
+
+// ReferrersSource lists and resolves the referrers of manifests in the repository of a reference.
+type ReferrersSource interface {
+	// Referrers lists the manifests referring to subject. If artifactType is not empty,
+	// only referrers of that artifact type are returned.
+	Referrers(ctx context.Context, ref string, subject digest.Digest, artifactType string) ([]ocispec.Descriptor, error)
+	// Referrer returns the referrer manifest described by desc.
+	Referrer(ctx context.Context, ref string, desc ocispec.Descriptor) (Image, error)
+}
+
+// ReferrersSink stores referrers in the repository of a reference.
+type ReferrersSink interface {
+	PushReferrer(ctx context.Context, ref string, img Image) error
+}
+
+// ErrUnsigned is returned by a Verifier if the image has no signatures at all.
+var ErrUnsigned = errors.New("image is not signed")
+
+// Verifier verifies an image before it is used, e.g. by checking its signatures.
+type Verifier interface {
+	// Verify verifies the image described by desc and referenced by ref.
+	// src is used to look up artifacts referring to the image, such as signatures.
+	Verify(ctx context.Context, src ReferrersSource, ref string, desc ocispec.Descriptor) error
+}
+
+// ReferrerDescriptor returns the descriptor of a referrer manifest as listed by the referrers API,
+// i.e. including its artifact type and annotations.
+func ReferrerDescriptor(desc ocispec.Descriptor, manifest *ocispec.Manifest) ocispec.Descriptor {
+	artifactType := manifest.ArtifactType
+	if artifactType == "" {
+		artifactType = manifest.Config.MediaType
+	}
+
+	return ocispec.Descriptor{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: artifactType,
+		Digest:       desc.Digest,
+		Size:         desc.Size,
+		Annotations:  manifest.Annotations,
+	}
+}
+
+// WithManifestKeyPrefixes registers the media types of the config and layers of the manifest
+// with the context, so transferring arbitrary artifacts does not log warnings on unknown media types.
+func WithManifestKeyPrefixes(ctx context.Context, manifest *ocispec.Manifest) context.Context {
+	ctx = remotes.WithMediaTypeKeyPrefix(ctx, manifest.Config.MediaType, "config-")
+	for _, layer := range manifest.Layers {
+		ctx = remotes.WithMediaTypeKeyPrefix(ctx, layer.MediaType, "layer-")
+	}
+	return ctx
+}
+
+// CopyReferrers copies all referrers of the given subjects from src to dst, including
+// referrers of referrers (e.g. signatures of an SBOM). The copied descriptors are returned.
+func CopyReferrers(ctx context.Context, dst ReferrersSink, src ReferrersSource, ref string, subjects ...digest.Digest) ([]ocispec.Descriptor, error) {
+	var (
+		res     []ocispec.Descriptor
+		visited = make(map[digest.Digest]struct{})
+		queue   = subjects
+	)
+	for len(queue) > 0 {
+		subject := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[subject]; ok {
+			continue
+		}
+		visited[subject] = struct{}{}
+
+		descs, err := src.Referrers(ctx, ref, subject, "")
+		if err != nil {
+			return nil, fmt.Errorf("error listing referrers of %s: %w", subject, err)
+		}
+
+		for _, desc := range descs {
+			if _, ok := visited[desc.Digest]; ok {
+				continue
+			}
+
+			img, err := src.Referrer(ctx, ref, desc)
+			if err != nil {
+				return nil, fmt.Errorf("error getting referrer %s: %w", desc.Digest, err)
+			}
+
+			manifest, err := img.Manifest(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("error reading referrer %s: %w", desc.Digest, err)
+			}
+			if err := dst.PushReferrer(WithManifestKeyPrefixes(ctx, manifest), ref, img); err != nil {
+				return nil, fmt.Errorf("error pushing referrer %s: %w", desc.Digest, err)
+			}
+
+			res = append(res, desc)
+			queue = append(queue, desc.Digest)
+		}
+	}
+	return res, nil
+}
@@ -17,9 +17,12 @@ import (
 )
 
 type Builder struct {
-	err    error
-	config image.Layer
-	layers []image.Layer
+	err          error
+	config       image.Layer
+	layers       []image.Layer
+	subject      *ocispec.Descriptor
+	artifactType string
+	annotations  map[string]string
 }
 
 func NewBuilder(config image.Layer) *Builder {
@@ -74,6 +77,40 @@ func (b *Builder) Layers(layers ...image.Layer) *Builder {
 	return b
 }
 
+// Subject sets the subject of the manifest, making the built image a referrer of the subject.
+func (b *Builder) Subject(subject ocispec.Descriptor) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	b.subject = &ocispec.Descriptor{
+		MediaType: subject.MediaType,
+		Digest:    subject.Digest,
+		Size:      subject.Size,
+	}
+	return b
+}
+
+// ArtifactType sets the artifact type of the manifest.
+func (b *Builder) ArtifactType(artifactType string) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	b.artifactType = artifactType
+	return b
+}
+
+// Annotations sets the annotations of the manifest.
+func (b *Builder) Annotations(annotations map[string]string) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	b.annotations = annotations
+	return b
+}
+
 func (b *Builder) Complete(opts ...DescriptorOpt) (image.Image, error) {
 	if b.err != nil {
 		return nil, b.err
@@ -91,8 +128,16 @@ func (b *Builder) Complete(opts ...DescriptorOpt) (image.Image, error) {
 		Versioned: specs.Versioned{
 			SchemaVersion: 2,
 		},
-		Config: b.config.Descriptor(),
-		Layers: layerDescriptors,
+		Config:       b.config.Descriptor(),
+		Layers:       layerDescriptors,
+		Subject:      b.subject,
+		ArtifactType: b.artifactType,
+		Annotations:  b.annotations,
+	}
+	if b.subject != nil || b.artifactType != "" {
+		// Referrers are required to state their media type, other manifests keep
+		// omitting it so their digests stay stable.
+		manifest.MediaType = ocispec.MediaTypeImageManifest
 	}
 	data, err := json.Marshal(manifest)
 	if err != nil {
@@ -149,6 +194,13 @@ func (c *composite) Layers(ctx context.Context) ([]image.Layer, error) {
 	return c.layers, nil
 }
 
+// NewArtifactBuilder returns a Builder for an artifact manifest of the given artifact type
+// using the empty config, as recommended for artifacts without configuration.
+func NewArtifactBuilder(artifactType string) *Builder {
+	return NewBytesConfigBuilder(ocispec.DescriptorEmptyJSON.Data, WithMediaType(ocispec.MediaTypeEmptyJSON)).
+		ArtifactType(artifactType)
+}
+
 func NewIndexImage(index ocispec.Index) (image.Image, error) {
 	data, err := json.Marshal(index)
 	if err != nil {
@@ -198,6 +250,11 @@ func (i *indexImage) Index(ctx context.Context) (ocispec.Index, error) {
 	return i.index, nil
 }
 
+func (i *indexImage) IndexManifest(ctx context.Context) (*ocispec.Index, error) {
+	index := i.index
+	return &index, nil
+}
+
 func (i *indexImage) Config(ctx context.Context) (image.Layer, error) {
 	return i.config, nil
 }
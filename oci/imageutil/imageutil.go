@@ -58,7 +58,20 @@ func (b *bytesLayer) Content(ctx context.Context) (io.ReadCloser, error) {
 	return io.NopCloser(bytes.NewReader(b.data)), nil
 }
 
+func (b *bytesLayer) ReaderAt(ctx context.Context) (image.ReaderAt, error) {
+	return nopCloserReaderAt{bytes.NewReader(b.data)}, nil
+}
+
+type nopCloserReaderAt struct {
+	*bytes.Reader
+}
+
+func (nopCloserReaderAt) Close() error {
+	return nil
+}
+
 // BytesLayer creates a new image.Layer from the given data.
+// The returned layer implements image.RangeLayer.
 // The descriptor digest will be overwritten with the digest obtained from the bytes.
 // The descriptor size will be overwritten with the length of the data.
 func BytesLayer(data []byte, opts ...DescriptorOpt) image.Layer {
@@ -83,10 +96,39 @@ func (f *fileLayer) Content(ctx context.Context) (io.ReadCloser, error) {
 	return os.Open(f.path)
 }
 
+func (f *fileLayer) ReaderAt(ctx context.Context) (image.ReaderAt, error) {
+	fp, err := os.Open(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := fp.Stat()
+	if err != nil {
+		_ = fp.Close()
+		return nil, fmt.Errorf("error statting file: %w", err)
+	}
+	return &fileReaderAt{fp, stat.Size()}, nil
+}
+
+type fileReaderAt struct {
+	*os.File
+	size int64
+}
+
+func (f *fileReaderAt) Size() int64 {
+	return f.size
+}
+
 func (f *fileLayer) Descriptor() ocispec.Descriptor {
 	return f.desc
 }
 
+func (f *fileLayer) LocalPath() (string, error) {
+	return f.path, nil
+}
+
+// FileLayer creates a new image.Layer of the file at path, computing its digest and size.
+// The returned layer implements image.LocalLayer and image.RangeLayer.
 func FileLayer(path string, opts ...DescriptorOpt) (image.Layer, error) {
 	desc := ocispec.Descriptor{}
 	for _, opt := range opts {
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/ironcore-dev/ironcore-image/oci/descriptormatcher"
 	"github.com/opencontainers/image-spec/specs-go"
@@ -20,12 +21,51 @@ const Filename = "index.json"
 
 var ErrNotFound = errors.New("not found")
 
-type Indexer struct {
+// storage persists the raw index data.
+type storage interface {
+	read() ([]byte, error)
+	write(data []byte) error
+}
+
+type fileStorage struct {
 	path string
 }
 
+func (s *fileStorage) read() ([]byte, error) {
+	return os.ReadFile(s.path)
+}
+
+func (s *fileStorage) write(data []byte) error {
+	return os.WriteFile(s.path, data, 0666)
+}
+
+type memoryStorage struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+func (s *memoryStorage) read() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data == nil {
+		return nil, os.ErrNotExist
+	}
+	return s.data, nil
+}
+
+func (s *memoryStorage) write(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = data
+	return nil
+}
+
+type Indexer struct {
+	storage storage
+}
+
 func (f *Indexer) readIndex() (*ocispec.Index, error) {
-	data, err := os.ReadFile(f.path)
+	data, err := f.storage.read()
 	if err != nil {
 		return nil, fmt.Errorf("error reading index file: %w", err)
 	}
@@ -44,7 +84,7 @@ func (f *Indexer) writeIndex(index *ocispec.Index) error {
 		return fmt.Errorf("could not convert index to json: %w", err)
 	}
 
-	if err := os.WriteFile(f.path, data, 0666); err != nil {
+	if err := f.storage.write(data); err != nil {
 		return fmt.Errorf("error writing index: %w", err)
 	}
 
@@ -136,8 +176,17 @@ func New(path string) (*Indexer, error) {
 		return nil, fmt.Errorf("error creating base directory: %w", err)
 	}
 
+	return newIndexer(&fileStorage{path: path})
+}
+
+// NewMemory returns a new Indexer that keeps the index in memory only.
+func NewMemory() (*Indexer, error) {
+	return newIndexer(&memoryStorage{})
+}
+
+func newIndexer(storage storage) (*Indexer, error) {
 	indexer := &Indexer{
-		path: path,
+		storage: storage,
 	}
 	if _, err := indexer.readIndex(); err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
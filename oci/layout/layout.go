@@ -20,11 +20,12 @@ import (
 	ociimage "github.com/ironcore-dev/ironcore-image/oci/image"
 	"github.com/ironcore-dev/ironcore-image/oci/indexer"
 	"github.com/ironcore-dev/ironcore-image/oci/local"
+	"github.com/ironcore-dev/ironcore-image/oci/memory"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 type Layout struct {
-	store   *local.Store
+	store   content.Store
 	indexer *indexer.Indexer
 }
 
@@ -120,7 +121,14 @@ func (l *Layout) Indexer() *indexer.Indexer {
 }
 
 // Store returns the backing local.Store of the oci layout.
+// If the layout is not backed by the local file system, nil is returned.
 func (l *Layout) Store() *local.Store {
+	s, _ := l.store.(*local.Store)
+	return s
+}
+
+// ContentStore returns the backing content.Store of the oci layout.
+func (l *Layout) ContentStore() content.Store {
 	return l.store
 }
 
@@ -147,3 +155,16 @@ func New(path string) (*Layout, error) {
 		store:   store,
 	}, nil
 }
+
+// NewMemory returns a new oci layout that is entirely held in memory.
+func NewMemory() (*Layout, error) {
+	index, err := indexer.NewMemory()
+	if err != nil {
+		return nil, fmt.Errorf("error creating indexer: %w", err)
+	}
+
+	return &Layout{
+		indexer: index,
+		store:   memory.NewStore(),
+	}, nil
+}
@@ -0,0 +1,334 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package lease
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ironcore-dev/ironcore-image/utils/fsutil"
+	"github.com/ironcore-dev/ironcore-image/utils/sets"
+	"github.com/opencontainers/go-digest"
+)
+
+const Filename = "leases.json"
+
+var (
+	ErrNotFound      = errors.New("lease not found")
+	ErrAlreadyExists = errors.New("lease already exists")
+)
+
+// Lease pins content against removal while it is in use.
+type Lease struct {
+	// ID is the unique name of the lease.
+	ID string `json:"id"`
+	// CreatedAt is the time the lease was created at.
+	CreatedAt time.Time `json:"createdAt"`
+	// ExpiresAt is the time after which the lease no longer pins its resources.
+	// If unset, the lease never expires.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// Labels hold additional information about the lease.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Resources are the digests of all content pinned by the lease.
+	Resources []digest.Digest `json:"resources,omitempty"`
+}
+
+// Expired reports whether the lease is expired at the given time.
+func (l Lease) Expired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+type leases struct {
+	Leases []Lease `json:"leases"`
+}
+
+// storage persists the raw lease data.
+type storage interface {
+	read() ([]byte, error)
+	write(data []byte) error
+	// lock locks the storage against concurrent updates, also by other processes.
+	lock() (unlock func() error, err error)
+}
+
+type fileStorage struct {
+	path string
+}
+
+func (s *fileStorage) read() ([]byte, error) {
+	return os.ReadFile(s.path)
+}
+
+func (s *fileStorage) write(data []byte) error {
+	return fsutil.WriteFileAtomic(s.path, data, 0644)
+}
+
+func (s *fileStorage) lock() (func() error, error) {
+	return fsutil.Lock(s.path + ".lock")
+}
+
+type memoryStorage struct {
+	data []byte
+}
+
+func (s *memoryStorage) read() ([]byte, error) {
+	if s.data == nil {
+		return nil, os.ErrNotExist
+	}
+	return s.data, nil
+}
+
+func (s *memoryStorage) write(data []byte) error {
+	s.data = data
+	return nil
+}
+
+func (s *memoryStorage) lock() (func() error, error) {
+	return func() error { return nil }, nil
+}
+
+// Manager manages leases.
+type Manager struct {
+	// mu serializes access within the process, the storage lock across processes.
+	mu      sync.Mutex
+	storage storage
+}
+
+func (m *Manager) readLeases() (*leases, error) {
+	data, err := m.storage.read()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &leases{}, nil
+		}
+		return nil, fmt.Errorf("error reading leases file: %w", err)
+	}
+
+	l := &leases{}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("error reading leases: %w", err)
+	}
+	return l, nil
+}
+
+func (m *Manager) writeLeases(l *leases) error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("could not convert leases to json: %w", err)
+	}
+
+	if err := m.storage.write(data); err != nil {
+		return fmt.Errorf("error writing leases: %w", err)
+	}
+	return nil
+}
+
+// update applies fn to the leases. The storage is locked while reading and writing the leases,
+// so updates of other processes are not lost.
+func (m *Manager) update(fn func(l *leases) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	unlock, err := m.storage.lock()
+	if err != nil {
+		return fmt.Errorf("error locking leases: %w", err)
+	}
+	defer func() { _ = unlock() }()
+
+	l, err := m.readLeases()
+	if err != nil {
+		return err
+	}
+	if err := fn(l); err != nil {
+		return err
+	}
+	return m.writeLeases(l)
+}
+
+// Opt is an option for creating a Lease.
+type Opt func(l *Lease)
+
+// WithID sets the ID of the lease. If unset, a random ID is generated.
+func WithID(id string) Opt {
+	return func(l *Lease) {
+		l.ID = id
+	}
+}
+
+// WithExpiration makes the lease expire after the given duration.
+func WithExpiration(d time.Duration) Opt {
+	return func(l *Lease) {
+		expiresAt := l.CreatedAt.Add(d)
+		l.ExpiresAt = &expiresAt
+	}
+}
+
+// WithLabels sets the labels of the lease.
+func WithLabels(labels map[string]string) Opt {
+	return func(l *Lease) {
+		l.Labels = labels
+	}
+}
+
+// WithResources sets the initial resources of the lease.
+func WithResources(resources ...digest.Digest) Opt {
+	return func(l *Lease) {
+		l.Resources = resources
+	}
+}
+
+// Pinned returns all digests pinned by leases that are not expired.
+func (m *Manager) Pinned(ctx context.Context) (sets.Set[digest.Digest], error) {
+	leases, err := m.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	pinned := sets.New[digest.Digest]()
+	for _, lease := range leases {
+		if !lease.Expired(now) {
+			pinned.Insert(lease.Resources...)
+		}
+	}
+	return pinned, nil
+}
+
+// DeleteExpired deletes all expired leases and returns their IDs.
+func (m *Manager) DeleteExpired(ctx context.Context) ([]string, error) {
+	var expired []string
+	if err := m.update(func(l *leases) error {
+		now := time.Now()
+		remaining := l.Leases[:0]
+		for _, lease := range l.Leases {
+			if lease.Expired(now) {
+				expired = append(expired, lease.ID)
+				continue
+			}
+			remaining = append(remaining, lease)
+		}
+		l.Leases = remaining
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+func randomID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating lease id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Create creates a new lease.
+func (m *Manager) Create(ctx context.Context, opts ...Opt) (Lease, error) {
+	lease := Lease{CreatedAt: time.Now().UTC()}
+	for _, opt := range opts {
+		opt(&lease)
+	}
+	if lease.ID == "" {
+		id, err := randomID()
+		if err != nil {
+			return Lease{}, err
+		}
+		lease.ID = id
+	}
+
+	if err := m.update(func(l *leases) error {
+		for _, existing := range l.Leases {
+			if existing.ID == lease.ID {
+				return fmt.Errorf("%w: %s", ErrAlreadyExists, lease.ID)
+			}
+		}
+		l.Leases = append(l.Leases, lease)
+		return nil
+	}); err != nil {
+		return Lease{}, err
+	}
+	return lease, nil
+}
+
+// Get returns the lease with the given ID.
+func (m *Manager) Get(ctx context.Context, id string) (Lease, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l, err := m.readLeases()
+	if err != nil {
+		return Lease{}, err
+	}
+	for _, lease := range l.Leases {
+		if lease.ID == id {
+			return lease, nil
+		}
+	}
+	return Lease{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// List returns all leases.
+func (m *Manager) List(ctx context.Context) ([]Lease, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l, err := m.readLeases()
+	if err != nil {
+		return nil, err
+	}
+	return l.Leases, nil
+}
+
+// AddResources adds the given digests to the lease with the given ID.
+func (m *Manager) AddResources(ctx context.Context, id string, resources ...digest.Digest) error {
+	return m.update(func(l *leases) error {
+		for i := range l.Leases {
+			if l.Leases[i].ID != id {
+				continue
+			}
+
+			for _, resource := range resources {
+				if !slices.Contains(l.Leases[i].Resources, resource) {
+					l.Leases[i].Resources = append(l.Leases[i].Resources, resource)
+				}
+			}
+			return nil
+		}
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	})
+}
+
+// Delete deletes the lease with the given ID.
+func (m *Manager) Delete(ctx context.Context, id string) error {
+	return m.update(func(l *leases) error {
+		for i, lease := range l.Leases {
+			if lease.ID == id {
+				l.Leases = append(l.Leases[:i], l.Leases[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	})
+}
+
+// New returns a new Manager persisting leases in the file at path.
+func New(path string) (*Manager, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("error creating base directory: %w", err)
+	}
+	return &Manager{storage: &fileStorage{path: path}}, nil
+}
+
+// NewMemory returns a new Manager that keeps leases in memory only.
+func NewMemory() *Manager {
+	return &Manager{storage: &memoryStorage{}}
+}
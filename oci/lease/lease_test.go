@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package lease_test
+
+import (
+	"path/filepath"
+	"sync"
+
+	. "github.com/ironcore-dev/ironcore-image/oci/lease"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Manager", func() {
+	It("should not lose updates of managers sharing a file", func(ctx SpecContext) {
+		path := filepath.Join(GinkgoT().TempDir(), Filename)
+
+		// Managers of the same file stand in for different processes, as each has its own mutex.
+		const managers, leasesPerManager = 4, 25
+		var wg sync.WaitGroup
+		for range managers {
+			m, err := New(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			wg.Go(func() {
+				defer GinkgoRecover()
+				for range leasesPerManager {
+					_, err := m.Create(ctx)
+					Expect(err).NotTo(HaveOccurred())
+					_, err = m.List(ctx)
+					Expect(err).NotTo(HaveOccurred())
+				}
+			})
+		}
+		wg.Wait()
+
+		m, err := New(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.List(ctx)).To(HaveLen(managers * leasesPerManager))
+	})
+})
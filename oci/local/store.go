@@ -5,7 +5,10 @@ package local
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
 	"path/filepath"
 
 	"github.com/containerd/containerd/content"
@@ -54,8 +57,11 @@ func (s *Store) Update(ctx context.Context, info content.Info, fieldpaths ...str
 	return s.store.Update(ctx, info, fieldpaths...)
 }
 
-// Walk implements content.Store.
+// Walk implements content.Store. A store no blob was written to yet is walked as empty.
 func (s *Store) Walk(ctx context.Context, fn content.WalkFunc, filters ...string) error {
+	if _, err := os.Stat(filepath.Join(s.root, "blobs")); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
 	return s.store.Walk(ctx, fn, filters...)
 }
 
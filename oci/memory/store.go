@@ -0,0 +1,394 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/filters"
+	"github.com/containerd/errdefs"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+type blob struct {
+	info content.Info
+	data []byte
+}
+
+// Store is a content.Store that keeps all blobs and ingests in memory.
+//
+// It is intended for tests and ephemeral use where touching the disk is not desired.
+type Store struct {
+	mu      sync.RWMutex
+	blobs   map[digest.Digest]*blob
+	ingests map[string]*writer
+}
+
+// NewStore returns a new, empty Store.
+func NewStore() *Store {
+	return &Store{
+		blobs:   make(map[digest.Digest]*blob),
+		ingests: make(map[string]*writer),
+	}
+}
+
+// Info implements content.Store.
+func (s *Store) Info(ctx context.Context, dgst digest.Digest) (content.Info, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, ok := s.blobs[dgst]
+	if !ok {
+		return content.Info{}, fmt.Errorf("content %v: %w", dgst, errdefs.ErrNotFound)
+	}
+	return copyInfo(b.info), nil
+}
+
+// Update implements content.Store.
+func (s *Store) Update(ctx context.Context, info content.Info, fieldpaths ...string) (content.Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.blobs[info.Digest]
+	if !ok {
+		return content.Info{}, fmt.Errorf("content %v: %w", info.Digest, errdefs.ErrNotFound)
+	}
+
+	labels := copyLabels(b.info.Labels)
+	if len(fieldpaths) == 0 {
+		labels = copyLabels(info.Labels)
+	}
+	for _, path := range fieldpaths {
+		if strings.HasPrefix(path, "labels.") {
+			key := strings.TrimPrefix(path, "labels.")
+			if value, ok := info.Labels[key]; ok {
+				if labels == nil {
+					labels = make(map[string]string)
+				}
+				labels[key] = value
+			} else {
+				delete(labels, key)
+			}
+			continue
+		}
+
+		switch path {
+		case "labels":
+			labels = copyLabels(info.Labels)
+		default:
+			return content.Info{}, fmt.Errorf("cannot update %q field on content info %q: %w", path, info.Digest, errdefs.ErrInvalidArgument)
+		}
+	}
+
+	b.info.Labels = labels
+	b.info.UpdatedAt = time.Now()
+	return copyInfo(b.info), nil
+}
+
+// Walk implements content.Store.
+func (s *Store) Walk(ctx context.Context, fn content.WalkFunc, fs ...string) error {
+	filter, err := filters.ParseAll(fs...)
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	infos := make([]content.Info, 0, len(s.blobs))
+	for _, b := range s.blobs {
+		infos = append(infos, copyInfo(b.info))
+	}
+	s.mu.RUnlock()
+
+	for _, info := range infos {
+		if !filter.Match(content.AdaptInfo(info)) {
+			continue
+		}
+		if err := fn(info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete implements content.Store.
+func (s *Store) Delete(ctx context.Context, dgst digest.Digest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.blobs[dgst]; !ok {
+		return fmt.Errorf("content %v: %w", dgst, errdefs.ErrNotFound)
+	}
+	delete(s.blobs, dgst)
+	return nil
+}
+
+// ReaderAt implements content.Store.
+func (s *Store) ReaderAt(ctx context.Context, desc ocispec.Descriptor) (content.ReaderAt, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, ok := s.blobs[desc.Digest]
+	if !ok {
+		return nil, fmt.Errorf("content %v: %w", desc.Digest, errdefs.ErrNotFound)
+	}
+	return &readerAt{Reader: bytes.NewReader(b.data), size: int64(len(b.data))}, nil
+}
+
+// Status implements content.Store.
+func (s *Store) Status(ctx context.Context, ref string) (content.Status, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	w, ok := s.ingests[ref]
+	if !ok {
+		return content.Status{}, fmt.Errorf("status for ref %q: %w", ref, errdefs.ErrNotFound)
+	}
+	return w.Status()
+}
+
+// ListStatuses implements content.Store.
+func (s *Store) ListStatuses(ctx context.Context, fs ...string) ([]content.Status, error) {
+	filter, err := filters.ParseAll(fs...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var res []content.Status
+	for _, w := range s.ingests {
+		status, err := w.Status()
+		if err != nil {
+			return nil, err
+		}
+		if filter.Match(adaptStatus(status)) {
+			res = append(res, status)
+		}
+	}
+	return res, nil
+}
+
+// Abort implements content.Store.
+func (s *Store) Abort(ctx context.Context, ref string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.ingests[ref]; !ok {
+		return fmt.Errorf("ingest ref %q: %w", ref, errdefs.ErrNotFound)
+	}
+	delete(s.ingests, ref)
+	return nil
+}
+
+// Writer implements content.Store.
+func (s *Store) Writer(ctx context.Context, opts ...content.WriterOpt) (content.Writer, error) {
+	var wOpts content.WriterOpts
+	for _, opt := range opts {
+		if err := opt(&wOpts); err != nil {
+			return nil, err
+		}
+	}
+	if wOpts.Ref == "" {
+		return nil, fmt.Errorf("ref must not be empty: %w", errdefs.ErrInvalidArgument)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if wOpts.Desc.Digest != "" {
+		if _, ok := s.blobs[wOpts.Desc.Digest]; ok {
+			return nil, fmt.Errorf("content %v: %w", wOpts.Desc.Digest, errdefs.ErrAlreadyExists)
+		}
+	}
+	if _, ok := s.ingests[wOpts.Ref]; ok {
+		return nil, fmt.Errorf("ref %v is locked: %w", wOpts.Ref, errdefs.ErrUnavailable)
+	}
+
+	now := time.Now()
+	w := &writer{
+		store:     s,
+		ref:       wOpts.Ref,
+		total:     wOpts.Desc.Size,
+		expected:  wOpts.Desc.Digest,
+		digester:  digest.Canonical.Digester(),
+		startedAt: now,
+		updatedAt: now,
+	}
+	s.ingests[wOpts.Ref] = w
+	return w, nil
+}
+
+func (s *Store) commit(w *writer, size int64, expected digest.Digest, opts ...content.Opt) error {
+	var base content.Info
+	for _, opt := range opts {
+		if err := opt(&base); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ingests[w.ref] != w {
+		return fmt.Errorf("ingest ref %q: %w", w.ref, errdefs.ErrNotFound)
+	}
+
+	w.mu.Lock()
+	data := w.buf.Bytes()
+	dgst := w.digester.Digest()
+	w.mu.Unlock()
+
+	// The ingest is released even if the commit fails, so the ref can be written again.
+	delete(s.ingests, w.ref)
+	if size > 0 && size != int64(len(data)) {
+		return fmt.Errorf("unexpected commit size %d, expected %d: %w", len(data), size, errdefs.ErrFailedPrecondition)
+	}
+	if expected != "" && expected != dgst {
+		return fmt.Errorf("unexpected commit digest %s, expected %s: %w", dgst, expected, errdefs.ErrFailedPrecondition)
+	}
+
+	if _, ok := s.blobs[dgst]; ok {
+		return fmt.Errorf("content %v: %w", dgst, errdefs.ErrAlreadyExists)
+	}
+
+	now := time.Now()
+	s.blobs[dgst] = &blob{
+		info: content.Info{
+			Digest:    dgst,
+			Size:      int64(len(data)),
+			CreatedAt: now,
+			UpdatedAt: now,
+			Labels:    copyLabels(base.Labels),
+		},
+		data: bytes.Clone(data),
+	}
+	return nil
+}
+
+type writer struct {
+	store *Store
+	ref   string
+
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	digester  digest.Digester
+	total     int64
+	expected  digest.Digest
+	startedAt time.Time
+	updatedAt time.Time
+}
+
+// Write implements content.Writer.
+func (w *writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n, err := w.buf.Write(p)
+	_, _ = w.digester.Hash().Write(p[:n])
+	w.updatedAt = time.Now()
+	return n, err
+}
+
+// Close implements content.Writer.
+//
+// In-memory ingests cannot be resumed, so closing a writer without committing discards its ingest.
+func (w *writer) Close() error {
+	w.store.mu.Lock()
+	defer w.store.mu.Unlock()
+
+	if w.store.ingests[w.ref] == w {
+		delete(w.store.ingests, w.ref)
+	}
+	return nil
+}
+
+// Digest implements content.Writer.
+func (w *writer) Digest() digest.Digest {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.digester.Digest()
+}
+
+// Commit implements content.Writer.
+func (w *writer) Commit(ctx context.Context, size int64, expected digest.Digest, opts ...content.Opt) error {
+	return w.store.commit(w, size, expected, opts...)
+}
+
+// Status implements content.Writer.
+func (w *writer) Status() (content.Status, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return content.Status{
+		Ref:       w.ref,
+		Offset:    int64(w.buf.Len()),
+		Total:     w.total,
+		Expected:  w.expected,
+		StartedAt: w.startedAt,
+		UpdatedAt: w.updatedAt,
+	}, nil
+}
+
+// Truncate implements content.Writer.
+func (w *writer) Truncate(size int64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if size != 0 {
+		return fmt.Errorf("truncate to non-zero size is not supported: %w", errdefs.ErrInvalidArgument)
+	}
+	w.buf.Reset()
+	w.digester = digest.Canonical.Digester()
+	return nil
+}
+
+type readerAt struct {
+	*bytes.Reader
+	size int64
+}
+
+func (r *readerAt) Size() int64 {
+	return r.size
+}
+
+func (r *readerAt) Close() error {
+	return nil
+}
+
+func adaptStatus(status content.Status) filters.Adaptor {
+	return filters.AdapterFunc(func(fieldpath []string) (string, bool) {
+		if len(fieldpath) == 0 {
+			return "", false
+		}
+		switch fieldpath[0] {
+		case "ref":
+			return status.Ref, true
+		}
+		return "", false
+	})
+}
+
+func copyInfo(info content.Info) content.Info {
+	info.Labels = copyLabels(info.Labels)
+	return info
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+	res := make(map[string]string, len(labels))
+	for k, v := range labels {
+		res[k] = v
+	}
+	return res
+}
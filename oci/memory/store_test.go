@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package memory_test
+
+import (
+	"context"
+	"strings"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/errdefs"
+	. "github.com/ironcore-dev/ironcore-image/oci/memory"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+var _ = Describe("Store", func() {
+	var (
+		ctx  context.Context
+		s    *Store
+		data = []byte("content")
+		desc = ocispec.Descriptor{Digest: digest.FromBytes(data), Size: int64(len(data))}
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		s = NewStore()
+	})
+
+	It("should retry a write after a failed commit", func() {
+		By("failing to commit unexpected content")
+		err := content.WriteBlob(ctx, s, "ref", strings.NewReader("CONTENT"), desc)
+		Expect(errdefs.IsFailedPrecondition(err)).To(BeTrue(), "unexpected error %v", err)
+		Expect(s.ListStatuses(ctx)).To(BeEmpty())
+
+		By("writing the content with the same ref")
+		Expect(content.WriteBlob(ctx, s, "ref", strings.NewReader("content"), desc)).To(Succeed())
+		Expect(content.ReadBlob(ctx, s, desc)).To(Equal(data))
+	})
+
+	It("should discard the ingest of a writer closed without committing", func() {
+		w, err := s.Writer(ctx, content.WithRef("ref"), content.WithDescriptor(desc))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = w.Write(data[:3])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.ListStatuses(ctx)).To(HaveLen(1))
+
+		By("closing the writer")
+		Expect(w.Close()).To(Succeed())
+		Expect(s.ListStatuses(ctx)).To(BeEmpty())
+
+		By("writing the content with the same ref")
+		Expect(content.WriteBlob(ctx, s, "ref", strings.NewReader("content"), desc)).To(Succeed())
+	})
+
+	It("should keep the ingest of an open writer", func() {
+		w, err := s.Writer(ctx, content.WithRef("ref"))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(w.Close)
+
+		_, err = s.Writer(ctx, content.WithRef("ref"))
+		Expect(errdefs.IsUnavailable(err)).To(BeTrue(), "unexpected error %v", err)
+	})
+})
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/distribution/reference"
+	ociimage "github.com/ironcore-dev/ironcore-image/oci/image"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// BlobReader provides random access to a blob in a remote registry using HTTP range requests.
+// It is safe for concurrent use.
+type BlobReader struct {
+	ctx  context.Context
+	host docker.RegistryHost
+	url  string
+	desc ocispec.Descriptor
+}
+
+// BlobReaderAt returns a BlobReader of the blob described by desc in the repository of ref.
+// Requests are issued with ctx, so the reader must not be used after ctx is done.
+func (r *Registry) BlobReaderAt(ctx context.Context, ref string, desc ocispec.Descriptor) (*BlobReader, error) {
+	named, err := reference.ParseNamed(ref)
+	if err != nil {
+		return nil, fmt.Errorf("ref %s is no named reference: %w", ref, err)
+	}
+
+	host, err := r.host(reference.Domain(named), docker.HostCapabilityPull)
+	if err != nil {
+		return nil, err
+	}
+
+	name := reference.Path(named)
+	u := url.URL{
+		Scheme: host.Scheme,
+		Host:   host.Host,
+		Path:   fmt.Sprintf("%s/%s/blobs/%s", host.Path, name, desc.Digest),
+	}
+	return &BlobReader{
+		ctx:  docker.ContextWithAppendPullRepositoryScope(ctx, name),
+		host: *host,
+		url:  u.String(),
+		desc: desc,
+	}, nil
+}
+
+// blobReader returns a blobReaderFunc reading blobs of the repository of ref.
+func (r *Registry) blobReader(ref string) blobReaderFunc {
+	return func(ctx context.Context, desc ocispec.Descriptor) (ociimage.ReaderAt, error) {
+		return r.BlobReaderAt(ctx, ref, desc)
+	}
+}
+
+// Descriptor returns the descriptor of the blob.
+func (b *BlobReader) Descriptor() ocispec.Descriptor {
+	return b.desc
+}
+
+// Size returns the size of the blob.
+func (b *BlobReader) Size() int64 {
+	return b.desc.Size
+}
+
+// Close is a no-op, every read is a separate request.
+func (b *BlobReader) Close() error {
+	return nil
+}
+
+func (b *BlobReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	if off >= b.desc.Size {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	length := min(int64(len(p)), b.desc.Size-off)
+	req, err := http.NewRequestWithContext(b.ctx, http.MethodGet, b.url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+length-1))
+
+	res, err := Do(b.ctx, nil, b.host, req)
+	if err != nil {
+		return 0, fmt.Errorf("error reading blob %s: %w", b.desc.Digest, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	switch res.StatusCode {
+	case http.StatusPartialContent:
+		start, end, err := parseContentRange(res.Header.Get("Content-Range"))
+		if err != nil {
+			return 0, fmt.Errorf("error reading blob %s: %w", b.desc.Digest, err)
+		}
+		if start != off || end-start+1 != length {
+			return 0, fmt.Errorf("error reading blob %s: registry returned range %d-%d instead of %d-%d",
+				b.desc.Digest, start, end, off, off+length-1)
+		}
+	case http.StatusOK:
+		// The registry ignored the range, skip to the requested offset.
+		if _, err := io.CopyN(io.Discard, res.Body, off); err != nil {
+			return 0, fmt.Errorf("error reading blob %s: %w", b.desc.Digest, err)
+		}
+	default:
+		return 0, fmt.Errorf("erroneous response status: %s for url %s", res.Status, req.URL)
+	}
+
+	n, err := io.ReadFull(res.Body, p[:length])
+	if err != nil {
+		return n, fmt.Errorf("error reading blob %s: %w", b.desc.Digest, err)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// parseContentRange parses the first and last byte position of a Content-Range header of the form
+// 'bytes first-last/size'.
+func parseContentRange(value string) (start, end int64, err error) {
+	invalid := fmt.Errorf("invalid Content-Range %q", value)
+
+	rng, ok := strings.CutPrefix(value, "bytes ")
+	if !ok {
+		return 0, 0, invalid
+	}
+	rng, _, _ = strings.Cut(rng, "/")
+	first, last, ok := strings.Cut(rng, "-")
+	if !ok {
+		return 0, 0, invalid
+	}
+	if start, err = strconv.ParseInt(first, 10, 64); err != nil || start < 0 {
+		return 0, 0, invalid
+	}
+	if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+		return 0, 0, invalid
+	}
+	return start, end, nil
+}
@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd/remotes"
+	ocicontent "github.com/ironcore-dev/ironcore-image/oci/content"
+	ociimage "github.com/ironcore-dev/ironcore-image/oci/image"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+var _ = Describe("layer", func() {
+	It("should verify the content by default", func() {
+		ctx := context.Background()
+		desc := ocispec.Descriptor{
+			MediaType: "application/octet-stream",
+			Digest:    digest.FromString("content"),
+			Size:      7,
+		}
+		fetcher := remotes.FetcherFunc(func(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("CONTENT")), nil
+		})
+		l := &layer{descriptor: desc, fetcher: fetcher}
+
+		By("reading the tampered content")
+		rc, err := l.Content(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = io.ReadAll(rc)
+		Expect(err).To(MatchError(ocicontent.ErrDigestMismatch))
+
+		By("reading the content without verification")
+		rc, err = l.Content(ocicontent.WithVerifyDigests(ctx, false))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(io.ReadAll(rc)).To(Equal([]byte("CONTENT")))
+	})
+
+	It("should only read ranges without verification", func() {
+		ctx := context.Background()
+		desc := ocispec.Descriptor{MediaType: "application/octet-stream", Digest: digest.FromString("content"), Size: 7}
+		l := &layer{descriptor: desc, blobReader: func(ctx context.Context, desc ocispec.Descriptor) (ociimage.ReaderAt, error) {
+			return &BlobReader{desc: desc}, nil
+		}}
+
+		_, err := l.ReaderAt(ctx)
+		Expect(err).To(MatchError(ociimage.ErrRangeNotSupported))
+
+		r, err := l.ReaderAt(ocicontent.WithVerifyDigests(ctx, false))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Size()).To(Equal(desc.Size))
+	})
+})
+
+var _ = Describe("BlobReader", func() {
+	var (
+		ctx      context.Context
+		data     []byte
+		desc     ocispec.Descriptor
+		ranges   []string
+		noRanges bool
+		// contentRange, if set, is returned as Content-Range of a partial response with the first 15 bytes.
+		contentRange *string
+		ref          string
+		registry     *Registry
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		data = []byte(strings.Repeat("0123456789", 100))
+		desc = ocispec.Descriptor{
+			MediaType: "application/octet-stream",
+			Digest:    digest.FromBytes(data),
+			Size:      int64(len(data)),
+		}
+		ranges = nil
+		noRanges = false
+		contentRange = nil
+		blobPath := "/v2/test/blobs/" + desc.Digest.String()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != blobPath {
+				http.NotFound(w, r)
+				return
+			}
+			ranges = append(ranges, r.Header.Get("Range"))
+			if noRanges {
+				_, _ = w.Write(data)
+				return
+			}
+			if contentRange != nil {
+				if *contentRange != "" {
+					w.Header().Set("Content-Range", *contentRange)
+				}
+				w.WriteHeader(http.StatusPartialContent)
+				_, _ = w.Write(data[:15])
+				return
+			}
+			http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+		}))
+		DeferCleanup(server.Close)
+		ref = strings.TrimPrefix(server.URL, "http://") + "/test:latest"
+
+		var err error
+		registry, err = DockerRegistryWithConfigPath(writeDockerConfig(GinkgoT().TempDir(), `{"auths": {}}`))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should read ranges of the blob", func() {
+		br, err := registry.BlobReaderAt(ctx, ref, desc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(br.Size()).To(Equal(desc.Size))
+
+		p := make([]byte, 15)
+		n, err := br.ReadAt(p, 995)
+		Expect(err).To(MatchError(io.EOF))
+		Expect(p[:n]).To(Equal(data[995:]))
+
+		n, err = br.ReadAt(p, 12)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p[:n]).To(Equal(data[12:27]))
+		Expect(ranges).To(Equal([]string{"bytes=995-999", "bytes=12-26"}))
+
+		_, err = br.ReadAt(p, desc.Size)
+		Expect(err).To(MatchError(io.EOF))
+	})
+
+	It("should read ranges if the registry ignores them", func() {
+		noRanges = true
+		br, err := registry.BlobReaderAt(ctx, ref, desc)
+		Expect(err).NotTo(HaveOccurred())
+
+		p := make([]byte, 15)
+		n, err := br.ReadAt(p, 500)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p[:n]).To(Equal(data[500:515]))
+	})
+
+	DescribeTable("should reject partial responses for other ranges",
+		func(value, expected string) {
+			contentRange = &value
+			br, err := registry.BlobReaderAt(ctx, ref, desc)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = br.ReadAt(make([]byte, 15), 500)
+			Expect(err).To(MatchError(ContainSubstring(expected)))
+		},
+		Entry("other start", "bytes 0-14/1000", "returned range 0-14 instead of 500-514"),
+		Entry("other length", "bytes 500-509/1000", "returned range 500-509 instead of 500-514"),
+		Entry("missing Content-Range", "", "invalid Content-Range"),
+		Entry("invalid Content-Range", "bytes */1000", "invalid Content-Range"),
+	)
+
+	It("should error if the blob does not exist", func() {
+		desc.Digest = digest.FromString("other")
+		br, err := registry.BlobReaderAt(ctx, ref, desc)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = br.ReadAt(make([]byte, 1), 0)
+		Expect(err).To(MatchError(ContainSubstring("404")))
+	})
+})
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package remote
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/containerd/containerd/remotes/docker"
+)
+
+// maxAttempts is the maximum number of requests issued by Do.
+const maxAttempts = 6
+
+var (
+	// retryBaseDelay is the delay before the first retry of a transient error without Retry-After header.
+	// It is doubled for every further retry.
+	retryBaseDelay = 250 * time.Millisecond
+	// maxRetryDelay is the maximum delay before retrying a transient error, also if requested via Retry-After.
+	maxRetryDelay = time.Minute
+)
+
+// retryDelay returns the delay before retrying the transient error res of the given attempt, starting at 0.
+// A Retry-After header in seconds or as HTTP date is honoured, otherwise the delay grows exponentially.
+func retryDelay(res *http.Response, attempt int) time.Duration {
+	delay := retryBaseDelay << attempt
+	if value := res.Header.Get("Retry-After"); value != "" {
+		if sec, err := strconv.Atoi(value); err == nil && sec >= 0 {
+			delay = time.Duration(sec) * time.Second
+		} else if date, err := http.ParseTime(value); err == nil {
+			delay = max(time.Until(date), 0)
+		}
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// Do authorizes and issues the request against the registry host, retrying on authentication
+// challenges and transient errors. Transient errors are retried after the delay requested by the
+// registry or with exponential backoff until ctx is done. The final response is returned regardless
+// of its status code. If client is nil, the client of the registry host is used.
+func Do(ctx context.Context, client *http.Client, host docker.RegistryHost, req *http.Request) (*http.Response, error) {
+	if client == nil {
+		client = host.Client
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var (
+		auth      = host.Authorizer
+		responses []*http.Response
+		retries   int
+	)
+	for len(responses) < maxAttempts {
+		if auth != nil {
+			if err := auth.Authorize(ctx, req); err != nil {
+				return nil, fmt.Errorf("error authorizing request: %w", err)
+			}
+		}
+
+		res, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		switch res.StatusCode {
+		case http.StatusUnauthorized:
+			_ = res.Body.Close()
+			if auth == nil {
+				return nil, fmt.Errorf("erroneous response status: %s for url %s", res.Status, req.URL)
+			}
+			responses = append(responses, res)
+			if err := auth.AddResponses(ctx, responses); err != nil {
+				return nil, fmt.Errorf("error adding responses: %w", err)
+			}
+		case http.StatusRequestTimeout, http.StatusTooManyRequests:
+			_ = res.Body.Close()
+			responses = append(responses, res)
+			if len(responses) == maxAttempts {
+				continue
+			}
+
+			timer := time.NewTimer(retryDelay(res, retries))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+			retries++
+		default:
+			return res, nil
+		}
+	}
+	return nil, fmt.Errorf("too many failed attempts for url %s", req.URL)
+}
+
+// host returns the first registry host of the given host name providing the capability.
+func (r *Registry) host(host string, capability docker.HostCapabilities) (*docker.RegistryHost, error) {
+	regs, err := r.hosts(host)
+	if err != nil {
+		return nil, fmt.Errorf("error getting host for %s: %w", host, err)
+	}
+	for _, reg := range regs {
+		if reg.Capabilities.Has(capability) {
+			reg := reg
+			return &reg, nil
+		}
+	}
+	return nil, fmt.Errorf("no registry providing the required capability for host %s", host)
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package remote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/containerd/containerd/remotes/docker"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Do", func() {
+	var (
+		retryAfter string
+		failures   int
+		requests   int
+		url        string
+	)
+
+	BeforeEach(func() {
+		DeferCleanup(func(base time.Duration) { retryBaseDelay = base }, retryBaseDelay)
+		retryBaseDelay = time.Millisecond
+
+		retryAfter = ""
+		failures = 0
+		requests = 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests <= failures {
+				if retryAfter != "" {
+					w.Header().Set("Retry-After", retryAfter)
+				}
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		DeferCleanup(server.Close)
+		url = server.URL
+	})
+
+	do := func(ctx context.Context) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		Expect(err).NotTo(HaveOccurred())
+		return Do(ctx, nil, docker.RegistryHost{}, req)
+	}
+
+	It("should retry transient errors", func(ctx SpecContext) {
+		failures = 3
+		res, err := do(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+		Expect(requests).To(Equal(4))
+	})
+
+	It("should give up after too many transient errors", func(ctx SpecContext) {
+		failures = 10
+		_, err := do(ctx)
+		Expect(err).To(MatchError(ContainSubstring("too many failed attempts")))
+		Expect(requests).To(Equal(6))
+	})
+
+	It("should stop waiting for a retry when the context is done", func(ctx SpecContext) {
+		failures = 1
+		retryAfter = "30"
+		ctx2, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		_, err := do(ctx2)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(requests).To(Equal(1))
+	})
+
+	DescribeTable("retryDelay",
+		func(header string, attempt int, expected time.Duration) {
+			res := &http.Response{Header: http.Header{}}
+			if header != "" {
+				res.Header.Set("Retry-After", header)
+			}
+			Expect(retryDelay(res, attempt)).To(Equal(expected))
+		},
+		Entry("exponential backoff", "", 3, 8*time.Millisecond),
+		Entry("retry after seconds", "2", 3, 2*time.Second),
+		Entry("retry after a date in the past", "Mon, 02 Jan 2006 15:04:05 GMT", 0, time.Duration(0)),
+		Entry("limited retry after", "3600", 0, time.Minute),
+		Entry("invalid retry after", "soon", 1, 2*time.Millisecond),
+	)
+})
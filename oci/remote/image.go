@@ -11,21 +11,56 @@ import (
 	"sync"
 
 	"github.com/containerd/containerd/remotes"
+	ocicontent "github.com/ironcore-dev/ironcore-image/oci/content"
 	ociimage "github.com/ironcore-dev/ironcore-image/oci/image"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
+// blobReaderFunc returns random access to the blob described by desc.
+type blobReaderFunc func(ctx context.Context, desc ocispec.Descriptor) (ociimage.ReaderAt, error)
+
 type layer struct {
 	descriptor ocispec.Descriptor
 	fetcher    remotes.Fetcher
+	blobReader blobReaderFunc
 }
 
 func (l *layer) Descriptor() ocispec.Descriptor {
 	return l.descriptor
 }
 
+// Content fetches the layer content. It is verified against the layer digest and size while
+// being read, unless disabled via ocicontent.WithVerifyDigests.
 func (l *layer) Content(ctx context.Context) (io.ReadCloser, error) {
-	return l.fetcher.Fetch(ctx, l.descriptor)
+	rc, err := l.fetcher.Fetch(ctx, l.descriptor)
+	if err != nil {
+		return nil, err
+	}
+	if !ocicontent.VerifyDigests(ctx, true) {
+		return rc, nil
+	}
+
+	vr, err := ocicontent.NewDescriptorVerifyingReader(rc, l.descriptor)
+	if err != nil {
+		_ = rc.Close()
+		return nil, err
+	}
+	return vr, nil
+}
+
+// ReaderAt returns random access to the layer content via HTTP range requests.
+// It returns ociimage.ErrRangeNotSupported for layers not obtained from a Registry.
+// As ranges cannot be verified against the layer digest, it also does so unless digest verification
+// is disabled via ocicontent.WithVerifyDigests, e.g. by callers verifying the ranges by other means.
+func (l *layer) ReaderAt(ctx context.Context) (ociimage.ReaderAt, error) {
+	if l.blobReader == nil {
+		return nil, fmt.Errorf("%w: layer %s", ociimage.ErrRangeNotSupported, l.descriptor.Digest)
+	}
+	if ocicontent.VerifyDigests(ctx, true) {
+		return nil, fmt.Errorf("%w: ranges of layer %s cannot be verified, disable digest verification to read them",
+			ociimage.ErrRangeNotSupported, l.descriptor.Digest)
+	}
+	return l.blobReader(ctx, l.descriptor)
 }
 
 type image struct {
@@ -67,6 +102,7 @@ func (i *image) Config(ctx context.Context) (ociimage.Layer, error) {
 	return &layer{
 		descriptor: i.manifest.Config,
 		fetcher:    i.fetcher,
+		blobReader: i.blobReader,
 	}, nil
 }
 
@@ -81,18 +117,66 @@ func (i *image) Layers(ctx context.Context) ([]ociimage.Layer, error) {
 		layers = append(layers, &layer{
 			descriptor: desc,
 			fetcher:    i.fetcher,
+			blobReader: i.blobReader,
 		})
 	}
 
 	return layers, nil
 }
 
+// Image returns an image manifest that lazily fetches its content via the given fetcher.
+// Its layers do not support random access, use Registry to obtain images whose layers do.
 func Image(fetcher remotes.Fetcher, desc ocispec.Descriptor) ociimage.Image {
+	return newImage(fetcher, desc, nil)
+}
+
+func newImage(fetcher remotes.Fetcher, desc ocispec.Descriptor, blobReader blobReaderFunc) ociimage.Image {
 	return &image{
 		layer: layer{
 			descriptor: desc,
 			fetcher:    fetcher,
+			blobReader: blobReader,
 		},
 		Once: sync.Once{},
 	}
 }
+
+type indexImage struct {
+	layer
+}
+
+func (i *indexImage) IndexManifest(ctx context.Context) (*ocispec.Index, error) {
+	rc, err := i.Content(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting content: %w", err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	indexManifest := &ocispec.Index{}
+	if err := json.NewDecoder(rc).Decode(indexManifest); err != nil {
+		return nil, fmt.Errorf("could not decode index manifest: %w", err)
+	}
+	return indexManifest, nil
+}
+
+func (i *indexImage) Manifest(ctx context.Context) (*ocispec.Manifest, error) {
+	return nil, fmt.Errorf("index manifests do not have a single manifest")
+}
+
+func (i *indexImage) Config(ctx context.Context) (ociimage.Layer, error) {
+	return nil, fmt.Errorf("index manifests do not have a config layer")
+}
+
+func (i *indexImage) Layers(ctx context.Context) ([]ociimage.Layer, error) {
+	return nil, fmt.Errorf("index manifests do not have layers")
+}
+
+// IndexImage returns an image index that lazily fetches its content via the given fetcher.
+func IndexImage(fetcher remotes.Fetcher, desc ocispec.Descriptor) ociimage.Image {
+	return &indexImage{
+		layer: layer{
+			descriptor: desc,
+			fetcher:    fetcher,
+		},
+	}
+}
@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/containerd/errdefs"
+	"github.com/distribution/reference"
+	ocicontent "github.com/ironcore-dev/ironcore-image/oci/content"
+	ociimage "github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/ironcore-dev/ironcore-image/oci/imageutil"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ReferrersTag returns the tag of the referrers index for subject according to the
+// referrers tag schema, used by registries not supporting the referrers API.
+func ReferrersTag(subject digest.Digest) string {
+	tag := subject.Algorithm().String() + "-" + subject.Encoded()
+	if len(tag) > 128 {
+		tag = tag[:128]
+	}
+	return tag
+}
+
+// Referrers lists the manifests in the repository of ref whose subject is the given digest.
+// The OCI referrers API is used if the registry supports it, otherwise the referrers tag schema.
+// If artifactType is not empty, only referrers of that artifact type are returned.
+func (r *Registry) Referrers(ctx context.Context, ref string, subject digest.Digest, artifactType string) ([]ocispec.Descriptor, error) {
+	index, supported, err := r.referrersIndex(ctx, ref, subject)
+	if err != nil {
+		return nil, err
+	}
+	if !supported {
+		index, err = r.referrersTagIndex(ctx, ref, subject)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := make([]ocispec.Descriptor, 0, len(index.Manifests))
+	for _, desc := range index.Manifests {
+		if artifactType != "" && desc.ArtifactType != artifactType {
+			continue
+		}
+		res = append(res, desc)
+	}
+	return res, nil
+}
+
+// Referrer returns the referrer manifest described by desc.
+func (r *Registry) Referrer(ctx context.Context, ref string, desc ocispec.Descriptor) (ociimage.Image, error) {
+	return r.GetManifest(ctx, ref, desc)
+}
+
+// PushReferrer pushes the referrer manifest by digest into the repository of ref.
+// If the registry does not support the referrers API, the referrers tag schema index
+// of the subject is updated.
+func (r *Registry) PushReferrer(ctx context.Context, ref string, img ociimage.Image) error {
+	manifest, err := img.Manifest(ctx)
+	if err != nil {
+		return fmt.Errorf("error reading referrer manifest: %w", err)
+	}
+	if manifest.Subject == nil {
+		return fmt.Errorf("manifest %s has no subject", img.Descriptor().Digest)
+	}
+
+	named, err := reference.ParseNamed(ref)
+	if err != nil {
+		return fmt.Errorf("ref %s is no named reference: %w", ref, err)
+	}
+	repository := reference.TrimNamed(named)
+
+	digestRef, err := reference.WithDigest(repository, img.Descriptor().Digest)
+	if err != nil {
+		return fmt.Errorf("error creating digest ref: %w", err)
+	}
+	if err := r.Push(ctx, digestRef.String(), img); err != nil {
+		return fmt.Errorf("error pushing referrer %s: %w", img.Descriptor().Digest, err)
+	}
+
+	subject := manifest.Subject.Digest
+	_, supported, err := r.referrersIndex(ctx, ref, subject)
+	if err != nil {
+		return err
+	}
+	if supported {
+		return nil
+	}
+
+	index, err := r.referrersTagIndex(ctx, ref, subject)
+	if err != nil {
+		return err
+	}
+
+	desc := ociimage.ReferrerDescriptor(img.Descriptor(), manifest)
+	for _, existing := range index.Manifests {
+		if existing.Digest == desc.Digest {
+			return nil
+		}
+	}
+	index.Manifests = append(index.Manifests, desc)
+
+	indexImg, err := imageutil.NewIndexImage(*index)
+	if err != nil {
+		return fmt.Errorf("error creating referrers index: %w", err)
+	}
+
+	tagRef, err := reference.WithTag(repository, ReferrersTag(subject))
+	if err != nil {
+		return fmt.Errorf("error creating referrers tag ref: %w", err)
+	}
+	if err := r.Push(ctx, tagRef.String(), indexImg); err != nil {
+		return fmt.Errorf("error pushing referrers index %s: %w", tagRef, err)
+	}
+	return nil
+}
+
+// referrersIndex queries the referrers API. If the registry does not support the API,
+// supported is false. Registries without the API respond with different client errors
+// (404, 400, 405, 406), so all client errors except for authentication failures are
+// treated as missing support.
+func (r *Registry) referrersIndex(ctx context.Context, ref string, subject digest.Digest) (index *ocispec.Index, supported bool, err error) {
+	named, err := reference.ParseNamed(ref)
+	if err != nil {
+		return nil, false, fmt.Errorf("ref %s is no named reference: %w", ref, err)
+	}
+
+	host, err := r.host(reference.Domain(named), docker.HostCapabilityPull)
+	if err != nil {
+		return nil, false, err
+	}
+
+	name := reference.Path(named)
+	ctx = docker.ContextWithAppendPullRepositoryScope(ctx, name)
+
+	u := url.URL{
+		Scheme: host.Scheme,
+		Host:   host.Host,
+		Path:   fmt.Sprintf("%s/%s/referrers/%s", host.Path, name, subject),
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Accept", ocispec.MediaTypeImageIndex)
+
+	res, err := Do(ctx, nil, *host, req)
+	if err != nil {
+		return nil, false, fmt.Errorf("error querying referrers of %s: %w", subject, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	switch {
+	case res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden:
+		return nil, false, fmt.Errorf("erroneous response status: %s for url %s", res.Status, req.URL)
+	case res.StatusCode >= 400 && res.StatusCode < 500:
+		return nil, false, nil
+	case res.StatusCode < 200 || res.StatusCode >= 300:
+		return nil, false, fmt.Errorf("erroneous response status: %s for url %s", res.Status, req.URL)
+	}
+
+	index = &ocispec.Index{}
+	if err := json.NewDecoder(io.LimitReader(res.Body, 4<<20)).Decode(index); err != nil {
+		return nil, false, fmt.Errorf("error decoding referrers of %s: %w", subject, err)
+	}
+	return index, true, nil
+}
+
+// referrersTagIndex fetches the referrers tag schema index of subject. If it does not exist,
+// an empty index is returned.
+func (r *Registry) referrersTagIndex(ctx context.Context, ref string, subject digest.Digest) (*ocispec.Index, error) {
+	named, err := reference.ParseNamed(ref)
+	if err != nil {
+		return nil, fmt.Errorf("ref %s is no named reference: %w", ref, err)
+	}
+
+	tagRef, err := reference.WithTag(reference.TrimNamed(named), ReferrersTag(subject))
+	if err != nil {
+		return nil, fmt.Errorf("error creating referrers tag ref: %w", err)
+	}
+
+	img, err := r.Get(ctx, tagRef.String())
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return &ocispec.Index{
+				Versioned: specs.Versioned{SchemaVersion: 2},
+				MediaType: ocispec.MediaTypeImageIndex,
+				Manifests: []ocispec.Descriptor{},
+			}, nil
+		}
+		return nil, fmt.Errorf("error getting referrers index %s: %w", tagRef, err)
+	}
+
+	index, err := ocicontent.GetIndexManifest(ctx, img)
+	if err != nil {
+		return nil, fmt.Errorf("error reading referrers index %s: %w", tagRef, err)
+	}
+	return index, nil
+}
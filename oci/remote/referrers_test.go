@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package remote
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
+)
+
+var _ = Describe("Referrers", func() {
+	var (
+		status   int
+		ref      string
+		registry *Registry
+	)
+
+	BeforeEach(func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.Contains(r.URL.Path, "/referrers/") {
+				w.WriteHeader(status)
+				return
+			}
+			// No referrers tag schema index exists.
+			http.NotFound(w, r)
+		}))
+		DeferCleanup(server.Close)
+		ref = strings.TrimPrefix(server.URL, "http://") + "/test:latest"
+
+		var err error
+		registry, err = DockerRegistryWithConfigPath(writeDockerConfig(GinkgoT().TempDir(), `{"auths": {}}`))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	DescribeTable("should fall back to the referrers tag schema for registries without referrers API",
+		func(ctx SpecContext, responseStatus int) {
+			status = responseStatus
+			Expect(registry.Referrers(ctx, ref, digest.FromString("subject"), "")).To(BeEmpty())
+		},
+		Entry("not found", http.StatusNotFound),
+		Entry("bad request", http.StatusBadRequest),
+		Entry("method not allowed", http.StatusMethodNotAllowed),
+		Entry("not acceptable", http.StatusNotAcceptable),
+	)
+
+	It("should fail if access is denied", func(ctx SpecContext) {
+		status = http.StatusForbidden
+		_, err := registry.Referrers(ctx, ref, digest.FromString("subject"), "")
+		Expect(err).To(MatchError(ContainSubstring("403 Forbidden")))
+	})
+})
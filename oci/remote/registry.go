@@ -11,6 +11,7 @@ import (
 	"fmt"
 
 	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/images"
 	"github.com/containerd/containerd/remotes"
 	"github.com/containerd/containerd/remotes/docker"
 	"github.com/containerd/errdefs"
@@ -23,7 +24,35 @@ var ErrNoPlatformMatch = errors.New("no matching platform found in index")
 
 type Registry struct {
 	resolver       remotes.Resolver
+	hosts          docker.RegistryHosts
 	targetPlatform *ocispec.Platform
+	verifier       ociimage.Verifier
+}
+
+// WithPlatform returns a copy of the Registry that selects the manifest of the given platform when resolving an index.
+func (r *Registry) WithPlatform(platform *ocispec.Platform) *Registry {
+	res := *r
+	res.targetPlatform = platform
+	return &res
+}
+
+// WithVerifier returns a copy of the Registry that verifies images using v when resolving them.
+// Verification happens before the image is returned, so no image content is fetched for images failing it.
+// For an index, the index is verified; if the index is not signed, the selected manifest is verified instead.
+func (r *Registry) WithVerifier(v ociimage.Verifier) *Registry {
+	res := *r
+	res.verifier = v
+	return &res
+}
+
+func (r *Registry) verify(ctx context.Context, ref string, desc ocispec.Descriptor) error {
+	if r.verifier == nil {
+		return nil
+	}
+	if err := r.verifier.Verify(ctx, r, ref, desc); err != nil {
+		return fmt.Errorf("error verifying %s: %w", ref, err)
+	}
+	return nil
 }
 
 func (r *Registry) Resolve(ctx context.Context, ref string) (ociimage.Image, error) {
@@ -38,10 +67,18 @@ func (r *Registry) Resolve(ctx context.Context, ref string) (ociimage.Image, err
 	}
 
 	switch desc.MediaType {
-	case ocispec.MediaTypeImageManifest:
-		return Image(fetcher, desc), nil
+	case ocispec.MediaTypeImageManifest, images.MediaTypeDockerSchema2Manifest:
+		if err := r.verify(ctx, ref, desc); err != nil {
+			return nil, err
+		}
+		return newImage(fetcher, desc, r.blobReader(ref)), nil
+
+	case ocispec.MediaTypeImageIndex, images.MediaTypeDockerSchema2ManifestList:
+		indexErr := r.verify(ctx, ref, desc)
+		if indexErr != nil && !errors.Is(indexErr, ociimage.ErrUnsigned) {
+			return nil, indexErr
+		}
 
-	case ocispec.MediaTypeImageIndex:
 		rc, err := fetcher.Fetch(ctx, desc)
 		if err != nil {
 			return nil, fmt.Errorf("error fetching index blob: %w", err)
@@ -57,14 +94,57 @@ func (r *Registry) Resolve(ctx context.Context, ref string) (ociimage.Image, err
 		if matched == nil {
 			return nil, fmt.Errorf("%w: platform not found %+v", ErrNoPlatformMatch, r.targetPlatform)
 		}
+		if indexErr != nil {
+			if err := r.verify(ctx, ref, *matched); err != nil {
+				return nil, err
+			}
+		}
+
+		return newImage(fetcher, *matched, r.blobReader(ref)), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported media type: %s", desc.MediaType)
+	}
+}
+
+// Get resolves ref to the image manifest or image index it references without selecting a platform.
+// Only the manifest itself is fetched, and only once it is accessed. Index contents can be read via
+// ocicontent.GetIndexManifest and their manifests obtained via GetManifest.
+func (r *Registry) Get(ctx context.Context, ref string) (ociimage.Image, error) {
+	_, desc, err := r.resolver.Resolve(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving %s: %w", ref, err)
+	}
 
-		return Image(fetcher, *matched), nil
+	fetcher, err := r.resolver.Fetcher(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("error getting fetcher for %s: %w", ref, err)
+	}
 
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageManifest, images.MediaTypeDockerSchema2Manifest:
+		return newImage(fetcher, desc, r.blobReader(ref)), nil
+	case ocispec.MediaTypeImageIndex, images.MediaTypeDockerSchema2ManifestList:
+		return IndexImage(fetcher, desc), nil
 	default:
 		return nil, fmt.Errorf("unsupported media type: %s", desc.MediaType)
 	}
 }
 
+// GetManifest returns the image manifest described by desc from the repository of ref,
+// e.g. a manifest referenced by an image index obtained via Get.
+func (r *Registry) GetManifest(ctx context.Context, ref string, desc ocispec.Descriptor) (ociimage.Image, error) {
+	if desc.MediaType != ocispec.MediaTypeImageManifest && desc.MediaType != images.MediaTypeDockerSchema2Manifest {
+		return nil, fmt.Errorf("unsupported media type: %s", desc.MediaType)
+	}
+
+	fetcher, err := r.resolver.Fetcher(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("error getting fetcher for %s: %w", ref, err)
+	}
+	return newImage(fetcher, desc, r.blobReader(ref)), nil
+}
+
 func matchPlatform(manifests []ocispec.Descriptor, target *ocispec.Platform) *ocispec.Descriptor {
 	if target == nil {
 		if len(manifests) == 1 {
@@ -171,27 +251,29 @@ func DockerRegistry() (*Registry, error) {
 }
 
 func DockerRegistryWithPlatform(platform *ocispec.Platform) (*Registry, error) {
-	credFunc, err := DockerCredentialFunc("")
-	if err != nil {
-		return nil, fmt.Errorf("error creating credential function: %w", err)
-	}
-
-	resolver := docker.NewResolver(docker.ResolverOptions{
-		Credentials: credFunc,
-	})
-
-	return &Registry{resolver: resolver, targetPlatform: platform}, nil
+	return newDockerRegistry("", platform)
 }
 
 func DockerRegistryWithConfigPath(configPath string) (*Registry, error) {
+	return newDockerRegistry(configPath, nil)
+}
+
+func newDockerRegistry(configPath string, platform *ocispec.Platform) (*Registry, error) {
 	credFunc, err := DockerCredentialFunc(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("error creating credential function: %w", err)
 	}
 
+	hosts := docker.ConfigureDefaultRegistries(
+		docker.WithPlainHTTP(docker.MatchLocalhost),
+		docker.WithAuthorizer(docker.NewDockerAuthorizer(
+			docker.WithAuthCreds(credFunc),
+		)),
+	)
+
 	resolver := docker.NewResolver(docker.ResolverOptions{
-		Credentials: credFunc,
+		Hosts: hosts,
 	})
 
-	return &Registry{resolver: resolver}, nil
+	return &Registry{resolver: resolver, hosts: hosts, targetPlatform: platform}, nil
 }
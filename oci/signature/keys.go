@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrUnsupportedKey is returned for keys of unsupported types or formats.
+var ErrUnsupportedKey = errors.New("unsupported key")
+
+// LoadPrivateKey loads a PEM encoded, unencrypted ECDSA, Ed25519 or RSA private key from the given file.
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading private key: %w", err)
+	}
+	return ParsePrivateKey(data)
+}
+
+// ParsePrivateKey parses a PEM encoded, unencrypted ECDSA, Ed25519 or RSA private key.
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM data found", ErrUnsupportedKey)
+	}
+
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: PEM type %q, only unencrypted private keys are supported", ErrUnsupportedKey, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing private key: %w", err)
+	}
+
+	switch key := key.(type) {
+	case *ecdsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	case *rsa.PrivateKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("%w: key type %T", ErrUnsupportedKey, key)
+	}
+}
+
+// LoadPublicKey loads a PEM encoded ECDSA, Ed25519 or RSA public key from the given file.
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading public key: %w", err)
+	}
+	return ParsePublicKey(data)
+}
+
+// ParsePublicKey parses a PEM encoded ECDSA, Ed25519 or RSA public key.
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM data found", ErrUnsupportedKey)
+	}
+
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: PEM type %q", ErrUnsupportedKey, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing public key: %w", err)
+	}
+
+	switch key := key.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey, *rsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("%w: key type %T", ErrUnsupportedKey, key)
+	}
+}
+
+// KeyID returns an identifier of the public key, the hex encoded SHA-256 digest of its PKIX encoding.
+func KeyID(key crypto.PublicKey) (string, error) {
+	data, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling public key: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func sign(key crypto.Signer, payload []byte) ([]byte, error) {
+	switch key.(type) {
+	case ed25519.PrivateKey:
+		return key.Sign(rand.Reader, payload, crypto.Hash(0))
+	case *ecdsa.PrivateKey, *rsa.PrivateKey:
+		sum := sha256.Sum256(payload)
+		return key.Sign(rand.Reader, sum[:], crypto.SHA256)
+	default:
+		return nil, fmt.Errorf("%w: key type %T", ErrUnsupportedKey, key)
+	}
+}
+
+func verify(key crypto.PublicKey, payload, sig []byte) bool {
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, payload, sig)
+	case *ecdsa.PublicKey:
+		sum := sha256.Sum256(payload)
+		return ecdsa.VerifyASN1(key, sum[:], sig)
+	case *rsa.PublicKey:
+		sum := sha256.Sum256(payload)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, sum[:], sig) == nil
+	default:
+		return false
+	}
+}
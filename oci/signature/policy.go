@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package signature
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.yaml.in/yaml/v3"
+)
+
+// ErrRejected is returned by a Policy for images matching a reject rule.
+var ErrRejected = errors.New("image rejected by policy")
+
+// RuleType is the type of a policy rule.
+type RuleType string
+
+const (
+	// RuleTypeRequireSignature requires a valid signature of any of the rule's keys.
+	RuleTypeRequireSignature RuleType = "require-signature"
+	// RuleTypeAllowUnsigned accepts images regardless of their signatures.
+	RuleTypeAllowUnsigned RuleType = "allow-unsigned"
+	// RuleTypeReject rejects all images.
+	RuleTypeReject RuleType = "reject"
+)
+
+// Rule is a rule of a PolicyConfig.
+type Rule struct {
+	// Scope is the registry host (e.g. 'ghcr.io'), repository namespace (e.g. 'ghcr.io/ironcore-dev')
+	// or repository (e.g. 'ghcr.io/ironcore-dev/gardenlinux') the rule applies to.
+	Scope string `json:"scope,omitempty" yaml:"scope,omitempty"`
+	// Type is the type of the rule.
+	Type RuleType `json:"type" yaml:"type"`
+	// Keys are the paths of the PEM encoded public keys accepted by a require-signature rule.
+	// Relative paths are relative to the directory of the policy file.
+	Keys []string `json:"keys,omitempty" yaml:"keys,omitempty"`
+}
+
+// PolicyConfig is the content of a policy file.
+type PolicyConfig struct {
+	// Default is applied to images not matching any of Rules. The scope of Default is ignored.
+	Default Rule `json:"default" yaml:"default"`
+	// Rules are the per registry / repository rules. The rule with the most specific scope matching
+	// the repository of an image applies.
+	Rules []Rule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// Policy is a signature verification policy. It implements image.Verifier.
+type Policy struct {
+	defaultRule policyRule
+	rules       []policyRule
+}
+
+type policyRule struct {
+	scope    string
+	ruleType RuleType
+	verifier *Verifier
+}
+
+// LoadPolicy loads the policy file at path. The file may be YAML or JSON.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading policy file: %w", err)
+	}
+
+	var config PolicyConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("error decoding policy file %s: %w", path, err)
+	}
+
+	policy, err := NewPolicy(config, filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+// NewPolicy creates a new Policy from config. Relative key paths are resolved against baseDir.
+func NewPolicy(config PolicyConfig, baseDir string) (*Policy, error) {
+	defaultRule, err := newPolicyRule(config.Default, baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("default: %w", err)
+	}
+
+	rules := make([]policyRule, 0, len(config.Rules))
+	for i, rule := range config.Rules {
+		scope := strings.TrimSuffix(rule.Scope, "/")
+		if scope == "" {
+			return nil, fmt.Errorf("rules[%d]: scope must not be empty", i)
+		}
+
+		r, err := newPolicyRule(rule, baseDir)
+		if err != nil {
+			return nil, fmt.Errorf("rules[%d]: %w", i, err)
+		}
+		r.scope = scope
+		rules = append(rules, r)
+	}
+	return &Policy{defaultRule: defaultRule, rules: rules}, nil
+}
+
+func newPolicyRule(rule Rule, baseDir string) (policyRule, error) {
+	switch rule.Type {
+	case RuleTypeRequireSignature:
+		if len(rule.Keys) == 0 {
+			return policyRule{}, fmt.Errorf("rule of type %s requires keys", rule.Type)
+		}
+
+		keys := make([]crypto.PublicKey, 0, len(rule.Keys))
+		for _, path := range rule.Keys {
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(baseDir, path)
+			}
+			key, err := LoadPublicKey(path)
+			if err != nil {
+				return policyRule{}, fmt.Errorf("error loading key %s: %w", path, err)
+			}
+			keys = append(keys, key)
+		}
+		return policyRule{ruleType: rule.Type, verifier: NewVerifier(keys...)}, nil
+	case RuleTypeAllowUnsigned, RuleTypeReject:
+		if len(rule.Keys) > 0 {
+			return policyRule{}, fmt.Errorf("rule of type %s must not specify keys", rule.Type)
+		}
+		return policyRule{ruleType: rule.Type}, nil
+	case "":
+		return policyRule{}, fmt.Errorf("type must not be empty")
+	default:
+		return policyRule{}, fmt.Errorf("unknown rule type %q", rule.Type)
+	}
+}
+
+// matches reports whether the scope matches the given repository name on a path component boundary.
+func (r *policyRule) matches(name string) bool {
+	return name == r.scope || strings.HasPrefix(name, r.scope+"/")
+}
+
+func (p *Policy) rule(name string) *policyRule {
+	var res *policyRule
+	for i := range p.rules {
+		rule := &p.rules[i]
+		if rule.matches(name) && (res == nil || len(rule.scope) > len(res.scope)) {
+			res = rule
+		}
+	}
+	if res == nil {
+		return &p.defaultRule
+	}
+	return res
+}
+
+// RuleType returns the type of the rule applying to the repository of ref.
+func (p *Policy) RuleType(ref string) (RuleType, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", fmt.Errorf("error parsing reference %s: %w", ref, err)
+	}
+	return p.rule(named.Name()).ruleType, nil
+}
+
+// Verify implements image.Verifier.
+func (p *Policy) Verify(ctx context.Context, src image.ReferrersSource, ref string, desc ocispec.Descriptor) error {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return fmt.Errorf("error parsing reference %s: %w", ref, err)
+	}
+
+	rule := p.rule(named.Name())
+	switch rule.ruleType {
+	case RuleTypeAllowUnsigned:
+		return nil
+	case RuleTypeRequireSignature:
+		return rule.verifier.Verify(ctx, src, ref, desc)
+	default:
+		return fmt.Errorf("%w: %s", ErrRejected, named.Name())
+	}
+}
@@ -0,0 +1,246 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package signature implements signing and verifying images with keys.
+//
+// Signatures are stored as OCI referrer artifacts in the cosign simple signing format:
+// The artifact has a single layer holding the JSON payload that names the signed manifest digest,
+// and the base64 encoded signature of that payload is stored in the layer annotations.
+package signature
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/ironcore-dev/ironcore-image/oci/imageutil"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// ArtifactType is the artifact type of signature artifacts.
+	ArtifactType = "application/vnd.dev.cosign.artifact.sig.v1+json"
+	// PayloadMediaType is the media type of the signed payload layer.
+	PayloadMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// SignatureAnnotation is the payload layer annotation holding the base64 encoded signature.
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// KeyIDAnnotation is the artifact annotation holding the ID of the signing key (see KeyID).
+	KeyIDAnnotation = "dev.ironcore.image.signature.key-id"
+
+	payloadType = "cosign container image signature"
+)
+
+var (
+	// ErrUnsigned is returned if an image has no signatures.
+	ErrUnsigned = image.ErrUnsigned
+	// ErrNoValidSignature is returned if an image has signatures, but none is valid for the given keys.
+	ErrNoValidSignature = errors.New("no valid signature found")
+)
+
+// Payload is the signed payload of a signature.
+type Payload struct {
+	Critical Critical          `json:"critical"`
+	Optional map[string]string `json:"optional"`
+}
+
+// Critical is the critical section of a Payload, identifying the signed image.
+type Critical struct {
+	Identity Identity `json:"identity"`
+	Image    Image    `json:"image"`
+	Type     string   `json:"type"`
+}
+
+// Identity identifies the repository of the signed image.
+type Identity struct {
+	DockerReference string `json:"docker-reference"`
+}
+
+// Image identifies the signed manifest.
+type Image struct {
+	DockerManifestDigest digest.Digest `json:"docker-manifest-digest"`
+}
+
+// Sign creates a signature artifact for the manifest described by subject in the repository of ref.
+// The returned artifact refers to subject and can be stored via an image.ReferrersSink.
+func Sign(subject ocispec.Descriptor, ref string, key crypto.Signer, annotations map[string]string) (image.Image, error) {
+	payload, err := json.Marshal(Payload{
+		Critical: Critical{
+			Identity: Identity{DockerReference: repositoryName(ref)},
+			Image:    Image{DockerManifestDigest: subject.Digest},
+			Type:     payloadType,
+		},
+		Optional: annotations,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling payload: %w", err)
+	}
+
+	sig, err := sign(key, payload)
+	if err != nil {
+		return nil, fmt.Errorf("error signing payload: %w", err)
+	}
+
+	keyID, err := KeyID(key.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	return imageutil.NewArtifactBuilder(ArtifactType).
+		Subject(subject).
+		Annotations(map[string]string{
+			KeyIDAnnotation:           keyID,
+			ocispec.AnnotationCreated: time.Now().UTC().Format(time.RFC3339),
+		}).
+		BytesLayer(payload,
+			imageutil.WithMediaType(PayloadMediaType),
+			imageutil.WithAnnotations(map[string]string{SignatureAnnotation: base64.StdEncoding.EncodeToString(sig)}),
+		).
+		Complete()
+}
+
+// repositoryName returns the normalized repository name of ref, e.g. "docker.io/library/foo" for "foo:bar",
+// or an empty string if ref does not name a repository, e.g. if it is a plain digest.
+func repositoryName(ref string) string {
+	parsed, err := reference.ParseAnyReference(ref)
+	if err != nil {
+		return ""
+	}
+	named, ok := parsed.(reference.Named)
+	if !ok {
+		return ""
+	}
+	return named.Name()
+}
+
+// Signature is a verified signature.
+type Signature struct {
+	// Descriptor is the descriptor of the signature artifact.
+	Descriptor ocispec.Descriptor `json:"descriptor"`
+	// KeyID is the ID of the key the signature was verified with.
+	KeyID string `json:"keyID"`
+	// Payload is the verified payload.
+	Payload Payload `json:"payload"`
+}
+
+// Verifier verifies that images are signed by at least one of its keys.
+//
+// The identity of a valid signature names the repository of the reference the image is verified for,
+// so signatures cannot be replayed for the same manifest in other repositories. References that do not
+// name a repository, e.g. plain digests, are only verified against the signed manifest digest.
+type Verifier struct {
+	keys []crypto.PublicKey
+}
+
+// NewVerifier returns a new Verifier accepting signatures of any of the given keys.
+func NewVerifier(keys ...crypto.PublicKey) *Verifier {
+	return &Verifier{keys: keys}
+}
+
+// LoadVerifier returns a new Verifier accepting signatures of the PEM encoded public keys at the given paths.
+func LoadVerifier(paths ...string) (*Verifier, error) {
+	keys := make([]crypto.PublicKey, 0, len(paths))
+	for _, path := range paths {
+		key, err := LoadPublicKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("error loading key %s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+	return NewVerifier(keys...), nil
+}
+
+// Verify implements image.Verifier.
+func (v *Verifier) Verify(ctx context.Context, src image.ReferrersSource, ref string, desc ocispec.Descriptor) error {
+	_, err := v.VerifySignature(ctx, src, ref, desc.Digest)
+	return err
+}
+
+// VerifySignature returns the first signature of subject that is valid for any of the keys of the Verifier.
+// If subject has no signatures, ErrUnsigned is returned. If none of the signatures is valid, ErrNoValidSignature.
+func (v *Verifier) VerifySignature(ctx context.Context, src image.ReferrersSource, ref string, subject digest.Digest) (*Signature, error) {
+	descs, err := src.Referrers(ctx, ref, subject, ArtifactType)
+	if err != nil {
+		return nil, fmt.Errorf("error listing signatures of %s: %w", subject, err)
+	}
+	if len(descs) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnsigned, subject)
+	}
+
+	var errs []error
+	for _, desc := range descs {
+		signature, err := v.verifyArtifact(ctx, src, ref, subject, desc)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("signature %s: %w", desc.Digest, err))
+			continue
+		}
+		return signature, nil
+	}
+	return nil, fmt.Errorf("%w for %s: %w", ErrNoValidSignature, subject, errors.Join(errs...))
+}
+
+func (v *Verifier) verifyArtifact(ctx context.Context, src image.ReferrersSource, ref string, subject digest.Digest, desc ocispec.Descriptor) (*Signature, error) {
+	artifact, err := src.Referrer(ctx, ref, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	layers, err := artifact.Layers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting layers: %w", err)
+	}
+
+	for _, layer := range layers {
+		layerDesc := layer.Descriptor()
+		if layerDesc.MediaType != PayloadMediaType {
+			continue
+		}
+		// Payloads are tiny, refuse to read anything unreasonably large.
+		if layerDesc.Size > 1<<20 {
+			return nil, fmt.Errorf("payload too large: %d bytes", layerDesc.Size)
+		}
+
+		sig, err := base64.StdEncoding.DecodeString(layerDesc.Annotations[SignatureAnnotation])
+		if err != nil {
+			return nil, fmt.Errorf("error decoding signature: %w", err)
+		}
+
+		payloadData, err := imageutil.ReadLayerContent(ctx, layer)
+		if err != nil {
+			return nil, fmt.Errorf("error reading payload: %w", err)
+		}
+		if dgst := digest.FromBytes(payloadData); dgst != layerDesc.Digest {
+			return nil, fmt.Errorf("payload digest mismatch: expected %s, got %s", layerDesc.Digest, dgst)
+		}
+
+		for _, key := range v.keys {
+			if !verify(key, payloadData, sig) {
+				continue
+			}
+
+			var payload Payload
+			if err := json.Unmarshal(payloadData, &payload); err != nil {
+				return nil, fmt.Errorf("error decoding payload: %w", err)
+			}
+			if payload.Critical.Image.DockerManifestDigest != subject {
+				return nil, fmt.Errorf("payload is for %s instead of %s", payload.Critical.Image.DockerManifestDigest, subject)
+			}
+			if name := repositoryName(ref); name != "" && payload.Critical.Identity.DockerReference != name {
+				return nil, fmt.Errorf("payload is for repository %q instead of %q", payload.Critical.Identity.DockerReference, name)
+			}
+
+			keyID, err := KeyID(key)
+			if err != nil {
+				return nil, err
+			}
+			return &Signature{Descriptor: desc, KeyID: keyID, Payload: payload}, nil
+		}
+	}
+	return nil, fmt.Errorf("not signed by any of the given keys")
+}
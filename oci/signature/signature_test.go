@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package signature_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/ironcore-dev/ironcore-image/oci/imageutil"
+	. "github.com/ironcore-dev/ironcore-image/oci/signature"
+	"github.com/ironcore-dev/ironcore-image/oci/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Signature", func() {
+	const ref = "example.org/foo:bar"
+
+	var (
+		ctx context.Context
+		s   *store.Store
+		img image.Image
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		var err error
+		s, err = store.NewMemory()
+		Expect(err).NotTo(HaveOccurred())
+
+		img, err = imageutil.NewBytesConfigBuilder([]byte("{}"), imageutil.WithMediaType("application/vnd.test.config")).
+			BytesLayer([]byte("layer"), imageutil.WithMediaType("application/vnd.test.layer")).
+			Complete()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Push(ctx, ref, img)).To(Succeed())
+	})
+
+	It("should parse PEM encoded keys", func() {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		privDER, err := x509.MarshalPKCS8PrivateKey(key)
+		Expect(err).NotTo(HaveOccurred())
+		priv, err := ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(priv.Public()).To(Equal(key.Public()))
+
+		pubDER, err := x509.MarshalPKIXPublicKey(key.Public())
+		Expect(err).NotTo(HaveOccurred())
+		pub, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pub).To(Equal(key.Public()))
+	})
+
+	It("should sign and verify an image", func() {
+		By("verifying the unsigned image")
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		verifier := NewVerifier(key.Public())
+		Expect(verifier.Verify(ctx, s, ref, img.Descriptor())).To(MatchError(ErrUnsigned))
+
+		By("signing the image")
+		sig, err := Sign(img.Descriptor(), ref, key, map[string]string{"foo": "bar"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.PushReferrer(ctx, ref, sig)).To(Succeed())
+
+		By("verifying the signed image")
+		signature, err := verifier.VerifySignature(ctx, s, ref, img.Descriptor().Digest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(signature.Descriptor.Digest).To(Equal(sig.Descriptor().Digest))
+		Expect(signature.Payload.Critical.Image.DockerManifestDigest).To(Equal(img.Descriptor().Digest))
+		Expect(signature.Payload.Critical.Identity.DockerReference).To(Equal("example.org/foo"))
+		Expect(signature.Payload.Optional).To(HaveKeyWithValue("foo", "bar"))
+
+		By("verifying the signed image with another key")
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(NewVerifier(otherKey.Public()).Verify(ctx, s, ref, img.Descriptor())).To(MatchError(ErrNoValidSignature))
+	})
+
+	It("should reject signatures for other repositories", func() {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		verifier := NewVerifier(key.Public())
+
+		By("signing the image for another repository")
+		sig, err := Sign(img.Descriptor(), "example.org/other:bar", key, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.PushReferrer(ctx, ref, sig)).To(Succeed())
+
+		err = verifier.Verify(ctx, s, ref, img.Descriptor())
+		Expect(err).To(MatchError(ErrNoValidSignature))
+		Expect(err).To(MatchError(ContainSubstring(`payload is for repository "example.org/other" instead of "example.org/foo"`)))
+
+		By("verifying the image by digest")
+		Expect(verifier.Verify(ctx, s, img.Descriptor().Digest.String(), img.Descriptor())).To(Succeed())
+
+		By("signing the image for its repository")
+		sig, err = Sign(img.Descriptor(), "example.org/foo@"+img.Descriptor().Digest.String(), key, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.PushReferrer(ctx, ref, sig)).To(Succeed())
+		Expect(verifier.Verify(ctx, s, ref, img.Descriptor())).To(Succeed())
+	})
+
+	Describe("Policy", func() {
+		It("should apply the rule with the most specific scope", func() {
+			By("writing a public key")
+			_, key, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			pubDER, err := x509.MarshalPKIXPublicKey(key.Public())
+			Expect(err).NotTo(HaveOccurred())
+			dir := GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(dir, "key.pub"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0644)).To(Succeed())
+
+			policy, err := NewPolicy(PolicyConfig{
+				Default: Rule{Type: RuleTypeReject},
+				Rules: []Rule{
+					{Scope: "example.org", Type: RuleTypeAllowUnsigned},
+					{Scope: "example.org/foo", Type: RuleTypeRequireSignature, Keys: []string{"key.pub"}},
+				},
+			}, dir)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(policy.Verify(ctx, s, "example.com/foo:bar", img.Descriptor())).To(MatchError(ErrRejected))
+			Expect(policy.Verify(ctx, s, "example.org/foobar:bar", img.Descriptor())).To(Succeed())
+			Expect(policy.Verify(ctx, s, ref, img.Descriptor())).To(MatchError(ErrUnsigned))
+
+			By("signing the image")
+			sig, err := Sign(img.Descriptor(), ref, key, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.PushReferrer(ctx, ref, sig)).To(Succeed())
+			Expect(policy.Verify(ctx, s, ref, img.Descriptor())).To(Succeed())
+		})
+
+		It("should reject invalid rules", func() {
+			_, err := NewPolicy(PolicyConfig{Default: Rule{Type: RuleTypeRequireSignature}}, "")
+			Expect(err).To(MatchError(ContainSubstring("requires keys")))
+
+			_, err = NewPolicy(PolicyConfig{
+				Default: Rule{Type: RuleTypeReject},
+				Rules:   []Rule{{Type: RuleTypeAllowUnsigned}},
+			}, "")
+			Expect(err).To(MatchError(ContainSubstring("scope must not be empty")))
+		})
+	})
+})
@@ -0,0 +1,287 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/errdefs"
+	"github.com/ironcore-dev/ironcore-image/oci/descriptormatcher"
+	"github.com/ironcore-dev/ironcore-image/utils/sets"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ErrInUse is returned if an operation would release content pinned by a lease.
+var ErrInUse = errors.New("content in use")
+
+// maxManifestSize is the maximum size of a blob that is considered when looking for references.
+const maxManifestSize = 4 << 20
+
+// manifestOrIndex contains the referencing fields of both ocispec.Manifest and ocispec.Index.
+type manifestOrIndex struct {
+	MediaType string               `json:"mediaType,omitempty"`
+	Config    *ocispec.Descriptor  `json:"config,omitempty"`
+	Layers    []ocispec.Descriptor `json:"layers,omitempty"`
+	Manifests []ocispec.Descriptor `json:"manifests,omitempty"`
+}
+
+// children returns the descriptors referenced by the manifest or index blob of dgst.
+// Blobs that are not present or are no manifest or index have no children.
+func (s *Store) children(ctx context.Context, dgst digest.Digest) ([]ocispec.Descriptor, error) {
+	cs := s.layout.ContentStore()
+	info, err := cs.Info(ctx, dgst)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting info for %s: %w", dgst, err)
+	}
+	if info.Size > maxManifestSize {
+		return nil, nil
+	}
+
+	data, err := content.ReadBlob(ctx, cs, ocispec.Descriptor{Digest: dgst, Size: info.Size})
+	if err != nil {
+		return nil, fmt.Errorf("error reading blob %s: %w", dgst, err)
+	}
+
+	var m manifestOrIndex
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, nil
+	}
+
+	var res []ocispec.Descriptor
+	if m.Config != nil {
+		res = append(res, *m.Config)
+	}
+	res = append(res, m.Layers...)
+	res = append(res, m.Manifests...)
+	return res, nil
+}
+
+// reachable returns the digests of the given roots and all content transitively referenced by them.
+func (s *Store) reachable(ctx context.Context, roots ...digest.Digest) (sets.Set[digest.Digest], error) {
+	marked := sets.New[digest.Digest]()
+	queue := append([]digest.Digest(nil), roots...)
+	for len(queue) > 0 {
+		dgst := queue[0]
+		queue = queue[1:]
+		if marked.Has(dgst) {
+			continue
+		}
+		marked.Insert(dgst)
+
+		children, err := s.children(ctx, dgst)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			queue = append(queue, child.Digest)
+		}
+	}
+	return marked, nil
+}
+
+// reachableWithReferrers returns the digests reachable from the given roots like reachable, including
+// the referrers whose keeping image (see ReferrerAnnotation) is reachable and all content reachable from them.
+func (s *Store) reachableWithReferrers(ctx context.Context, referrers []ocispec.Descriptor, roots ...digest.Digest) (sets.Set[digest.Digest], error) {
+	marked, err := s.reachable(ctx, roots...)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var kept []digest.Digest
+		for _, desc := range referrers {
+			if !marked.Has(desc.Digest) && marked.Has(digest.Digest(desc.Annotations[ReferrerAnnotation])) {
+				kept = append(kept, desc.Digest)
+			}
+		}
+		if len(kept) == 0 {
+			return marked, nil
+		}
+
+		more, err := s.reachable(ctx, kept...)
+		if err != nil {
+			return nil, err
+		}
+		for dgst := range more {
+			marked.Insert(dgst)
+		}
+	}
+}
+
+func descriptorDigests(descs []ocispec.Descriptor) []digest.Digest {
+	res := make([]digest.Digest, 0, len(descs))
+	for _, desc := range descs {
+		res = append(res, desc.Digest)
+	}
+	return res
+}
+
+// isTagged reports whether the index entry desc is a tag.
+func isTagged(desc ocispec.Descriptor) bool {
+	return desc.Annotations[ocispec.AnnotationRefName] != ""
+}
+
+// deleteIndexEntries deletes all index entries matching, unless this would release content pinned by a lease.
+// Untagged entries only left for content of the deleted entries, e.g. those added by Put before tagging, are
+// deleted as well, so the content can be garbage collected.
+func (s *Store) deleteIndexEntries(ctx context.Context, match descriptormatcher.Matcher) error {
+	descs, err := s.layout.Indexer().List(ctx, descriptormatcher.Every)
+	if err != nil {
+		return fmt.Errorf("error listing index entries: %w", err)
+	}
+
+	var deleted, remaining []ocispec.Descriptor
+	for _, desc := range descs {
+		if match(desc) {
+			deleted = append(deleted, desc)
+		} else {
+			remaining = append(remaining, desc)
+		}
+	}
+	if len(deleted) == 0 {
+		return s.layout.Indexer().Delete(ctx, match)
+	}
+
+	pinned, err := s.leases.Pinned(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing pinned content: %w", err)
+	}
+	released, err := s.reachable(ctx, descriptorDigests(deleted)...)
+	if err != nil {
+		return err
+	}
+	if len(pinned) > 0 {
+		after, err := s.reachable(ctx, descriptorDigests(remaining)...)
+		if err != nil {
+			return err
+		}
+		for dgst := range pinned {
+			if released.Has(dgst) && !after.Has(dgst) {
+				return fmt.Errorf("%w: %s is pinned by a lease", ErrInUse, dgst)
+			}
+		}
+	}
+
+	// Untagged entries for released content are only kept if they are still reachable otherwise.
+	var roots []digest.Digest
+	for _, desc := range remaining {
+		if isTagged(desc) || !released.Has(desc.Digest) {
+			roots = append(roots, desc.Digest)
+		}
+	}
+	for dgst := range pinned {
+		roots = append(roots, dgst)
+	}
+	kept, err := s.reachable(ctx, roots...)
+	if err != nil {
+		return err
+	}
+	stale := func(desc ocispec.Descriptor) bool {
+		return !isTagged(desc) && released.Has(desc.Digest) && !kept.Has(desc.Digest)
+	}
+
+	return s.layout.Indexer().Delete(ctx, func(desc ocispec.Descriptor) bool {
+		return match(desc) || stale(desc)
+	})
+}
+
+// GCOptions are options for GarbageCollect.
+type GCOptions struct {
+	// DryRun only reports what would be removed without removing anything.
+	DryRun bool
+}
+
+// GCResult is the result of a garbage collection.
+type GCResult struct {
+	// ExpiredLeases are the IDs of the expired leases that were removed.
+	ExpiredLeases []string `json:"expiredLeases"`
+	// Removed are the digests of the removed blobs.
+	Removed []digest.Digest `json:"removed"`
+	// ReclaimedBytes is the total size of the removed blobs.
+	ReclaimedBytes int64 `json:"reclaimedBytes"`
+}
+
+// GarbageCollect removes all blobs that are neither reachable from the index nor pinned by a lease.
+// Referrers are only kept while the image keeping them is, see ReferrerAnnotation. Expired leases are removed before collecting. The store is locked exclusively meanwhile, so no blobs
+// of an image being put concurrently are removed.
+func (s *Store) GarbageCollect(ctx context.Context, opts GCOptions) (*GCResult, error) {
+	unlock, err := s.lock(true)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = unlock() }()
+
+	res := &GCResult{}
+	if !opts.DryRun {
+		expired, err := s.leases.DeleteExpired(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error deleting expired leases: %w", err)
+		}
+		res.ExpiredLeases = expired
+	}
+
+	pinned, err := s.leases.Pinned(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing pinned content: %w", err)
+	}
+
+	descs, err := s.layout.Indexer().List(ctx, descriptormatcher.Every)
+	if err != nil {
+		return nil, fmt.Errorf("error listing index entries: %w", err)
+	}
+
+	var roots []digest.Digest
+	var referrers []ocispec.Descriptor
+	for _, desc := range descs {
+		if IsReferrer(desc) {
+			referrers = append(referrers, desc)
+		} else {
+			roots = append(roots, desc.Digest)
+		}
+	}
+	for dgst := range pinned {
+		roots = append(roots, dgst)
+	}
+	marked, err := s.reachableWithReferrers(ctx, referrers, roots...)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.DryRun {
+		if err := s.layout.Indexer().Delete(ctx, func(desc ocispec.Descriptor) bool {
+			return IsReferrer(desc) && !marked.Has(desc.Digest)
+		}); err != nil {
+			return nil, fmt.Errorf("error deleting index entries of collected referrers: %w", err)
+		}
+	}
+
+	cs := s.layout.ContentStore()
+	var unreferenced []content.Info
+	if err := cs.Walk(ctx, func(info content.Info) error {
+		if !marked.Has(info.Digest) {
+			unreferenced = append(unreferenced, info)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("error walking content: %w", err)
+	}
+
+	for _, info := range unreferenced {
+		if !opts.DryRun {
+			if err := cs.Delete(ctx, info.Digest); err != nil && !errdefs.IsNotFound(err) {
+				return nil, fmt.Errorf("error deleting blob %s: %w", info.Digest, err)
+			}
+		}
+		res.Removed = append(res.Removed, info.Digest)
+		res.ReclaimedBytes += info.Size
+	}
+	return res, nil
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/containerd/errdefs"
+	ocicontent "github.com/ironcore-dev/ironcore-image/oci/content"
+	"github.com/ironcore-dev/ironcore-image/oci/descriptormatcher"
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/ironcore-dev/ironcore-image/utils/sets"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ReferrerAnnotation marks the index entries of referrers stored via PushReferrer, so they are not listed
+// as images. Its value is the digest of the image keeping the referrer: its subject or, if the subject is
+// not stored (e.g. the index a pulled manifest was selected from), the image the referrer was stored for.
+const ReferrerAnnotation = "dev.ironcore.image.referrer"
+
+// IsReferrer reports whether the index entry desc is a referrer stored via PushReferrer.
+func IsReferrer(desc ocispec.Descriptor) bool {
+	_, ok := desc.Annotations[ReferrerAnnotation]
+	return ok
+}
+
+// Referrers lists the manifests in the store whose subject is the given digest.
+// If artifactType is not empty, only referrers of that artifact type are returned.
+// The ref is ignored, as the store does not separate repositories.
+func (s *Store) Referrers(ctx context.Context, ref string, subject digest.Digest, artifactType string) ([]ocispec.Descriptor, error) {
+	descs, err := s.layout.Indexer().List(ctx, descriptormatcher.MediaTypes(ocispec.MediaTypeImageManifest))
+	if err != nil {
+		return nil, fmt.Errorf("error listing manifests: %w", err)
+	}
+
+	var (
+		res  []ocispec.Descriptor
+		seen = sets.New[digest.Digest]()
+	)
+	for _, desc := range descs {
+		if seen.Has(desc.Digest) {
+			continue
+		}
+		seen.Insert(desc.Digest)
+
+		manifest, err := ocicontent.Image(s.layout.ContentStore(), desc).Manifest(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error reading manifest %s: %w", desc.Digest, err)
+		}
+		if manifest.Subject == nil || manifest.Subject.Digest != subject {
+			continue
+		}
+
+		referrer := image.ReferrerDescriptor(desc, manifest)
+		if artifactType != "" && referrer.ArtifactType != artifactType {
+			continue
+		}
+		res = append(res, referrer)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Digest < res[j].Digest })
+	return res, nil
+}
+
+// Referrer returns the referrer manifest described by desc.
+func (s *Store) Referrer(ctx context.Context, ref string, desc ocispec.Descriptor) (image.Image, error) {
+	if _, err := s.layout.ContentStore().Info(ctx, desc.Digest); err != nil {
+		return nil, fmt.Errorf("error getting referrer %s: %w", desc.Digest, err)
+	}
+	return ocicontent.Image(s.layout.ContentStore(), desc), nil
+}
+
+// PushReferrer stores the referrer manifest untagged. It can be found via Referrers of its subject and is
+// garbage collected once the image keeping it (see ReferrerAnnotation) is no longer reachable.
+func (s *Store) PushReferrer(ctx context.Context, ref string, img image.Image) error {
+	manifest, err := img.Manifest(ctx)
+	if err != nil {
+		return fmt.Errorf("error reading referrer manifest: %w", err)
+	}
+	if manifest.Subject == nil {
+		return fmt.Errorf("referrer %s has no subject", img.Descriptor().Digest)
+	}
+
+	keep := manifest.Subject.Digest
+	if _, err := s.layout.ContentStore().Info(ctx, keep); errdefs.IsNotFound(err) {
+		if desc, err := s.resolveDescriptor(ctx, ref); err == nil {
+			keep = desc.Digest
+		}
+	}
+
+	unlock, err := s.lock(false)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = unlock() }()
+
+	if err := ocicontent.WriteImageToIngester(ctx, s.layout.ContentStore(), img); err != nil {
+		return fmt.Errorf("error writing referrer: %w", err)
+	}
+	desc := ocispec.Descriptor{
+		MediaType:   img.Descriptor().MediaType,
+		Digest:      img.Descriptor().Digest,
+		Size:        img.Descriptor().Size,
+		Annotations: map[string]string{ReferrerAnnotation: keep.String()},
+	}
+	if err := s.layout.Indexer().Replace(ctx, desc, descriptormatcher.And(descriptormatcher.Digests(desc.Digest), IsReferrer)); err != nil {
+		return fmt.Errorf("error indexing referrer: %w", err)
+	}
+	return nil
+}
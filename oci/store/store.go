@@ -6,19 +6,63 @@ package store
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"sync"
 
 	"github.com/distribution/reference"
+	ocicontent "github.com/ironcore-dev/ironcore-image/oci/content"
 	"github.com/ironcore-dev/ironcore-image/oci/descriptormatcher"
 	"github.com/ironcore-dev/ironcore-image/oci/image"
 	"github.com/ironcore-dev/ironcore-image/oci/layout"
+	"github.com/ironcore-dev/ironcore-image/oci/lease"
+	"github.com/ironcore-dev/ironcore-image/utils/fsutil"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
+// LockFilename is the name of the file in the store directory used to lock the store.
+const LockFilename = "store.lock"
+
 type Store struct {
 	layout *layout.Layout
+	leases *lease.Manager
+
+	// mu locks the store within the process, the file at lockPath across processes.
+	mu       sync.RWMutex
+	lockPath string
+}
+
+// lock locks the store against garbage collection. Writes hold the lock shared, so they can run
+// concurrently, while a garbage collection holds it exclusively.
+func (s *Store) lock(exclusive bool) (unlock func() error, err error) {
+	lock, unlockMu, fsLock := s.mu.RLock, s.mu.RUnlock, fsutil.RLock
+	if exclusive {
+		lock, unlockMu, fsLock = s.mu.Lock, s.mu.Unlock, fsutil.Lock
+	}
+
+	lock()
+	if s.lockPath == "" {
+		return func() error { unlockMu(); return nil }, nil
+	}
+	unlockFS, err := fsLock(s.lockPath)
+	if err != nil {
+		unlockMu()
+		return nil, fmt.Errorf("error locking store: %w", err)
+	}
+	return func() error {
+		defer unlockMu()
+		return unlockFS()
+	}, nil
 }
 
+// Put writes the image to the store and indexes it. The store is locked meanwhile, so a concurrent
+// garbage collection does not remove blobs already written but not indexed yet.
 func (s *Store) Put(ctx context.Context, img image.Image) error {
+	unlock, err := s.lock(false)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = unlock() }()
+
 	desc := ocispec.Descriptor{MediaType: img.Descriptor().MediaType, Digest: img.Descriptor().Digest}
 	if err := s.layout.ReplaceImage(ctx, img, descriptormatcher.Equal(desc)); err != nil {
 		return fmt.Errorf("could not create image: %w", err)
@@ -37,6 +81,12 @@ func (s *Store) Push(ctx context.Context, ref string, img image.Image) error {
 }
 
 func (s *Store) PushIndexManifest(ctx context.Context, indexImage image.Image, indexManifest *ocispec.Index, ref string) error {
+	unlock, err := s.lock(false)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = unlock() }()
+
 	if err := s.layout.AddIndexManifest(ctx, indexManifest); err != nil {
 		return fmt.Errorf("error adding index manifest: %w", err)
 	}
@@ -84,7 +134,7 @@ func (s *Store) Delete(ctx context.Context, ref string) error {
 		return err
 	}
 
-	if err := s.layout.Indexer().Delete(ctx, match); err != nil {
+	if err := s.deleteIndexEntries(ctx, match); err != nil {
 		return fmt.Errorf("error deleting ref %s from indexer: %w", ref, err)
 	}
 	return nil
@@ -119,6 +169,33 @@ func (s *Store) Resolve(ctx context.Context, ref string) (image.Image, error) {
 	}
 }
 
+// ResolveArch resolves the ref like Resolve. If the ref points to an index,
+// the manifest for the given architecture is resolved instead.
+func (s *Store) ResolveArch(ctx context.Context, ref, arch string) (image.Image, error) {
+	img, err := s.Resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	indexManifest, err := ocicontent.GetIndexManifest(ctx, img)
+	if err != nil {
+		return img, nil
+	}
+
+	for _, manifest := range indexManifest.Manifests {
+		if manifest.Platform == nil || manifest.Platform.Architecture != arch {
+			continue
+		}
+
+		archImg, err := s.Resolve(ctx, manifest.Digest.String())
+		if err != nil {
+			return nil, fmt.Errorf("error resolving manifest %s for arch %s: %w", manifest.Digest, arch, err)
+		}
+		return archImg, nil
+	}
+	return nil, fmt.Errorf("index %s contains no manifest for arch %s", ref, arch)
+}
+
 func (s *Store) Tag(ctx context.Context, srcRef, dstRef string) error {
 	if _, err := reference.ParseNamed(dstRef); err != nil {
 		return fmt.Errorf("destination has to be a named reference: %w", err)
@@ -149,7 +226,7 @@ func (s *Store) Untag(ctx context.Context, ref string) error {
 	if _, err := reference.ParseNamed(ref); err != nil {
 		return fmt.Errorf("ref has to be a named reference: %w", err)
 	}
-	if err := s.layout.Indexer().Delete(ctx, descriptormatcher.Name(ref)); err != nil {
+	if err := s.deleteIndexEntries(ctx, descriptormatcher.Name(ref)); err != nil {
 		return fmt.Errorf("error removing index entries: %w", err)
 	}
 	return nil
@@ -159,10 +236,29 @@ func (s *Store) Layout() *layout.Layout {
 	return s.layout
 }
 
+// Leases returns the lease.Manager pinning content of the store.
+func (s *Store) Leases() *lease.Manager {
+	return s.leases
+}
+
 func New(path string) (*Store, error) {
 	l, err := layout.New(path)
 	if err != nil {
 		return nil, fmt.Errorf("could not created oci layout: %w", err)
 	}
-	return &Store{layout: l}, nil
+
+	leases, err := lease.New(filepath.Join(path, lease.Filename))
+	if err != nil {
+		return nil, fmt.Errorf("could not create lease manager: %w", err)
+	}
+	return &Store{layout: l, leases: leases, lockPath: filepath.Join(path, LockFilename)}, nil
+}
+
+// NewMemory returns a new Store that keeps all images and tags in memory.
+func NewMemory() (*Store, error) {
+	l, err := layout.NewMemory()
+	if err != nil {
+		return nil, fmt.Errorf("could not create in-memory oci layout: %w", err)
+	}
+	return &Store{layout: l, leases: lease.NewMemory()}, nil
 }
@@ -0,0 +1,415 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package store_test
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/errdefs"
+	"github.com/ironcore-dev/ironcore-image/oci/descriptormatcher"
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/ironcore-dev/ironcore-image/oci/imageutil"
+	"github.com/ironcore-dev/ironcore-image/oci/indexer"
+	"github.com/ironcore-dev/ironcore-image/oci/lease"
+	. "github.com/ironcore-dev/ironcore-image/oci/store"
+	"github.com/ironcore-dev/ironcore-image/utils/fsutil"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+var _ = Describe("Store", func() {
+	var (
+		ctx context.Context
+		s   *Store
+		img image.Image
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		var err error
+		s, err = NewMemory()
+		Expect(err).NotTo(HaveOccurred())
+
+		img, err = imageutil.NewBytesConfigBuilder([]byte("{}"), imageutil.WithMediaType("application/vnd.test.config")).
+			BytesLayer([]byte("layer"), imageutil.WithMediaType("application/vnd.test.layer")).
+			Complete()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("NewMemory", func() {
+		It("should push and resolve an image", func() {
+			By("pushing the image")
+			Expect(s.Push(ctx, "example.org/foo:bar", img)).To(Succeed())
+
+			By("resolving the image by tag")
+			res, err := s.Resolve(ctx, "example.org/foo:bar")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Descriptor().Digest).To(Equal(img.Descriptor().Digest))
+
+			By("inspecting the layers")
+			layers, err := res.Layers(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(layers).To(HaveLen(1))
+			Expect(imageutil.ReadLayerContent(ctx, layers[0])).To(Equal([]byte("layer")))
+
+			By("reading a range of the layer")
+			ra, err := image.LayerReaderAt(ctx, layers[0])
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = ra.Close() }()
+			Expect(ra.Size()).To(Equal(int64(5)))
+			p := make([]byte, 3)
+			Expect(ra.ReadAt(p, 1)).To(Equal(3))
+			Expect(p).To(Equal([]byte("aye")))
+
+			By("resolving the image by digest")
+			res, err = s.Resolve(ctx, img.Descriptor().Digest.String())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Descriptor().Digest).To(Equal(img.Descriptor().Digest))
+		})
+
+		It("should tag and untag an image", func() {
+			Expect(s.Push(ctx, "example.org/foo:bar", img)).To(Succeed())
+
+			By("tagging the image")
+			Expect(s.Tag(ctx, "example.org/foo:bar", "example.org/foo:baz")).To(Succeed())
+			res, err := s.Resolve(ctx, "example.org/foo:baz")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Descriptor().Digest).To(Equal(img.Descriptor().Digest))
+
+			By("untagging the image")
+			Expect(s.Untag(ctx, "example.org/foo:baz")).To(Succeed())
+			_, err = s.Resolve(ctx, "example.org/foo:baz")
+			Expect(err).To(MatchError(indexer.ErrNotFound))
+		})
+
+		It("should copy an image between stores", func() {
+			Expect(s.Push(ctx, "example.org/foo:bar", img)).To(Succeed())
+
+			dst, err := NewMemory()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = image.Copy(ctx, dst, s, "example.org/foo:bar")
+			Expect(err).NotTo(HaveOccurred())
+
+			res, err := dst.Resolve(ctx, "example.org/foo:bar")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Descriptor().Digest).To(Equal(img.Descriptor().Digest))
+		})
+
+		It("should not be backed by a local store", func() {
+			Expect(s.Layout().Store()).To(BeNil())
+			Expect(s.Layout().ContentStore()).NotTo(BeNil())
+
+			_, err := s.Layout().ContentStore().Info(ctx, "sha256:0000000000000000000000000000000000000000000000000000000000000000")
+			Expect(errdefs.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Describe("GarbageCollect", func() {
+		It("should remove unreferenced blobs", func() {
+			Expect(s.Push(ctx, "example.org/foo:bar", img)).To(Succeed())
+			Expect(s.Delete(ctx, img.Descriptor().Digest.String())).To(Succeed())
+
+			res, err := s.GarbageCollect(ctx, GCOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Removed).To(HaveLen(3))
+
+			_, err = s.Layout().ContentStore().Info(ctx, img.Descriptor().Digest)
+			Expect(errdefs.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should reclaim the content of deleted images", func() {
+			other, err := imageutil.NewBytesConfigBuilder([]byte("{}"), imageutil.WithMediaType("application/vnd.test.config")).
+				BytesLayer([]byte("other"), imageutil.WithMediaType("application/vnd.test.layer")).
+				Complete()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("pushing an index like build does")
+			indexImg := pushIndex(s, "example.org/foo:bar", map[string]image.Image{"amd64": img, "arm64": other})
+
+			By("keeping the content while it is tagged")
+			Expect(s.Delete(ctx, "example.org/foo:bar")).To(Succeed())
+			Expect(s.Delete(ctx, "example.org/foo:bar-amd64")).To(Succeed())
+			res, err := s.GarbageCollect(ctx, GCOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Removed).To(ContainElements(indexImg.Descriptor().Digest, img.Descriptor().Digest))
+			Expect(res.Removed).NotTo(ContainElement(other.Descriptor().Digest))
+			Expect(s.Resolve(ctx, "example.org/foo:bar-arm64")).NotTo(BeNil())
+
+			By("reclaiming all content after the last tag is deleted")
+			Expect(s.Delete(ctx, "example.org/foo:bar-arm64")).To(Succeed())
+			Expect(s.Layout().Indexer().List(ctx, descriptormatcher.Every)).To(BeEmpty())
+			res, err = s.GarbageCollect(ctx, GCOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.ReclaimedBytes).To(BeNumerically(">", 0))
+
+			var blobs int
+			Expect(s.Layout().ContentStore().Walk(ctx, func(content.Info) error {
+				blobs++
+				return nil
+			})).To(Succeed())
+			Expect(blobs).To(BeZero())
+		})
+
+		It("should keep images that were never tagged", func() {
+			Expect(s.Put(ctx, img)).To(Succeed())
+
+			res, err := s.GarbageCollect(ctx, GCOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Removed).To(BeEmpty())
+		})
+
+		It("should wait for writes of other processes", func() {
+			dir := GinkgoT().TempDir()
+			s, err := New(dir)
+			Expect(err).NotTo(HaveOccurred())
+
+			unlock, err := fsutil.RLock(filepath.Join(dir, LockFilename))
+			Expect(err).NotTo(HaveOccurred())
+
+			done := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(done)
+				_, err := s.GarbageCollect(ctx, GCOptions{})
+				Expect(err).NotTo(HaveOccurred())
+			}()
+			Consistently(done, 100*time.Millisecond).ShouldNot(BeClosed())
+
+			Expect(unlock()).To(Succeed())
+			Eventually(done).Should(BeClosed())
+		})
+
+		It("should keep blobs pinned by a lease", func() {
+			Expect(s.Push(ctx, "example.org/foo:bar", img)).To(Succeed())
+			_, err := s.Leases().Create(ctx, lease.WithID("vm"), lease.WithResources(img.Descriptor().Digest))
+			Expect(err).NotTo(HaveOccurred())
+
+			By("refusing to delete the pinned image")
+			Expect(s.Delete(ctx, img.Descriptor().Digest.String())).To(MatchError(ErrInUse))
+
+			By("collecting garbage")
+			Expect(s.Layout().Indexer().Delete(ctx, descriptormatcher.Every)).To(Succeed())
+			res, err := s.GarbageCollect(ctx, GCOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Removed).To(BeEmpty())
+
+			By("collecting garbage after the lease is deleted")
+			Expect(s.Leases().Delete(ctx, "vm")).To(Succeed())
+			res, err = s.GarbageCollect(ctx, GCOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Removed).To(HaveLen(3))
+		})
+
+		It("should not pin content after putting an image", func() {
+			Expect(s.Push(ctx, "example.org/foo:bar", img)).To(Succeed())
+			Expect(s.Leases().List(ctx)).To(BeEmpty())
+		})
+
+		It("should collect an empty local store", func() {
+			s, err := New(GinkgoT().TempDir())
+			Expect(err).NotTo(HaveOccurred())
+
+			res, err := s.GarbageCollect(ctx, GCOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Removed).To(BeEmpty())
+		})
+
+		It("should remove expired leases", func() {
+			_, err := s.Leases().Create(ctx, lease.WithID("expired"), lease.WithExpiration(-time.Second))
+			Expect(err).NotTo(HaveOccurred())
+
+			res, err := s.GarbageCollect(ctx, GCOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.ExpiredLeases).To(ConsistOf("expired"))
+			Expect(s.Leases().List(ctx)).To(BeEmpty())
+		})
+	})
+
+	Describe("DiskUsage", func() {
+		It("should compute unique and shared sizes", func() {
+			other, err := imageutil.NewBytesConfigBuilder([]byte("{}"), imageutil.WithMediaType("application/vnd.test.config")).
+				BytesLayer([]byte("other"), imageutil.WithMediaType("application/vnd.test.layer")).
+				Complete()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(s.Push(ctx, "example.org/foo:bar", img)).To(Succeed())
+			Expect(s.Push(ctx, "example.org/foo:other", other)).To(Succeed())
+
+			usage, err := s.DiskUsage(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usage.Images).To(HaveLen(2))
+			Expect(usage.BlobCount).To(Equal(5))
+			Expect(usage.OrphanedBlobCount).To(BeZero())
+
+			for _, imgUsage := range usage.Images {
+				Expect(imgUsage.Names).To(HaveLen(1))
+				Expect(imgUsage.SharedSize).To(Equal(int64(len("{}"))))
+				Expect(imgUsage.UniqueSize).To(Equal(imgUsage.Size - imgUsage.SharedSize))
+			}
+		})
+
+		It("should report an empty local store", func() {
+			s, err := New(GinkgoT().TempDir())
+			Expect(err).NotTo(HaveOccurred())
+
+			usage, err := s.DiskUsage(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*usage).To(BeZero())
+			Expect(s.ImageSizes(ctx)).To(BeEmpty())
+		})
+	})
+
+	Describe("Referrers", func() {
+		It("should list and copy the referrers of an image", func() {
+			Expect(s.Push(ctx, "example.org/foo:bar", img)).To(Succeed())
+
+			By("attaching an artifact")
+			artifact, err := imageutil.NewArtifactBuilder("application/vnd.test.artifact").
+				Subject(img.Descriptor()).
+				BytesLayer([]byte("sbom"), imageutil.WithMediaType("application/vnd.test.sbom")).
+				Complete()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.PushReferrer(ctx, "example.org/foo:bar", artifact)).To(Succeed())
+
+			By("listing the referrers")
+			referrers, err := s.Referrers(ctx, "example.org/foo:bar", img.Descriptor().Digest, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(referrers).To(ConsistOf(HaveField("Digest", artifact.Descriptor().Digest)))
+			Expect(referrers[0].ArtifactType).To(Equal("application/vnd.test.artifact"))
+
+			By("filtering the referrers by artifact type")
+			Expect(s.Referrers(ctx, "example.org/foo:bar", img.Descriptor().Digest, "application/vnd.test.other")).To(BeEmpty())
+
+			By("copying the referrers to another store")
+			dst, err := NewMemory()
+			Expect(err).NotTo(HaveOccurred())
+			copied, err := image.CopyReferrers(ctx, dst, s, "example.org/foo:bar", img.Descriptor().Digest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(copied).To(HaveLen(1))
+			Expect(dst.Referrers(ctx, "example.org/foo:bar", img.Descriptor().Digest, "")).To(HaveLen(1))
+		})
+
+		It("should not treat referrers as images", func() {
+			Expect(s.Push(ctx, "example.org/foo:bar", img)).To(Succeed())
+			artifact := newArtifact(img.Descriptor(), "sbom")
+			Expect(s.PushReferrer(ctx, "example.org/foo:bar", artifact)).To(Succeed())
+
+			descs, err := s.Layout().Indexer().List(ctx, descriptormatcher.Digests(artifact.Descriptor().Digest))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(descs).To(ConsistOf(Satisfy(IsReferrer)))
+
+			usage, err := s.DiskUsage(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usage.Images).To(ConsistOf(HaveField("Digest", img.Descriptor().Digest)))
+			Expect(usage.ReferrerCount).To(Equal(1))
+			Expect(usage.ReferrerSize).To(BeNumerically(">", 0))
+			Expect(usage.OrphanedBlobCount).To(BeZero())
+		})
+
+		It("should collect referrers along with their subject", func() {
+			Expect(s.Push(ctx, "example.org/foo:bar", img)).To(Succeed())
+			artifact := newArtifact(img.Descriptor(), "sbom")
+			Expect(s.PushReferrer(ctx, "example.org/foo:bar", artifact)).To(Succeed())
+			signature := newArtifact(artifact.Descriptor(), "signature")
+			Expect(s.PushReferrer(ctx, "example.org/foo:bar", signature)).To(Succeed())
+
+			By("keeping the referrers while the subject is tagged")
+			res, err := s.GarbageCollect(ctx, GCOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Removed).To(BeEmpty())
+
+			By("collecting the referrers once the subject is deleted")
+			Expect(s.Delete(ctx, "example.org/foo:bar")).To(Succeed())
+			res, err = s.GarbageCollect(ctx, GCOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Removed).To(ContainElements(img.Descriptor().Digest, artifact.Descriptor().Digest, signature.Descriptor().Digest))
+			Expect(s.Layout().Indexer().List(ctx, descriptormatcher.Every)).To(BeEmpty())
+		})
+
+		It("should copy the referrers of an index along with the selected manifest", func() {
+			indexImg := pushIndex(s, "example.org/foo:bar", map[string]image.Image{"amd64": img})
+			indexArtifact := newArtifact(indexImg.Descriptor(), "provenance")
+			Expect(s.PushReferrer(ctx, "example.org/foo:bar", indexArtifact)).To(Succeed())
+			artifact := newArtifact(img.Descriptor(), "sbom")
+			Expect(s.PushReferrer(ctx, "example.org/foo:bar", artifact)).To(Succeed())
+
+			dst, err := NewMemory()
+			Expect(err).NotTo(HaveOccurred())
+			copied, referrers, err := image.CopyWithReferrers(ctx, dst, archSource{s}, "example.org/foo:bar")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(copied.Descriptor().Digest).To(Equal(img.Descriptor().Digest))
+			Expect(referrers).To(ConsistOf(
+				HaveField("Digest", indexArtifact.Descriptor().Digest),
+				HaveField("Digest", artifact.Descriptor().Digest),
+			))
+			Expect(dst.Referrers(ctx, "example.org/foo:bar", indexImg.Descriptor().Digest, "")).To(HaveLen(1))
+
+			By("keeping the referrers of the index while the manifest is tagged")
+			res, err := dst.GarbageCollect(ctx, GCOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Removed).To(BeEmpty())
+
+			By("collecting them once the manifest is deleted")
+			Expect(dst.Delete(ctx, "example.org/foo:bar")).To(Succeed())
+			res, err = dst.GarbageCollect(ctx, GCOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Removed).To(ContainElements(indexArtifact.Descriptor().Digest, artifact.Descriptor().Digest))
+		})
+	})
+})
+
+// pushIndex pushes the images as index like build does, tagging the manifests with their architecture.
+func pushIndex(s *Store, ref string, imgs map[string]image.Image) image.Image {
+	GinkgoHelper()
+	ctx := context.Background()
+
+	index := ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+	}
+	for arch, img := range imgs {
+		Expect(s.Put(ctx, img)).To(Succeed())
+		Expect(s.Tag(ctx, img.Descriptor().Digest.String(), ref+"-"+arch)).To(Succeed())
+		desc := img.Descriptor()
+		desc.Platform = &ocispec.Platform{OS: "linux", Architecture: arch}
+		index.Manifests = append(index.Manifests, desc)
+	}
+	indexImg, err := imageutil.NewIndexImage(index)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(s.PushIndexManifest(ctx, indexImg, &index, ref)).To(Succeed())
+	return indexImg
+}
+
+// newArtifact returns an artifact referring to subject.
+func newArtifact(subject ocispec.Descriptor, data string) image.Image {
+	GinkgoHelper()
+	artifact, err := imageutil.NewArtifactBuilder("application/vnd.test.artifact").
+		Subject(subject).
+		BytesLayer([]byte(data), imageutil.WithMediaType("application/vnd.test.data")).
+		Complete()
+	Expect(err).NotTo(HaveOccurred())
+	return artifact
+}
+
+// archSource resolves indexes of the store to their amd64 manifest like a remote registry.
+type archSource struct {
+	*Store
+}
+
+func (s archSource) Resolve(ctx context.Context, ref string) (image.Image, error) {
+	return s.ResolveArch(ctx, ref, "amd64")
+}
+
+func (s archSource) Get(ctx context.Context, ref string) (image.Image, error) {
+	return s.Store.Resolve(ctx, ref)
+}
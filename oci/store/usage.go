@@ -0,0 +1,207 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containerd/containerd/content"
+	"github.com/ironcore-dev/ironcore-image/oci/descriptormatcher"
+	"github.com/ironcore-dev/ironcore-image/utils/sets"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ImageUsage is the disk usage of a single image.
+type ImageUsage struct {
+	// Digest is the digest of the image manifest or index.
+	Digest digest.Digest `json:"digest"`
+	// MediaType is the media type of the image manifest or index.
+	MediaType string `json:"mediaType"`
+	// Names are all names the image is tagged with.
+	Names []string `json:"names"`
+	// Size is the total size of all blobs of the image present in the store.
+	Size int64 `json:"size"`
+	// UniqueSize is the size of the blobs only used by this image.
+	UniqueSize int64 `json:"uniqueSize"`
+	// SharedSize is the size of the blobs that are also used by other images.
+	SharedSize int64 `json:"sharedSize"`
+}
+
+// DiskUsage is the disk usage of a store.
+type DiskUsage struct {
+	// Images are the usages of all top-level images, i.e. images that are not part of another image.
+	Images []ImageUsage `json:"images"`
+	// BlobCount is the number of blobs in the store.
+	BlobCount int `json:"blobCount"`
+	// TotalSize is the total size of all blobs in the store.
+	TotalSize int64 `json:"totalSize"`
+	// ReferrerCount is the number of referrers stored via PushReferrer, such as SBOMs or signatures.
+	ReferrerCount int `json:"referrerCount"`
+	// ReferrerSize is the total size of the blobs only used by referrers.
+	ReferrerSize int64 `json:"referrerSize"`
+	// OrphanedBlobCount is the number of blobs not referenced by any image.
+	OrphanedBlobCount int `json:"orphanedBlobCount"`
+	// OrphanedSize is the total size of all blobs not referenced by any image.
+	OrphanedSize int64 `json:"orphanedSize"`
+	// IngestCount is the number of in-progress ingests.
+	IngestCount int `json:"ingestCount"`
+	// IngestSize is the number of bytes written by in-progress ingests.
+	IngestSize int64 `json:"ingestSize"`
+}
+
+// ImageSizes returns the total size of all blobs present in the store for each image with the given digests.
+func (s *Store) ImageSizes(ctx context.Context, dgsts ...digest.Digest) (map[digest.Digest]int64, error) {
+	sizes, err := s.blobSizes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[digest.Digest]int64, len(dgsts))
+	for _, dgst := range dgsts {
+		if _, ok := res[dgst]; ok {
+			continue
+		}
+
+		reachable, err := s.reachable(ctx, dgst)
+		if err != nil {
+			return nil, err
+		}
+		res[dgst] = sumSizes(sizes, reachable)
+	}
+	return res, nil
+}
+
+func (s *Store) blobSizes(ctx context.Context) (map[digest.Digest]int64, error) {
+	sizes := make(map[digest.Digest]int64)
+	if err := s.layout.ContentStore().Walk(ctx, func(info content.Info) error {
+		sizes[info.Digest] = info.Size
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("error walking content: %w", err)
+	}
+	return sizes, nil
+}
+
+func sumSizes(sizes map[digest.Digest]int64, digests sets.Set[digest.Digest]) int64 {
+	var total int64
+	for dgst := range digests {
+		total += sizes[dgst]
+	}
+	return total
+}
+
+// DiskUsage computes the disk usage of the store by walking all images against the stored blobs.
+func (s *Store) DiskUsage(ctx context.Context) (*DiskUsage, error) {
+	sizes, err := s.blobSizes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	descs, err := s.layout.Indexer().List(ctx, descriptormatcher.Every)
+	if err != nil {
+		return nil, fmt.Errorf("error listing index entries: %w", err)
+	}
+
+	var (
+		order     []digest.Digest
+		images    = make(map[digest.Digest]*ImageUsage)
+		reachable = make(map[digest.Digest]sets.Set[digest.Digest])
+	)
+	var referrers []digest.Digest
+	for _, desc := range descs {
+		if IsReferrer(desc) {
+			referrers = append(referrers, desc.Digest)
+			continue
+		}
+
+		img, ok := images[desc.Digest]
+		if !ok {
+			img = &ImageUsage{Digest: desc.Digest, MediaType: desc.MediaType}
+			images[desc.Digest] = img
+			order = append(order, desc.Digest)
+
+			r, err := s.reachable(ctx, desc.Digest)
+			if err != nil {
+				return nil, err
+			}
+			reachable[desc.Digest] = r
+		}
+		if name, ok := desc.Annotations[ocispec.AnnotationRefName]; ok {
+			img.Names = append(img.Names, name)
+		}
+	}
+
+	// Images contained in another image (e.g. the manifests of an index) are not top-level.
+	nested := sets.New[digest.Digest]()
+	for _, dgst := range order {
+		for other := range reachable[dgst] {
+			if other != dgst {
+				nested.Insert(other)
+			}
+		}
+	}
+
+	refCounts := make(map[digest.Digest]int)
+	referenced := sets.New[digest.Digest]()
+	for _, dgst := range order {
+		for blob := range reachable[dgst] {
+			referenced.Insert(blob)
+			if !nested.Has(dgst) {
+				refCounts[blob]++
+			}
+		}
+	}
+
+	usage := &DiskUsage{}
+	for _, dgst := range order {
+		if nested.Has(dgst) {
+			continue
+		}
+
+		img := images[dgst]
+		for blob := range reachable[dgst] {
+			size := sizes[blob]
+			img.Size += size
+			if refCounts[blob] > 1 {
+				img.SharedSize += size
+			} else {
+				img.UniqueSize += size
+			}
+		}
+		usage.Images = append(usage.Images, *img)
+	}
+
+	usage.ReferrerCount = len(referrers)
+	referrerBlobs, err := s.reachable(ctx, referrers...)
+	if err != nil {
+		return nil, err
+	}
+	for dgst := range referrerBlobs {
+		if !referenced.Has(dgst) {
+			referenced.Insert(dgst)
+			usage.ReferrerSize += sizes[dgst]
+		}
+	}
+
+	for dgst, size := range sizes {
+		usage.BlobCount++
+		usage.TotalSize += size
+		if !referenced.Has(dgst) {
+			usage.OrphanedBlobCount++
+			usage.OrphanedSize += size
+		}
+	}
+
+	statuses, err := s.layout.ContentStore().ListStatuses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing ingests: %w", err)
+	}
+	for _, status := range statuses {
+		usage.IngestCount++
+		usage.IngestSize += status.Offset
+	}
+	return usage, nil
+}
@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package provenance implements SLSA build provenance (https://slsa.dev/provenance/v1) in-toto statements
+// that are stored as referrer artifacts of the images they describe.
+package provenance
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"time"
+
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/ironcore-dev/ironcore-image/oci/imageutil"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// ArtifactType is the artifact type (and layer media type) of provenance artifacts.
+	ArtifactType = "application/vnd.in-toto+json"
+	// StatementType is the in-toto statement type.
+	StatementType = "https://in-toto.io/Statement/v1"
+	// PredicateType is the SLSA provenance predicate type.
+	PredicateType = "https://slsa.dev/provenance/v1"
+	// BuildType is the build type of images built with ironcore-image.
+	BuildType = "https://ironcore.dev/ironcore-image/build/v1"
+	// DefaultBuilderID is the default builder id.
+	DefaultBuilderID = "https://github.com/ironcore-dev/ironcore-image"
+)
+
+// Statement is an in-toto statement with a SLSA provenance predicate.
+type Statement struct {
+	Type          string               `json:"_type"`
+	Subject       []ResourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     Provenance           `json:"predicate"`
+}
+
+// ResourceDescriptor describes an artifact by name, location and digest.
+type ResourceDescriptor struct {
+	Name        string            `json:"name,omitempty"`
+	URI         string            `json:"uri,omitempty"`
+	Digest      map[string]string `json:"digest,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Provenance is the SLSA provenance predicate.
+type Provenance struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+// BuildDefinition describes the inputs of a build.
+type BuildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   map[string]any       `json:"externalParameters"`
+	InternalParameters   map[string]any       `json:"internalParameters,omitempty"`
+	ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies,omitempty"`
+}
+
+// RunDetails describes the build run.
+type RunDetails struct {
+	Builder  Builder       `json:"builder"`
+	Metadata BuildMetadata `json:"metadata"`
+}
+
+// Builder identifies the builder.
+type Builder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+// BuildMetadata holds metadata of the build run.
+type BuildMetadata struct {
+	InvocationID string    `json:"invocationId,omitempty"`
+	StartedOn    time.Time `json:"startedOn"`
+	FinishedOn   time.Time `json:"finishedOn"`
+}
+
+// DigestSet returns the digest set of dgst as used in resource descriptors.
+func DigestSet(dgst digest.Digest) map[string]string {
+	return map[string]string{dgst.Algorithm().String(): dgst.Encoded()}
+}
+
+// Version returns the version of the running ironcore-image module.
+func Version() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+// NewStatement returns a new statement for the given subjects and provenance.
+func NewStatement(subjects []ResourceDescriptor, provenance Provenance) *Statement {
+	return &Statement{
+		Type:          StatementType,
+		Subject:       subjects,
+		PredicateType: PredicateType,
+		Predicate:     provenance,
+	}
+}
+
+// Artifact builds a referrer artifact for subject holding the statement.
+func Artifact(subject ocispec.Descriptor, statement *Statement) (image.Image, error) {
+	data, err := json.Marshal(statement)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling statement: %w", err)
+	}
+
+	return imageutil.NewArtifactBuilder(ArtifactType).
+		Subject(subject).
+		Annotations(map[string]string{
+			ocispec.AnnotationCreated: statement.Predicate.RunDetails.Metadata.FinishedOn.UTC().Format(time.RFC3339),
+		}).
+		BytesLayer(data,
+			imageutil.WithMediaType(ArtifactType),
+			imageutil.WithAnnotations(map[string]string{"in-toto.io/predicate-type": PredicateType}),
+		).
+		Complete()
+}
+
+// Find returns the provenance statements referring to subject, most recent first.
+func Find(ctx context.Context, src image.ReferrersSource, ref string, subject digest.Digest) ([]*Statement, error) {
+	descs, err := src.Referrers(ctx, ref, subject, ArtifactType)
+	if err != nil {
+		return nil, fmt.Errorf("error listing provenance of %s: %w", subject, err)
+	}
+
+	var res []*Statement
+	for _, desc := range descs {
+		artifact, err := src.Referrer(ctx, ref, desc)
+		if err != nil {
+			return nil, fmt.Errorf("error getting provenance %s: %w", desc.Digest, err)
+		}
+
+		layers, err := artifact.Layers(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting layers of provenance %s: %w", desc.Digest, err)
+		}
+		for _, layer := range layers {
+			if layer.Descriptor().MediaType != ArtifactType {
+				continue
+			}
+
+			data, err := imageutil.ReadLayerContent(ctx, layer)
+			if err != nil {
+				return nil, fmt.Errorf("error reading provenance %s: %w", desc.Digest, err)
+			}
+			statement := &Statement{}
+			if err := json.Unmarshal(data, statement); err != nil {
+				return nil, fmt.Errorf("error decoding provenance %s: %w", desc.Digest, err)
+			}
+			if statement.PredicateType != PredicateType {
+				continue
+			}
+			res = append(res, statement)
+		}
+	}
+
+	slices.SortStableFunc(res, func(a, b *Statement) int {
+		return cmp.Compare(b.Predicate.RunDetails.Metadata.FinishedOn.UnixNano(), a.Predicate.RunDetails.Metadata.FinishedOn.UnixNano())
+	})
+	return res, nil
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provenance_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/ironcore-dev/ironcore-image/oci/image"
+	"github.com/ironcore-dev/ironcore-image/oci/imageutil"
+	"github.com/ironcore-dev/ironcore-image/oci/store"
+	. "github.com/ironcore-dev/ironcore-image/provenance"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
+)
+
+var _ = Describe("Provenance", func() {
+	const ref = "example.org/foo:bar"
+
+	var (
+		ctx context.Context
+		s   *store.Store
+		img image.Image
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		var err error
+		s, err = store.NewMemory()
+		Expect(err).NotTo(HaveOccurred())
+
+		img, err = imageutil.NewBytesConfigBuilder([]byte("{}"), imageutil.WithMediaType("application/vnd.test.config")).
+			BytesLayer([]byte("layer"), imageutil.WithMediaType("application/vnd.test.layer")).
+			Complete()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Push(ctx, ref, img)).To(Succeed())
+	})
+
+	newStatement := func(finishedOn time.Time) *Statement {
+		return NewStatement(
+			[]ResourceDescriptor{{Name: ref, Digest: DigestSet(img.Descriptor().Digest)}},
+			Provenance{
+				BuildDefinition: BuildDefinition{
+					BuildType:          BuildType,
+					ExternalParameters: map[string]any{"tag": ref},
+					ResolvedDependencies: []ResourceDescriptor{{
+						Name:   "kernel (amd64)",
+						URI:    "file:///vmlinuz",
+						Digest: DigestSet(digest.FromString("kernel")),
+					}},
+				},
+				RunDetails: RunDetails{
+					Builder: Builder{ID: DefaultBuilderID},
+					Metadata: BuildMetadata{
+						StartedOn:  finishedOn.Add(-time.Minute),
+						FinishedOn: finishedOn,
+					},
+				},
+			},
+		)
+	}
+
+	It("should store and find provenance statements", func() {
+		By("finding no statements of the image")
+		statements, err := Find(ctx, s, ref, img.Descriptor().Digest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statements).To(BeEmpty())
+
+		By("attaching two statements")
+		older := newStatement(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		newer := newStatement(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+		for _, statement := range []*Statement{older, newer} {
+			artifact, err := Artifact(img.Descriptor(), statement)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.PushReferrer(ctx, ref, artifact)).To(Succeed())
+		}
+
+		By("finding the statements, most recent first")
+		statements, err = Find(ctx, s, ref, img.Descriptor().Digest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statements).To(HaveLen(2))
+		Expect(statements[0].Type).To(Equal(StatementType))
+		Expect(statements[0].PredicateType).To(Equal(PredicateType))
+		Expect(statements[0].Predicate.RunDetails.Metadata.FinishedOn).To(Equal(newer.Predicate.RunDetails.Metadata.FinishedOn))
+		Expect(statements[1].Predicate.RunDetails.Metadata.FinishedOn).To(Equal(older.Predicate.RunDetails.Metadata.FinishedOn))
+		Expect(statements[0].Subject).To(Equal(newer.Subject))
+		Expect(statements[0].Predicate.BuildDefinition.ResolvedDependencies).To(Equal(newer.Predicate.BuildDefinition.ResolvedDependencies))
+	})
+})
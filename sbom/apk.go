@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sbom
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// readAPK reads the packages of the apk database. It returns no packages if there is no apk database.
+func readAPK(fsys fs.FS) ([]Package, error) {
+	data, err := fs.ReadFile(fsys, "lib/apk/db/installed")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading apk database: %w", err)
+	}
+
+	var (
+		res     []Package
+		current *Package
+	)
+	flush := func() {
+		if current != nil && current.Name != "" {
+			res = append(res, *current)
+		}
+		current = nil
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if current == nil {
+			current = &Package{Type: PackageTypeAPK}
+		}
+
+		switch key {
+		case "P":
+			current.Name = value
+		case "V":
+			current.Version = value
+		case "A":
+			current.Arch = value
+		case "L":
+			current.License = value
+		case "m":
+			current.Supplier = value
+		case "o":
+			current.Source = value
+		}
+	}
+	flush()
+	return res, nil
+}
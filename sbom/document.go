@@ -0,0 +1,329 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sbom
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Format is an SBOM document format.
+type Format string
+
+const (
+	// FormatSPDX is SPDX 2.3 in JSON encoding.
+	FormatSPDX Format = "spdx"
+	// FormatCycloneDX is CycloneDX 1.5 in JSON encoding.
+	FormatCycloneDX Format = "cyclonedx"
+)
+
+// Formats are all supported formats.
+var Formats = []Format{FormatSPDX, FormatCycloneDX}
+
+// MediaType returns the media type of documents of the format, which is also used as artifact type.
+func (f Format) MediaType() string {
+	switch f {
+	case FormatSPDX:
+		return "application/spdx+json"
+	case FormatCycloneDX:
+		return "application/vnd.cyclonedx+json"
+	default:
+		return ""
+	}
+}
+
+// ParseFormat parses a Format.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown sbom format %q, supported formats are %v", s, Formats)
+}
+
+// DocumentOptions are options for generating SBOM documents.
+type DocumentOptions struct {
+	// Name is the name of the described root file system, e.g. its image reference.
+	Name string
+	// Created is the creation time of the document.
+	Created time.Time
+	// Tool is the name of the generating tool.
+	Tool string
+}
+
+// Encode writes the inventory as a document of the given format to w.
+func (inv *Inventory) Encode(w io.Writer, format Format, opts DocumentOptions) error {
+	if opts.Tool == "" {
+		opts.Tool = "ironcore-image"
+	}
+	if opts.Created.IsZero() {
+		opts.Created = time.Now()
+	}
+	opts.Created = opts.Created.UTC().Truncate(time.Second)
+
+	var doc any
+	switch format {
+	case FormatSPDX:
+		doc = inv.spdx(opts)
+	case FormatCycloneDX:
+		doc = inv.cycloneDX(opts)
+	default:
+		return fmt.Errorf("unknown sbom format %q", format)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(doc)
+}
+
+// fingerprint returns a stable hash of the inventory, used to derive unique document identifiers.
+func (inv *Inventory) fingerprint(name string) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%s %s\n", name, inv.Distro.ID, inv.Distro.VersionID)
+	for _, pkg := range inv.Packages {
+		_, _ = fmt.Fprintf(h, "%s %s %s %s\n", pkg.Type, pkg.Name, pkg.FullVersion(), pkg.Arch)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (inv *Inventory) osName() string {
+	switch {
+	case inv.Distro.ID != "":
+		return inv.Distro.ID
+	case inv.Distro.Name != "":
+		return inv.Distro.Name
+	default:
+		return "rootfs"
+	}
+}
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	Supplier         string            `json:"supplier,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	SourceInfo       string            `json:"sourceInfo,omitempty"`
+	PrimaryPurpose   string            `json:"primaryPackagePurpose,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+const spdxNoAssertion = "NOASSERTION"
+
+var spdxIDInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+func (inv *Inventory) spdx(opts DocumentOptions) *spdxDocument {
+	name := opts.Name
+	if name == "" {
+		name = inv.osName()
+	}
+
+	const rootID = "SPDXRef-OperatingSystem"
+	doc := &spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              name,
+		DocumentNamespace: "https://ironcore.dev/spdx/" + inv.fingerprint(name),
+		CreationInfo: spdxCreationInfo{
+			Created:  opts.Created.Format(time.RFC3339),
+			Creators: []string{"Tool: " + opts.Tool},
+		},
+		Packages: []spdxPackage{{
+			SPDXID:           rootID,
+			Name:             inv.osName(),
+			VersionInfo:      inv.Distro.VersionID,
+			DownloadLocation: spdxNoAssertion,
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  spdxNoAssertion,
+			CopyrightText:    spdxNoAssertion,
+			PrimaryPurpose:   "OPERATING-SYSTEM",
+		}},
+		Relationships: []spdxRelationship{{
+			SPDXElementID:      "SPDXRef-DOCUMENT",
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: rootID,
+		}},
+	}
+
+	for i, pkg := range inv.Packages {
+		id := fmt.Sprintf("SPDXRef-Package-%s-%s-%d", pkg.Type, spdxIDInvalidChars.ReplaceAllString(pkg.Name, "-"), i)
+
+		supplier := spdxNoAssertion
+		if pkg.Supplier != "" {
+			supplier = "Organization: " + strings.ReplaceAll(pkg.Supplier, "\n", " ")
+		}
+		var sourceInfo string
+		if pkg.Source != "" {
+			sourceInfo = "built package from: " + pkg.Source
+		}
+
+		// Licenses of package managers are not necessarily valid SPDX license expressions,
+		// so they are only referenced in the CycloneDX format, which allows free-form names.
+		doc.Packages = append(doc.Packages, spdxPackage{
+			SPDXID:           id,
+			Name:             pkg.Name,
+			VersionInfo:      pkg.FullVersion(),
+			Supplier:         supplier,
+			DownloadLocation: spdxNoAssertion,
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  spdxNoAssertion,
+			CopyrightText:    spdxNoAssertion,
+			SourceInfo:       sourceInfo,
+			PrimaryPurpose:   "LIBRARY",
+			ExternalRefs: []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  pkg.PURL(inv.Distro),
+			}},
+		})
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      rootID,
+			RelationshipType:   "CONTAINS",
+			RelatedSPDXElement: id,
+		})
+	}
+	return doc
+}
+
+type cycloneDXDocument struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     cycloneDXMetadata    `json:"metadata"`
+	Components   []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     cycloneDXTools     `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	BOMRef     string              `json:"bom-ref,omitempty"`
+	Type       string              `json:"type"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	Supplier   *cycloneDXSupplier  `json:"supplier,omitempty"`
+	Licenses   []cycloneDXLicense  `json:"licenses,omitempty"`
+	PURL       string              `json:"purl,omitempty"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXSupplier struct {
+	Name string `json:"name"`
+}
+
+type cycloneDXLicense struct {
+	License cycloneDXLicenseName `json:"license"`
+}
+
+type cycloneDXLicenseName struct {
+	Name string `json:"name"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (inv *Inventory) cycloneDX(opts DocumentOptions) *cycloneDXDocument {
+	fp := inv.fingerprint(opts.Name)
+	// Derive a version 4 style UUID from the fingerprint so identical inventories get identical serial numbers.
+	serial := fmt.Sprintf("urn:uuid:%s-%s-4%s-a%s-%s", fp[0:8], fp[8:12], fp[13:16], fp[17:20], fp[20:32])
+
+	name := opts.Name
+	if name == "" {
+		name = inv.osName()
+	}
+
+	doc := &cycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: serial,
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: opts.Created.Format(time.RFC3339),
+			Tools: cycloneDXTools{Components: []cycloneDXComponent{{
+				Type: "application",
+				Name: opts.Tool,
+			}}},
+			Component: cycloneDXComponent{
+				BOMRef:  "operating-system",
+				Type:    "operating-system",
+				Name:    name,
+				Version: inv.Distro.VersionID,
+			},
+		},
+		Components: []cycloneDXComponent{},
+	}
+
+	for _, pkg := range inv.Packages {
+		purl := pkg.PURL(inv.Distro)
+		component := cycloneDXComponent{
+			BOMRef:  purl,
+			Type:    "library",
+			Name:    pkg.Name,
+			Version: pkg.FullVersion(),
+			PURL:    purl,
+		}
+		if pkg.Supplier != "" {
+			component.Supplier = &cycloneDXSupplier{Name: strings.ReplaceAll(pkg.Supplier, "\n", " ")}
+		}
+		if pkg.License != "" {
+			component.Licenses = []cycloneDXLicense{{License: cycloneDXLicenseName{Name: pkg.License}}}
+		}
+		if pkg.Source != "" {
+			component.Properties = []cycloneDXProperty{{Name: "ironcore:package:source", Value: pkg.Source}}
+		}
+		doc.Components = append(doc.Components, component)
+	}
+	return doc
+}
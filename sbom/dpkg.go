@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sbom
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// readDpkg reads the packages of the dpkg database, including the per-package status files
+// used by distroless images. It returns no packages if there is no dpkg database.
+func readDpkg(fsys fs.FS) ([]Package, error) {
+	var res []Package
+
+	data, err := fs.ReadFile(fsys, "var/lib/dpkg/status")
+	switch {
+	case err == nil:
+		res = append(res, parseDpkgStatus(string(data))...)
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("error reading dpkg status: %w", err)
+	}
+
+	entries, err := fs.ReadDir(fsys, "var/lib/dpkg/status.d")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("error reading dpkg status directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), ".md5sums") {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join("var/lib/dpkg/status.d", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading dpkg status file %s: %w", entry.Name(), err)
+		}
+		res = append(res, parseDpkgStatus(string(data))...)
+	}
+	return res, nil
+}
+
+func parseDpkgStatus(data string) []Package {
+	var res []Package
+	for _, paragraph := range parseControl(data) {
+		// Packages in status files without a Status field (status.d) are installed.
+		if status, ok := paragraph["Status"]; ok && !strings.HasSuffix(status, " installed") {
+			continue
+		}
+		if paragraph["Package"] == "" {
+			continue
+		}
+
+		source, _, _ := strings.Cut(paragraph["Source"], " ")
+		res = append(res, Package{
+			Type:     PackageTypeDeb,
+			Name:     paragraph["Package"],
+			Version:  paragraph["Version"],
+			Arch:     paragraph["Architecture"],
+			Supplier: paragraph["Maintainer"],
+			Source:   source,
+		})
+	}
+	return res
+}
+
+// parseControl parses a file in the Debian control format into its paragraphs.
+// Continuation lines are joined with newlines.
+func parseControl(data string) []map[string]string {
+	var (
+		res     []map[string]string
+		current map[string]string
+		lastKey string
+	)
+	for _, line := range strings.Split(data, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if current != nil {
+				res = append(res, current)
+				current = nil
+			}
+			continue
+		}
+		if current == nil {
+			current = make(map[string]string)
+		}
+
+		if line[0] == ' ' || line[0] == '\t' {
+			if lastKey != "" {
+				current[lastKey] += "\n" + strings.TrimSpace(line)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		lastKey = key
+		current[key] = strings.TrimSpace(value)
+	}
+	if current != nil {
+		res = append(res, current)
+	}
+	return res
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sbom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+)
+
+// rpmDatabaseDirs are the directories rpm keeps its database in, newest location first.
+var rpmDatabaseDirs = []string{
+	"usr/lib/sysimage/rpm",
+	"var/lib/rpm",
+}
+
+// ErrUnsupportedDatabase is returned for package databases in formats that cannot be read.
+var ErrUnsupportedDatabase = errors.New("unsupported package database")
+
+const (
+	rpmTagName      = 1000
+	rpmTagVersion   = 1001
+	rpmTagRelease   = 1002
+	rpmTagEpoch     = 1003
+	rpmTagVendor    = 1011
+	rpmTagLicense   = 1014
+	rpmTagArch      = 1022
+	rpmTagSourceRPM = 1044
+
+	rpmTypeInt32       = 4
+	rpmTypeString      = 6
+	rpmTypeStringArray = 8
+	rpmTypeI18NString  = 9
+)
+
+// readRPM reads the packages of the rpm database. It returns no packages if there is no rpm database.
+func readRPM(fsys fs.FS) ([]Package, error) {
+	for _, dir := range rpmDatabaseDirs {
+		data, err := fs.ReadFile(fsys, dir+"/rpmdb.sqlite")
+		if err == nil {
+			return readRPMSQLite(data)
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("error reading rpm database: %w", err)
+		}
+
+		for _, legacy := range []string{"Packages", "Packages.db"} {
+			if _, err := fs.Stat(fsys, dir+"/"+legacy); err == nil {
+				return nil, fmt.Errorf("%w: rpm database %s/%s (only sqlite databases are supported)", ErrUnsupportedDatabase, dir, legacy)
+			}
+		}
+	}
+	return nil, nil
+}
+
+func readRPMSQLite(data []byte) ([]Package, error) {
+	db, err := openSQLite(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error opening rpm database: %w", err)
+	}
+
+	root, err := db.tableRootPage("Packages")
+	if err != nil {
+		return nil, fmt.Errorf("error reading rpm database: %w", err)
+	}
+
+	var res []Package
+	err = db.scan(root, func(record []any) error {
+		if len(record) < 2 {
+			return nil
+		}
+		blob, ok := record[1].([]byte)
+		if !ok {
+			return nil
+		}
+
+		pkg, err := parseRPMHeader(blob)
+		if err != nil {
+			return fmt.Errorf("error parsing rpm header: %w", err)
+		}
+		// Imported signing keys are stored as pseudo packages.
+		if pkg.Name == "gpg-pubkey" {
+			return nil
+		}
+		res = append(res, *pkg)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// parseRPMHeader parses an rpm header blob as stored in the rpm database (without the header magic).
+func parseRPMHeader(blob []byte) (*Package, error) {
+	if len(blob) < 8 {
+		return nil, errors.New("header too short")
+	}
+	indexCount := int(binary.BigEndian.Uint32(blob[0:]))
+	dataLen := int(binary.BigEndian.Uint32(blob[4:]))
+	dataStart := 8 + indexCount*16
+	if indexCount < 0 || dataLen < 0 || dataStart+dataLen > len(blob) || dataStart < 8 {
+		return nil, errors.New("invalid header size")
+	}
+	store := blob[dataStart : dataStart+dataLen]
+
+	pkg := &Package{Type: PackageTypeRPM}
+	for i := 0; i < indexCount; i++ {
+		entry := blob[8+i*16:]
+		tag := binary.BigEndian.Uint32(entry[0:])
+		typ := binary.BigEndian.Uint32(entry[4:])
+		offset := int(int32(binary.BigEndian.Uint32(entry[8:])))
+		if offset < 0 || offset >= len(store) {
+			continue
+		}
+
+		var value string
+		switch typ {
+		case rpmTypeString, rpmTypeStringArray, rpmTypeI18NString:
+			// Use the first string of arrays.
+			end := bytes.IndexByte(store[offset:], 0)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string of tag %d", tag)
+			}
+			value = string(store[offset : offset+end])
+		case rpmTypeInt32:
+			if offset+4 > len(store) {
+				return nil, fmt.Errorf("truncated integer of tag %d", tag)
+			}
+			value = strconv.FormatUint(uint64(binary.BigEndian.Uint32(store[offset:])), 10)
+		default:
+			continue
+		}
+
+		switch tag {
+		case rpmTagName:
+			pkg.Name = value
+		case rpmTagVersion:
+			pkg.Version = value
+		case rpmTagRelease:
+			pkg.Release = value
+		case rpmTagEpoch:
+			pkg.Epoch = value
+		case rpmTagVendor:
+			pkg.Supplier = value
+		case rpmTagLicense:
+			pkg.License = value
+		case rpmTagArch:
+			pkg.Arch = value
+		case rpmTagSourceRPM:
+			pkg.Source = value
+		}
+	}
+	if pkg.Name == "" {
+		return nil, errors.New("package without name")
+	}
+	return pkg, nil
+}
@@ -0,0 +1,233 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sbom generates software bills of materials for root file systems by reading
+// their package databases (dpkg, apk and rpm).
+package sbom
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/ironcore-dev/ironcore-image/fs/ext4"
+	"github.com/ironcore-dev/ironcore-image/fs/squashfs"
+)
+
+// ErrUnknownFileSystem is returned by OpenFS for images that are neither ext4 nor squashfs.
+var ErrUnknownFileSystem = errors.New("unknown file system")
+
+// OpenFS opens the ext2/3/4 or squashfs file system image r.
+func OpenFS(r io.ReaderAt) (fs.FS, error) {
+	switch {
+	case ext4.IsExt4(r):
+		return ext4.New(r)
+	case squashfs.IsSquashFS(r):
+		return squashfs.New(r)
+	default:
+		return nil, ErrUnknownFileSystem
+	}
+}
+
+// PackageType is the type of package manager a package was installed with.
+type PackageType string
+
+const (
+	PackageTypeDeb PackageType = "deb"
+	PackageTypeRPM PackageType = "rpm"
+	PackageTypeAPK PackageType = "apk"
+)
+
+// Package is an installed package.
+type Package struct {
+	// Type is the type of the package.
+	Type PackageType
+	// Name is the name of the package.
+	Name string
+	// Version is the version of the package. For rpm packages, it excludes Epoch and Release.
+	Version string
+	// Release is the release of rpm packages.
+	Release string
+	// Epoch is the epoch of rpm packages.
+	Epoch string
+	// Arch is the architecture of the package.
+	Arch string
+	// License is the license of the package as declared by the package manager, if any.
+	License string
+	// Supplier is the maintainer or vendor of the package, if any.
+	Supplier string
+	// Source is the name of the source package, if any.
+	Source string
+}
+
+// FullVersion returns the complete version of the package, including rpm epoch and release.
+func (p *Package) FullVersion() string {
+	if p.Type != PackageTypeRPM {
+		return p.Version
+	}
+	v := p.Version
+	if p.Release != "" {
+		v += "-" + p.Release
+	}
+	if p.Epoch != "" && p.Epoch != "0" {
+		v = p.Epoch + ":" + v
+	}
+	return v
+}
+
+// PURL returns the package URL (https://github.com/package-url/purl-spec) of the package.
+func (p *Package) PURL(distro Distro) string {
+	namespace := distro.ID
+	if namespace == "" {
+		namespace = string(p.Type)
+	}
+
+	version := p.Version
+	if p.Type == PackageTypeRPM && p.Release != "" {
+		version += "-" + p.Release
+	}
+
+	qualifiers := url.Values{}
+	if p.Arch != "" {
+		qualifiers.Set("arch", p.Arch)
+	}
+	if p.Type == PackageTypeRPM && p.Epoch != "" && p.Epoch != "0" {
+		qualifiers.Set("epoch", p.Epoch)
+	}
+	if distro.ID != "" {
+		qualifiers.Set("distro", strings.TrimSuffix(distro.ID+"-"+distro.VersionID, "-"))
+	}
+
+	purl := fmt.Sprintf("pkg:%s/%s/%s", p.Type, purlEscape(strings.ToLower(namespace)), purlEscape(p.Name))
+	if version != "" {
+		purl += "@" + purlEscape(version)
+	}
+	if len(qualifiers) > 0 {
+		// url.Values.Encode sorts by key as required by the purl specification.
+		purl += "?" + qualifiers.Encode()
+	}
+	return purl
+}
+
+// purlEscape percent-encodes all characters of s except unreserved ones.
+func purlEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("-._~", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		_, _ = fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// Distro identifies the operating system of a root file system as described by os-release(5).
+type Distro struct {
+	ID         string
+	VersionID  string
+	Name       string
+	PrettyName string
+}
+
+// Inventory is the set of packages installed in a root file system.
+type Inventory struct {
+	Distro   Distro
+	Packages []Package
+}
+
+// Merge adds the packages of other not yet contained in inv. The distro of inv is kept if set.
+func (inv *Inventory) Merge(other *Inventory) {
+	if inv.Distro == (Distro{}) {
+		inv.Distro = other.Distro
+	}
+
+	seen := make(map[Package]struct{}, len(inv.Packages))
+	for _, pkg := range inv.Packages {
+		seen[pkg] = struct{}{}
+	}
+	for _, pkg := range other.Packages {
+		if _, ok := seen[pkg]; ok {
+			continue
+		}
+		seen[pkg] = struct{}{}
+		inv.Packages = append(inv.Packages, pkg)
+	}
+	inv.sort()
+}
+
+func (inv *Inventory) sort() {
+	slices.SortFunc(inv.Packages, func(a, b Package) int {
+		return cmp.Or(
+			cmp.Compare(a.Type, b.Type),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.FullVersion(), b.FullVersion()),
+			cmp.Compare(a.Arch, b.Arch),
+		)
+	})
+}
+
+// Scan reads os-release and all package databases found in fsys.
+func Scan(fsys fs.FS) (*Inventory, error) {
+	distro, err := readOSRelease(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	inv := &Inventory{Distro: *distro}
+	for _, read := range []func(fs.FS) ([]Package, error){readDpkg, readAPK, readRPM} {
+		pkgs, err := read(fsys)
+		if err != nil {
+			return nil, err
+		}
+		inv.Packages = append(inv.Packages, pkgs...)
+	}
+
+	inv.sort()
+	return inv, nil
+}
+
+func readOSRelease(fsys fs.FS) (*Distro, error) {
+	var (
+		data []byte
+		err  error
+	)
+	for _, name := range []string{"etc/os-release", "usr/lib/os-release"} {
+		data, err = fs.ReadFile(fsys, name)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("error reading %s: %w", name, err)
+		}
+	}
+	if err != nil {
+		return &Distro{}, nil
+	}
+
+	distro := &Distro{}
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		value = strings.Trim(value, `"'`)
+		switch key {
+		case "ID":
+			distro.ID = value
+		case "VERSION_ID":
+			distro.VersionID = value
+		case "NAME":
+			distro.Name = value
+		case "PRETTY_NAME":
+			distro.PrettyName = value
+		}
+	}
+	return distro, nil
+}
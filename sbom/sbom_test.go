@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sbom_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing/fstest"
+	"time"
+
+	. "github.com/ironcore-dev/ironcore-image/sbom"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const dpkgStatus = `Package: base-files
+Status: install ok installed
+Maintainer: Garden Linux Maintainers <contact@gardenlinux.io>
+Architecture: amd64
+Version: 1592.1
+Description: Garden Linux base system miscellaneous files
+ This package contains the basic filesystem hierarchy.
+
+Package: removed
+Status: deinstall ok config-files
+Architecture: amd64
+Version: 1.0
+
+Package: libc6
+Status: install ok installed
+Architecture: amd64
+Source: glibc (2.36-9)
+Version: 2.36-9+deb12u1
+`
+
+// rpmHeader builds an rpm header blob with the given string tags.
+func rpmHeader(tags map[uint32]string) []byte {
+	var (
+		index bytes.Buffer
+		store bytes.Buffer
+	)
+	for _, tag := range []uint32{1000, 1001, 1002, 1014, 1022} {
+		value, ok := tags[tag]
+		if !ok {
+			continue
+		}
+		_ = binary.Write(&index, binary.BigEndian, []uint32{tag, 6, uint32(store.Len()), 1})
+		store.WriteString(value)
+		store.WriteByte(0)
+	}
+
+	var res bytes.Buffer
+	_ = binary.Write(&res, binary.BigEndian, []uint32{uint32(index.Len() / 16), uint32(store.Len())})
+	res.Write(index.Bytes())
+	res.Write(store.Bytes())
+	return res.Bytes()
+}
+
+var _ = Describe("SBOM", func() {
+	It("should scan dpkg and apk databases", func() {
+		fsys := fstest.MapFS{
+			"etc/os-release":                       {Data: []byte("ID=gardenlinux\nVERSION_ID=1592.1\nNAME=\"Garden Linux\"\n")},
+			"var/lib/dpkg/status":                  {Data: []byte(dpkgStatus)},
+			"var/lib/dpkg/status.d/tzdata":         {Data: []byte("Package: tzdata\nVersion: 2024a\nArchitecture: all\n")},
+			"var/lib/dpkg/status.d/tzdata.md5sums": {Data: []byte("ignored")},
+			"lib/apk/db/installed":                 {Data: []byte("P:musl\nV:1.2.4-r2\nA:x86_64\nL:MIT\no:musl\n\n")},
+		}
+
+		inv, err := Scan(fsys)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inv.Distro).To(Equal(Distro{ID: "gardenlinux", VersionID: "1592.1", Name: "Garden Linux"}))
+		Expect(inv.Packages).To(Equal([]Package{
+			{Type: PackageTypeAPK, Name: "musl", Version: "1.2.4-r2", Arch: "x86_64", License: "MIT", Source: "musl"},
+			{Type: PackageTypeDeb, Name: "base-files", Version: "1592.1", Arch: "amd64", Supplier: "Garden Linux Maintainers <contact@gardenlinux.io>"},
+			{Type: PackageTypeDeb, Name: "libc6", Version: "2.36-9+deb12u1", Arch: "amd64", Source: "glibc"},
+			{Type: PackageTypeDeb, Name: "tzdata", Version: "2024a", Arch: "all"},
+		}))
+		Expect(inv.Packages[2].PURL(inv.Distro)).To(Equal("pkg:deb/gardenlinux/libc6@2.36-9%2Bdeb12u1?arch=amd64&distro=gardenlinux-1592.1"))
+	})
+
+	It("should scan sqlite rpm databases", func() {
+		if _, err := exec.LookPath("sqlite3"); err != nil {
+			Skip("sqlite3 not found")
+		}
+
+		dir := GinkgoT().TempDir()
+		db := filepath.Join(dir, "rpmdb.sqlite")
+		var sql bytes.Buffer
+		sql.WriteString("CREATE TABLE Packages (hnum INTEGER PRIMARY KEY AUTOINCREMENT, blob BLOB NOT NULL);\n")
+		// Enough packages with large headers to require interior pages and overflow pages.
+		for i := 0; i < 200; i++ {
+			blob := rpmHeader(map[uint32]string{
+				1000: fmt.Sprintf("pkg%03d", i),
+				1001: "1.0",
+				1002: "1.fc40",
+				1014: "MIT " + string(bytes.Repeat([]byte("x"), i*30)),
+				1022: "x86_64",
+			})
+			_, _ = fmt.Fprintf(&sql, "INSERT INTO Packages (blob) VALUES (X'%s');\n", hex.EncodeToString(blob))
+		}
+		_, _ = fmt.Fprintf(&sql, "INSERT INTO Packages (blob) VALUES (X'%s');\n", hex.EncodeToString(rpmHeader(map[uint32]string{1000: "gpg-pubkey"})))
+		cmd := exec.Command("sqlite3", db)
+		cmd.Stdin = &sql
+		out, err := cmd.CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(out))
+
+		data, err := os.ReadFile(db)
+		Expect(err).NotTo(HaveOccurred())
+		inv, err := Scan(fstest.MapFS{
+			"usr/lib/os-release":                {Data: []byte("ID=fedora\nVERSION_ID=40\n")},
+			"usr/lib/sysimage/rpm/rpmdb.sqlite": {Data: data},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inv.Packages).To(HaveLen(200))
+		Expect(inv.Packages[199]).To(Equal(Package{
+			Type:    PackageTypeRPM,
+			Name:    "pkg199",
+			Version: "1.0",
+			Release: "1.fc40",
+			Arch:    "x86_64",
+			License: "MIT " + string(bytes.Repeat([]byte("x"), 199*30)),
+		}))
+		Expect(inv.Packages[0].PURL(inv.Distro)).To(Equal("pkg:rpm/fedora/pkg000@1.0-1.fc40?arch=x86_64&distro=fedora-40"))
+	})
+
+	It("should reject legacy rpm databases", func() {
+		_, err := Scan(fstest.MapFS{"var/lib/rpm/Packages": {Data: []byte("bdb")}})
+		Expect(err).To(MatchError(ErrUnsupportedDatabase))
+	})
+
+	DescribeTable("should encode documents",
+		func(format Format, expected string) {
+			inv := &Inventory{
+				Distro:   Distro{ID: "gardenlinux", VersionID: "1592.1"},
+				Packages: []Package{{Type: PackageTypeDeb, Name: "libc6", Version: "2.36-9", Arch: "amd64"}},
+			}
+
+			var buf bytes.Buffer
+			Expect(inv.Encode(&buf, format, DocumentOptions{Name: "example.org/os:v1", Created: time.Unix(0, 0)})).To(Succeed())
+
+			var doc map[string]any
+			Expect(json.Unmarshal(buf.Bytes(), &doc)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring(expected))
+			Expect(buf.String()).To(ContainSubstring(`"pkg:deb/gardenlinux/libc6@2.36-9?arch=amd64&distro=gardenlinux-1592.1"`))
+		},
+		Entry("SPDX", FormatSPDX, `"spdxVersion": "SPDX-2.3"`),
+		Entry("CycloneDX", FormatCycloneDX, `"bomFormat": "CycloneDX"`),
+	)
+})
@@ -0,0 +1,297 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sbom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// sqliteHeader is the magic header string of SQLite database files.
+var sqliteHeader = []byte("SQLite format 3\x00")
+
+// sqliteDB is a minimal read-only SQLite database reader supporting full scans of rowid tables.
+type sqliteDB struct {
+	r          io.ReaderAt
+	pageSize   int64
+	usableSize int64
+}
+
+func openSQLite(r io.ReaderAt) (*sqliteDB, error) {
+	header := make([]byte, 100)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("error reading sqlite header: %w", err)
+	}
+	if !bytes.Equal(header[:16], sqliteHeader) {
+		return nil, errors.New("not a sqlite database")
+	}
+
+	pageSize := int64(binary.BigEndian.Uint16(header[16:]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 || pageSize&(pageSize-1) != 0 {
+		return nil, fmt.Errorf("invalid sqlite page size %d", pageSize)
+	}
+	return &sqliteDB{
+		r:          r,
+		pageSize:   pageSize,
+		usableSize: pageSize - int64(header[20]),
+	}, nil
+}
+
+func (db *sqliteDB) page(num uint32) ([]byte, error) {
+	if num == 0 {
+		return nil, errors.New("invalid page 0")
+	}
+	data := make([]byte, db.pageSize)
+	if _, err := db.r.ReadAt(data, int64(num-1)*db.pageSize); err != nil {
+		return nil, fmt.Errorf("error reading page %d: %w", num, err)
+	}
+	return data, nil
+}
+
+// tableRootPage returns the root page of the table with the given name.
+func (db *sqliteDB) tableRootPage(name string) (uint32, error) {
+	var (
+		root  uint32
+		found bool
+	)
+	// The schema table (type, name, tbl_name, rootpage, sql) is rooted at page 1.
+	err := db.scan(1, func(record []any) error {
+		if len(record) < 4 || found {
+			return nil
+		}
+		if typ, _ := record[0].(string); typ != "table" {
+			return nil
+		}
+		if n, _ := record[1].(string); n != name {
+			return nil
+		}
+		page, ok := record[3].(int64)
+		if !ok {
+			return fmt.Errorf("invalid root page of table %s", name)
+		}
+		root, found = uint32(page), true
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("table %s not found", name)
+	}
+	return root, nil
+}
+
+// scan calls fn for each record of the table b-tree rooted at the given page.
+func (db *sqliteDB) scan(root uint32, fn func(record []any) error) error {
+	return db.scanPage(root, 0, fn)
+}
+
+func (db *sqliteDB) scanPage(num uint32, depth int, fn func(record []any) error) error {
+	if depth > 32 {
+		return errors.New("b-tree too deep")
+	}
+	data, err := db.page(num)
+	if err != nil {
+		return err
+	}
+
+	// The first page starts with the database header.
+	offset := 0
+	if num == 1 {
+		offset = 100
+	}
+	page := data[offset:]
+
+	pageType := page[0]
+	cellCount := int(binary.BigEndian.Uint16(page[3:]))
+	headerSize := 8
+	if pageType == 0x05 {
+		headerSize = 12
+	}
+	if headerSize+cellCount*2 > len(page) {
+		return fmt.Errorf("page %d: invalid cell count %d", num, cellCount)
+	}
+
+	for i := 0; i < cellCount; i++ {
+		cellOffset := int(binary.BigEndian.Uint16(page[headerSize+i*2:]))
+		if cellOffset >= len(data) {
+			return fmt.Errorf("page %d: invalid cell offset %d", num, cellOffset)
+		}
+		cell := data[cellOffset:]
+
+		switch pageType {
+		case 0x05: // Table interior page.
+			if len(cell) < 4 {
+				return fmt.Errorf("page %d: truncated cell", num)
+			}
+			if err := db.scanPage(binary.BigEndian.Uint32(cell), depth+1, fn); err != nil {
+				return err
+			}
+		case 0x0D: // Table leaf page.
+			payload, err := db.leafPayload(cell)
+			if err != nil {
+				return fmt.Errorf("page %d: %w", num, err)
+			}
+			record, err := parseRecord(payload)
+			if err != nil {
+				return fmt.Errorf("page %d: %w", num, err)
+			}
+			if err := fn(record); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("page %d: unexpected page type %#x", num, pageType)
+		}
+	}
+
+	if pageType == 0x05 {
+		return db.scanPage(binary.BigEndian.Uint32(page[8:]), depth+1, fn)
+	}
+	return nil
+}
+
+// leafPayload returns the full payload of a table leaf cell, following overflow pages.
+func (db *sqliteDB) leafPayload(cell []byte) ([]byte, error) {
+	size, n := readVarint(cell)
+	if n == 0 {
+		return nil, errors.New("invalid payload size")
+	}
+	cell = cell[n:]
+	if _, n = readVarint(cell); n == 0 {
+		return nil, errors.New("invalid rowid")
+	}
+	cell = cell[n:]
+
+	p := int64(size)
+	u := db.usableSize
+	x := u - 35
+	if p <= x {
+		if int64(len(cell)) < p {
+			return nil, errors.New("truncated payload")
+		}
+		return cell[:p], nil
+	}
+
+	m := ((u - 12) * 32 / 255) - 23
+	local := m + (p-m)%(u-4)
+	if local > x {
+		local = m
+	}
+	if int64(len(cell)) < local+4 {
+		return nil, errors.New("truncated payload")
+	}
+
+	payload := make([]byte, 0, p)
+	payload = append(payload, cell[:local]...)
+	next := binary.BigEndian.Uint32(cell[local:])
+	for int64(len(payload)) < p {
+		if next == 0 {
+			return nil, errors.New("truncated overflow chain")
+		}
+		data, err := db.page(next)
+		if err != nil {
+			return nil, err
+		}
+		next = binary.BigEndian.Uint32(data)
+		chunk := data[4:u]
+		if remaining := p - int64(len(payload)); int64(len(chunk)) > remaining {
+			chunk = chunk[:remaining]
+		}
+		payload = append(payload, chunk...)
+	}
+	return payload, nil
+}
+
+// parseRecord decodes a record into its values (nil, int64, float64 bits as int64, string or []byte).
+func parseRecord(payload []byte) ([]any, error) {
+	headerSize, n := readVarint(payload)
+	if n == 0 || headerSize > uint64(len(payload)) {
+		return nil, errors.New("invalid record header")
+	}
+
+	var (
+		header = payload[n:headerSize]
+		body   = payload[headerSize:]
+		res    []any
+	)
+	for len(header) > 0 {
+		serialType, n := readVarint(header)
+		if n == 0 {
+			return nil, errors.New("invalid record serial type")
+		}
+		header = header[n:]
+
+		var size int
+		switch {
+		case serialType == 0, serialType == 8, serialType == 9:
+			size = 0
+		case serialType <= 4:
+			size = int(serialType)
+		case serialType == 5:
+			size = 6
+		case serialType == 6, serialType == 7:
+			size = 8
+		case serialType >= 12:
+			size = int((serialType - 12) / 2)
+		default:
+			return nil, fmt.Errorf("invalid serial type %d", serialType)
+		}
+		if size > len(body) {
+			return nil, errors.New("truncated record")
+		}
+		value := body[:size]
+		body = body[size:]
+
+		switch {
+		case serialType == 0:
+			res = append(res, nil)
+		case serialType == 8:
+			res = append(res, int64(0))
+		case serialType == 9:
+			res = append(res, int64(1))
+		case serialType <= 6:
+			// Big-endian two's complement integer.
+			var v int64
+			if len(value) > 0 && value[0]&0x80 != 0 {
+				v = -1
+			}
+			for _, b := range value {
+				v = v<<8 | int64(b)
+			}
+			res = append(res, v)
+		case serialType == 7:
+			res = append(res, int64(binary.BigEndian.Uint64(value)))
+		case serialType%2 == 0:
+			res = append(res, value)
+		default:
+			res = append(res, string(value))
+		}
+	}
+	return res, nil
+}
+
+// readVarint reads a SQLite variable-length integer and returns it along with the number of bytes read.
+// It returns n == 0 if data is too short.
+func readVarint(data []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 9; i++ {
+		if i >= len(data) {
+			return 0, 0
+		}
+		if i == 8 {
+			return v<<8 | uint64(data[i]), 9
+		}
+		v = v<<7 | uint64(data[i]&0x7F)
+		if data[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return v, 9
+}
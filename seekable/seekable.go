@@ -0,0 +1,434 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package seekable implements the zstd seekable format
+// (https://github.com/facebook/zstd/blob/dev/contrib/seekable_format/zstd_seekable_compression_format.md).
+//
+// Content is compressed in independent zstd frames of a fixed uncompressed size, followed by a
+// skippable frame holding a seek table. The result is a regular zstd stream that any zstd decoder
+// can decompress, while Reader can decompress arbitrary ranges by fetching only the frames covering them.
+//
+// Between the frames and the seek table, Writer adds another skippable frame holding the SHA-256
+// digests of the decompressed frames. The digest of both trailing frames, the table digest, covers
+// the whole content: a Reader created with NewVerifiedReader verifies every frame it decompresses.
+package seekable
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/opencontainers/go-digest"
+)
+
+const (
+	// DefaultChunkSize is the default uncompressed size of the frames.
+	DefaultChunkSize = 1 << 20
+	// MaxChunkSize is the maximum uncompressed size of the frames.
+	MaxChunkSize = 1 << 30
+
+	skippableMagic = 0x184D2A5E
+	seekableMagic  = 0x8F92EAB1
+	// digestsMagic is the magic number of the skippable frame holding the frame digests.
+	digestsMagic = 0x184D2A5D
+
+	// footerSize is the size of the seek table footer (number of frames, descriptor, magic).
+	footerSize = 9
+	// skippableHeaderSize is the size of the skippable frame header (magic, frame size).
+	skippableHeaderSize = 8
+
+	checksumFlag = 1 << 7
+	// cachedFrames is the number of decompressed frames a Reader keeps.
+	cachedFrames = 4
+)
+
+var (
+	// ErrNotSeekable is returned for content without a seek table.
+	ErrNotSeekable = errors.New("content is not in zstd seekable format")
+	// ErrDigestMismatch is returned if the seek table or a frame does not match its digest.
+	ErrDigestMismatch = errors.New("digest mismatch")
+)
+
+// Writer compresses content written to it in the zstd seekable format.
+// Close has to be called to write the remaining frame and the seek table.
+type Writer struct {
+	w         io.Writer
+	enc       *zstd.Encoder
+	chunkSize int
+	buf       []byte
+	out       []byte
+	frames    []frame
+	table     digest.Digest
+	err       error
+}
+
+type frame struct {
+	compressedSize   uint32
+	decompressedSize uint32
+	digest           [sha256.Size]byte
+}
+
+// NewWriter returns a Writer writing frames of chunkSize uncompressed bytes to w. The output is deterministic.
+func NewWriter(w io.Writer, chunkSize int) (*Writer, error) {
+	if chunkSize <= 0 || chunkSize > MaxChunkSize {
+		return nil, fmt.Errorf("invalid chunk size %d", chunkSize)
+	}
+
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, fmt.Errorf("error creating zstd encoder: %w", err)
+	}
+	return &Writer{
+		w:         w,
+		enc:       enc,
+		chunkSize: chunkSize,
+		buf:       make([]byte, 0, chunkSize),
+	}, nil
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	var n int
+	for len(p) > 0 {
+		chunk := min(len(p), w.chunkSize-len(w.buf))
+		w.buf = append(w.buf, p[:chunk]...)
+		p = p[chunk:]
+		n += chunk
+
+		if len(w.buf) == w.chunkSize {
+			if err := w.flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (w *Writer) flush() error {
+	w.out = w.enc.EncodeAll(w.buf, w.out[:0])
+	if _, err := w.w.Write(w.out); err != nil {
+		w.err = err
+		return err
+	}
+	w.frames = append(w.frames, frame{
+		compressedSize:   uint32(len(w.out)),
+		decompressedSize: uint32(len(w.buf)),
+		digest:           sha256.Sum256(w.buf),
+	})
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// Close writes the remaining frame, the frame digests and the seek table. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if len(w.buf) > 0 {
+		if err := w.flush(); err != nil {
+			return err
+		}
+	}
+	w.err = errors.New("writer is closed")
+
+	digestsSize := len(w.frames) * sha256.Size
+	tableSize := len(w.frames)*8 + footerSize
+	table := make([]byte, 0, 2*skippableHeaderSize+digestsSize+tableSize)
+	table = binary.LittleEndian.AppendUint32(table, digestsMagic)
+	table = binary.LittleEndian.AppendUint32(table, uint32(digestsSize))
+	for _, f := range w.frames {
+		table = append(table, f.digest[:]...)
+	}
+
+	table = binary.LittleEndian.AppendUint32(table, skippableMagic)
+	table = binary.LittleEndian.AppendUint32(table, uint32(tableSize))
+	for _, f := range w.frames {
+		table = binary.LittleEndian.AppendUint32(table, f.compressedSize)
+		table = binary.LittleEndian.AppendUint32(table, f.decompressedSize)
+	}
+	table = binary.LittleEndian.AppendUint32(table, uint32(len(w.frames)))
+	table = append(table, 0)
+	table = binary.LittleEndian.AppendUint32(table, seekableMagic)
+
+	if _, err := w.w.Write(table); err != nil {
+		return err
+	}
+	w.table = digest.FromBytes(table)
+	return nil
+}
+
+// TableDigest returns the digest of the frame digests and the seek table, to be passed to NewVerifiedReader.
+// It is only available after Close.
+func (w *Writer) TableDigest() digest.Digest {
+	return w.table
+}
+
+// Compress compresses the content of r in the zstd seekable format with frames of chunkSize uncompressed bytes.
+// It returns the table digest of the compressed content.
+func Compress(w io.Writer, r io.Reader, chunkSize int) (digest.Digest, error) {
+	sw, err := NewWriter(w, chunkSize)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(sw, r); err != nil {
+		return "", err
+	}
+	if err := sw.Close(); err != nil {
+		return "", err
+	}
+	return sw.TableDigest(), nil
+}
+
+// entry is a frame of the seek table with its offsets.
+type entry struct {
+	compressedOffset   int64
+	compressedSize     int64
+	decompressedOffset int64
+	decompressedSize   int64
+	// digest is the digest of the decompressed frame, nil if the content has no frame digests.
+	digest []byte
+}
+
+// Reader provides random access to the decompressed content of zstd seekable content.
+// Frames are verified against their digests, if the content has frame digests.
+// It is safe for concurrent use.
+type Reader struct {
+	r       io.ReaderAt
+	entries []entry
+	size    int64
+	dec     *zstd.Decoder
+
+	mu  sync.Mutex
+	lru []cachedFrame
+}
+
+type cachedFrame struct {
+	index int
+	data  []byte
+}
+
+// NewReader reads the seek table of the size bytes of compressed content in r.
+// It returns ErrNotSeekable if the content has no seek table.
+//
+// The seek table and frame digests are read from r as is. Use NewVerifiedReader if the content
+// has to be verified, e.g. as r is not trusted.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	return newReader(r, size, "")
+}
+
+// NewVerifiedReader reads the seek table of the size bytes of compressed content in r and verifies
+// the seek table and frame digests against tableDigest, as returned by Writer.TableDigest.
+// Every frame is verified against its digest when decompressed, so the content of the Reader
+// is covered by tableDigest. It returns an error wrapping ErrDigestMismatch if verification fails.
+func NewVerifiedReader(r io.ReaderAt, size int64, tableDigest digest.Digest) (*Reader, error) {
+	if err := tableDigest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid table digest: %w", err)
+	}
+	return newReader(r, size, tableDigest)
+}
+
+func newReader(r io.ReaderAt, size int64, tableDigest digest.Digest) (*Reader, error) {
+	if size < skippableHeaderSize+footerSize {
+		return nil, ErrNotSeekable
+	}
+
+	footer := make([]byte, footerSize)
+	if _, err := r.ReadAt(footer, size-footerSize); err != nil {
+		return nil, fmt.Errorf("error reading seek table footer: %w", err)
+	}
+	if binary.LittleEndian.Uint32(footer[5:]) != seekableMagic {
+		return nil, ErrNotSeekable
+	}
+	numFrames := int64(binary.LittleEndian.Uint32(footer[0:]))
+	descriptor := footer[4]
+	if descriptor&0x7C != 0 {
+		return nil, fmt.Errorf("invalid seek table descriptor %#x", descriptor)
+	}
+	entrySize := int64(8)
+	if descriptor&checksumFlag != 0 {
+		entrySize = 12
+	}
+
+	tableSize := numFrames*entrySize + footerSize
+	tableOffset := size - tableSize - skippableHeaderSize
+	if tableOffset < 0 {
+		return nil, fmt.Errorf("seek table of %d frames exceeds content size", numFrames)
+	}
+
+	// The frame digests precede the seek table, if present.
+	digestsSize := numFrames * sha256.Size
+	digestsOffset := max(tableOffset-digestsSize-skippableHeaderSize, 0)
+	trailer := make([]byte, size-digestsOffset)
+	if _, err := r.ReadAt(trailer, digestsOffset); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error reading seek table: %w", err)
+	}
+	table := trailer[tableOffset-digestsOffset:]
+	var digests []byte
+	if header := trailer[:tableOffset-digestsOffset]; int64(len(header)) == skippableHeaderSize+digestsSize &&
+		binary.LittleEndian.Uint32(header[0:]) == digestsMagic &&
+		int64(binary.LittleEndian.Uint32(header[4:])) == digestsSize {
+		digests = header[skippableHeaderSize:]
+	} else {
+		digestsOffset, trailer = tableOffset, table
+	}
+
+	if tableDigest != "" {
+		if digests == nil {
+			return nil, fmt.Errorf("%w: content has no frame digests", ErrDigestMismatch)
+		}
+		if actual := tableDigest.Algorithm().FromBytes(trailer); actual != tableDigest {
+			return nil, fmt.Errorf("%w: seek table has digest %s, expected %s", ErrDigestMismatch, actual, tableDigest)
+		}
+	}
+	if binary.LittleEndian.Uint32(table[0:]) != skippableMagic || int64(binary.LittleEndian.Uint32(table[4:])) != tableSize {
+		return nil, fmt.Errorf("invalid seek table frame header")
+	}
+
+	var (
+		entries            = make([]entry, 0, numFrames)
+		compressedOffset   int64
+		decompressedOffset int64
+	)
+	for i := int64(0); i < numFrames; i++ {
+		data := table[skippableHeaderSize+i*entrySize:]
+		e := entry{
+			compressedOffset:   compressedOffset,
+			compressedSize:     int64(binary.LittleEndian.Uint32(data[0:])),
+			decompressedOffset: decompressedOffset,
+			decompressedSize:   int64(binary.LittleEndian.Uint32(data[4:])),
+		}
+		if digests != nil {
+			e.digest = digests[i*sha256.Size : (i+1)*sha256.Size]
+		}
+		compressedOffset += e.compressedSize
+		decompressedOffset += e.decompressedSize
+		entries = append(entries, e)
+	}
+	if compressedOffset != digestsOffset {
+		return nil, fmt.Errorf("seek table frames span %d bytes, expected %d", compressedOffset, digestsOffset)
+	}
+
+	dec, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, fmt.Errorf("error creating zstd decoder: %w", err)
+	}
+	return &Reader{
+		r:       r,
+		entries: entries,
+		size:    decompressedOffset,
+		dec:     dec,
+	}, nil
+}
+
+// Size returns the size of the decompressed content.
+func (r *Reader) Size() int64 {
+	return r.size
+}
+
+// Frames returns the number of frames.
+func (r *Reader) Frames() int {
+	return len(r.entries)
+}
+
+func (r *Reader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+
+	// Find the first frame containing off.
+	i := sort.Search(len(r.entries), func(i int) bool {
+		e := r.entries[i]
+		return e.decompressedOffset+e.decompressedSize > off
+	})
+
+	var n int
+	for n < len(p) && i < len(r.entries) {
+		data, err := r.frame(i)
+		if err != nil {
+			return n, err
+		}
+
+		start := off + int64(n) - r.entries[i].decompressedOffset
+		n += copy(p[n:], data[start:])
+		i++
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Close releases the resources of the reader. It does not close the underlying reader.
+func (r *Reader) Close() error {
+	r.dec.Close()
+	return nil
+}
+
+// frame returns the decompressed data of the i-th frame. The cache is only locked while
+// accessed, so frames are fetched concurrently.
+func (r *Reader) frame(i int) ([]byte, error) {
+	if data, ok := r.cached(i); ok {
+		return data, nil
+	}
+
+	e := r.entries[i]
+	compressed := make([]byte, e.compressedSize)
+	if _, err := r.r.ReadAt(compressed, e.compressedOffset); err != nil {
+		return nil, fmt.Errorf("error reading frame %d: %w", i, err)
+	}
+	data, err := r.dec.DecodeAll(compressed, make([]byte, 0, e.decompressedSize))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing frame %d: %w", i, err)
+	}
+	if int64(len(data)) != e.decompressedSize {
+		return nil, fmt.Errorf("frame %d has %d decompressed bytes, expected %d", i, len(data), e.decompressedSize)
+	}
+	if e.digest != nil {
+		if sum := sha256.Sum256(data); !bytes.Equal(sum[:], e.digest) {
+			return nil, fmt.Errorf("%w: frame %d", ErrDigestMismatch, i)
+		}
+	}
+
+	r.cache(i, data)
+	return data, nil
+}
+
+// cached returns the decompressed data of the i-th frame if it is cached.
+func (r *Reader) cached(i int) ([]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for j, cached := range r.lru {
+		if cached.index == i {
+			// Move the frame to the front.
+			copy(r.lru[1:j+1], r.lru[:j])
+			r.lru[0] = cached
+			return cached.data, true
+		}
+	}
+	return nil, false
+}
+
+// cache adds the decompressed data of the i-th frame to the cache, evicting the least recently used frame.
+func (r *Reader) cache(i int, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.lru) < cachedFrames {
+		r.lru = append(r.lru, cachedFrame{})
+	}
+	copy(r.lru[1:], r.lru)
+	r.lru[0] = cachedFrame{index: i, data: data}
+}
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package seekable_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"slices"
+	"sync"
+
+	. "github.com/ironcore-dev/ironcore-image/seekable"
+	"github.com/klauspost/compress/zstd"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
+)
+
+var _ = Describe("Seekable", func() {
+	const chunkSize = 4096
+
+	var data []byte
+
+	BeforeEach(func() {
+		// Compressible data not aligned to the chunk size.
+		rng := rand.New(rand.NewSource(1))
+		data = make([]byte, 10*chunkSize+123)
+		for i := range data {
+			data[i] = byte('a' + rng.Intn(4))
+		}
+	})
+
+	compressWithDigest := func(data []byte) ([]byte, digest.Digest) {
+		var buf bytes.Buffer
+		tableDigest, err := Compress(&buf, bytes.NewReader(data), chunkSize)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return buf.Bytes(), tableDigest
+	}
+
+	compress := func(data []byte) []byte {
+		compressed, _ := compressWithDigest(data)
+		return compressed
+	}
+
+	It("should produce a deterministic, regular zstd stream", func() {
+		compressed := compress(data)
+		Expect(compress(data)).To(Equal(compressed))
+
+		dec, err := zstd.NewReader(bytes.NewReader(compressed))
+		Expect(err).NotTo(HaveOccurred())
+		defer dec.Close()
+		Expect(io.ReadAll(dec)).To(Equal(data))
+	})
+
+	It("should read arbitrary ranges", func() {
+		compressed := compress(data)
+		r, err := NewReader(bytes.NewReader(compressed), int64(len(compressed)))
+		Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = r.Close() }()
+		Expect(r.Size()).To(Equal(int64(len(data))))
+		Expect(r.Frames()).To(Equal(11))
+
+		By("reading within and across frames")
+		for _, rng := range [][2]int{{0, 10}, {chunkSize - 5, chunkSize + 5}, {100, 3*chunkSize + 7}, {0, len(data)}} {
+			p := make([]byte, rng[1]-rng[0])
+			n, err := r.ReadAt(p, int64(rng[0]))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(len(p)))
+			Expect(p).To(Equal(data[rng[0]:rng[1]]))
+		}
+
+		By("reading beyond the end")
+		p := make([]byte, 200)
+		n, err := r.ReadAt(p, int64(len(data)-100))
+		Expect(err).To(MatchError(io.EOF))
+		Expect(n).To(Equal(100))
+		Expect(p[:n]).To(Equal(data[len(data)-100:]))
+
+		_, err = r.ReadAt(p, int64(len(data)))
+		Expect(err).To(MatchError(io.EOF))
+
+		By("reading the whole content sequentially")
+		Expect(io.ReadAll(io.NewSectionReader(r, 0, r.Size()))).To(Equal(data))
+	})
+
+	It("should verify frames against the table digest", func() {
+		compressed, tableDigest := compressWithDigest(data)
+		r, err := NewVerifiedReader(bytes.NewReader(compressed), int64(len(compressed)), tableDigest)
+		Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = r.Close() }()
+
+		By("reading frames concurrently")
+		var wg sync.WaitGroup
+		for i := range r.Frames() {
+			wg.Go(func() {
+				defer GinkgoRecover()
+				p := make([]byte, 100)
+				_, err := r.ReadAt(p, int64(i*chunkSize))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(p).To(Equal(data[i*chunkSize : i*chunkSize+100]))
+			})
+		}
+		wg.Wait()
+
+		By("rejecting a different table digest")
+		_, err = NewVerifiedReader(bytes.NewReader(compressed), int64(len(compressed)), digest.FromString("other"))
+		Expect(err).To(MatchError(ErrDigestMismatch))
+
+		By("rejecting a frame replaced by another valid frame")
+		enc, err := zstd.NewWriter(nil)
+		Expect(err).NotTo(HaveOccurred())
+		replacement := enc.EncodeAll(bytes.Repeat([]byte("x"), chunkSize), nil)
+		// The replacement is padded to the size of the first frame with a skippable frame.
+		frameSize := len(compress(data[:chunkSize])) - (8 + 32) - (8 + 8 + 9)
+		Expect(len(replacement) + 8).To(BeNumerically("<=", frameSize))
+		tampered := slices.Clone(compressed)
+		copy(tampered, replacement)
+		binary.LittleEndian.PutUint32(tampered[len(replacement):], 0x184D2A50)
+		binary.LittleEndian.PutUint32(tampered[len(replacement)+4:], uint32(frameSize-len(replacement)-8))
+
+		r, err = NewVerifiedReader(bytes.NewReader(tampered), int64(len(tampered)), tableDigest)
+		Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = r.Close() }()
+		_, err = r.ReadAt(make([]byte, 10), 0)
+		Expect(err).To(MatchError(ErrDigestMismatch))
+		_, err = r.ReadAt(make([]byte, 10), chunkSize)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should read content without frame digests", func() {
+		compressed := compress(data)
+		// Remove the frame digests between the frames and the seek table of 11 frames.
+		tableSize := 8 + 11*8 + 9
+		digestsSize := 8 + 11*32
+		stripped := slices.Concat(
+			compressed[:len(compressed)-tableSize-digestsSize],
+			compressed[len(compressed)-tableSize:],
+		)
+
+		r, err := NewReader(bytes.NewReader(stripped), int64(len(stripped)))
+		Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = r.Close() }()
+		Expect(io.ReadAll(io.NewSectionReader(r, 0, r.Size()))).To(Equal(data))
+
+		_, err = NewVerifiedReader(bytes.NewReader(stripped), int64(len(stripped)), digest.FromString("other"))
+		Expect(err).To(MatchError(ErrDigestMismatch))
+	})
+
+	It("should handle empty content", func() {
+		compressed := compress(nil)
+		r, err := NewReader(bytes.NewReader(compressed), int64(len(compressed)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Size()).To(BeZero())
+		Expect(r.Frames()).To(BeZero())
+	})
+
+	It("should reject content without seek table", func() {
+		enc, err := zstd.NewWriter(nil)
+		Expect(err).NotTo(HaveOccurred())
+		compressed := enc.EncodeAll(data, nil)
+
+		_, err = NewReader(bytes.NewReader(compressed), int64(len(compressed)))
+		Expect(err).To(MatchError(ErrNotSeekable))
+	})
+})
@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package uki assembles unified kernel images (UKIs) by adding the kernel, initramfs, kernel
+// command line and os-release as sections to a systemd EFI stub, the way ukify does.
+package uki
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Names of the UKI sections read by the EFI stub.
+const (
+	SectionLinux   = ".linux"
+	SectionInitRD  = ".initrd"
+	SectionCMDLine = ".cmdline"
+	SectionOSRel   = ".osrel"
+)
+
+const (
+	// sectionHeaderSize is the size of a PE section header.
+	sectionHeaderSize = 40
+	// sectionCharacteristics marks sections as initialized, readable data.
+	sectionCharacteristics = pe.IMAGE_SCN_CNT_INITIALIZED_DATA | pe.IMAGE_SCN_MEM_READ
+	// certificateTableEntry is the index of the data directory of the attribute certificate table.
+	certificateTableEntry = 4
+)
+
+// ErrNoRoom is returned if the section table of the EFI stub has no room for the additional sections.
+var ErrNoRoom = errors.New("no room for additional section headers in EFI stub")
+
+// machines are the PE machine types of the EFI stubs by architecture.
+var machines = map[string]uint16{
+	"amd64": pe.IMAGE_FILE_MACHINE_AMD64,
+	"386":   pe.IMAGE_FILE_MACHINE_I386,
+	"arm64": pe.IMAGE_FILE_MACHINE_ARM64,
+	"arm":   pe.IMAGE_FILE_MACHINE_ARMNT,
+}
+
+// Section is a section to add to the EFI stub.
+type Section struct {
+	// Name is the name of the section, at most 8 bytes.
+	Name string
+	// Content is the content of the section.
+	Content io.Reader
+	// Size is the size of the content.
+	Size int64
+}
+
+// CheckArch returns an error if the EFI stub is not built for the given architecture.
+// Unknown architectures are not checked.
+func CheckArch(stub []byte, arch string) error {
+	machine, ok := machines[arch]
+	if !ok {
+		return nil
+	}
+	f, err := pe.NewFile(bytes.NewReader(stub))
+	if err != nil {
+		return fmt.Errorf("error parsing EFI stub: %w", err)
+	}
+	if f.Machine != machine {
+		return fmt.Errorf("EFI stub is built for machine %#x, expected %#x for %s", f.Machine, machine, arch)
+	}
+	return nil
+}
+
+// Assemble writes the EFI stub with the given sections appended to w.
+// Sections are placed in the given order after the sections of the stub. A signature of the stub
+// is dropped, as it is invalidated by adding sections, and the PE checksum is cleared.
+func Assemble(w io.Writer, stub []byte, sections ...Section) error {
+	f, err := pe.NewFile(bytes.NewReader(stub))
+	if err != nil {
+		return fmt.Errorf("error parsing EFI stub: %w", err)
+	}
+
+	var (
+		sectionAlignment, fileAlignment, sizeOfHeaders uint32
+		dataDirectories                                []pe.DataDirectory
+		dataDirectoriesOffset                          int
+	)
+	peOffset := int(binary.LittleEndian.Uint32(stub[0x3c:]))
+	optionalHeaderOffset := peOffset + 4 + binary.Size(pe.FileHeader{})
+	switch oh := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		sectionAlignment, fileAlignment, sizeOfHeaders = oh.SectionAlignment, oh.FileAlignment, oh.SizeOfHeaders
+		dataDirectories, dataDirectoriesOffset = oh.DataDirectory[:oh.NumberOfRvaAndSizes], optionalHeaderOffset+96
+	case *pe.OptionalHeader64:
+		sectionAlignment, fileAlignment, sizeOfHeaders = oh.SectionAlignment, oh.FileAlignment, oh.SizeOfHeaders
+		dataDirectories, dataDirectoriesOffset = oh.DataDirectory[:oh.NumberOfRvaAndSizes], optionalHeaderOffset+112
+	default:
+		return fmt.Errorf("EFI stub has no optional header")
+	}
+	if sectionAlignment == 0 || fileAlignment == 0 {
+		return fmt.Errorf("EFI stub has invalid section alignment %d / file alignment %d", sectionAlignment, fileAlignment)
+	}
+
+	// The new section headers have to fit between the existing ones and the first section data.
+	sectionTableOffset := optionalHeaderOffset + int(f.SizeOfOptionalHeader)
+	headersEnd := uint32(sectionTableOffset + sectionHeaderSize*(len(f.Sections)+len(sections)))
+	var virtualEnd, dataEnd uint32
+	for _, s := range f.Sections {
+		if slices.ContainsFunc(sections, func(section Section) bool { return section.Name == s.Name }) {
+			return fmt.Errorf("EFI stub already contains a %s section", s.Name)
+		}
+		virtualEnd = max(virtualEnd, s.VirtualAddress+s.VirtualSize)
+		if s.Size == 0 {
+			continue
+		}
+		if s.Offset < headersEnd {
+			return ErrNoRoom
+		}
+		dataEnd = max(dataEnd, s.Offset+s.Size)
+	}
+	if headersEnd > sizeOfHeaders {
+		return ErrNoRoom
+	}
+
+	if int(max(dataEnd, sizeOfHeaders)) > len(stub) {
+		return fmt.Errorf("EFI stub is truncated")
+	}
+
+	out := slices.Clone(stub[:max(dataEnd, sizeOfHeaders)])
+	offset := alignUp(uint64(len(out)), fileAlignment)
+	address := alignUp(uint64(virtualEnd), sectionAlignment)
+	var initializedData uint64
+	for i, section := range sections {
+		if len(section.Name) > 8 {
+			return fmt.Errorf("section name %s is longer than 8 bytes", section.Name)
+		}
+		rawSize := alignUp(uint64(section.Size), fileAlignment)
+		if address+uint64(section.Size) > 0xFFFFFFFF || offset+rawSize > 0xFFFFFFFF {
+			return fmt.Errorf("section %s exceeds the PE size limit", section.Name)
+		}
+
+		header := pe.SectionHeader32{
+			VirtualSize:      uint32(section.Size),
+			VirtualAddress:   uint32(address),
+			SizeOfRawData:    uint32(rawSize),
+			PointerToRawData: uint32(offset),
+			Characteristics:  sectionCharacteristics,
+		}
+		copy(header.Name[:], section.Name)
+		headerOffset := sectionTableOffset + sectionHeaderSize*(len(f.Sections)+i)
+		if _, err := binary.Encode(out[headerOffset:], binary.LittleEndian, header); err != nil {
+			return fmt.Errorf("error encoding section header: %w", err)
+		}
+
+		offset += rawSize
+		address = alignUp(address+uint64(section.Size), sectionAlignment)
+		initializedData += rawSize
+	}
+
+	binary.LittleEndian.PutUint16(out[peOffset+4+2:], uint16(len(f.Sections)+len(sections)))
+	// The COFF symbol table is not part of the sections, so it is dropped along with other trailing data.
+	binary.LittleEndian.PutUint32(out[peOffset+4+8:], 0)
+	binary.LittleEndian.PutUint32(out[peOffset+4+12:], 0)
+	binary.LittleEndian.PutUint32(out[optionalHeaderOffset+8:],
+		binary.LittleEndian.Uint32(out[optionalHeaderOffset+8:])+uint32(initializedData))
+	binary.LittleEndian.PutUint32(out[optionalHeaderOffset+56:], uint32(address))
+	binary.LittleEndian.PutUint32(out[optionalHeaderOffset+64:], 0)
+	if len(dataDirectories) > certificateTableEntry {
+		clear(out[dataDirectoriesOffset+8*certificateTableEntry : dataDirectoriesOffset+8*(certificateTableEntry+1)])
+	}
+
+	if _, err := w.Write(out); err != nil {
+		return err
+	}
+	written := uint64(len(out))
+	for _, section := range sections {
+		if err := pad(w, alignUp(written, fileAlignment)-written); err != nil {
+			return err
+		}
+		if n, err := io.CopyN(w, section.Content, section.Size); err != nil {
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("section %s is shorter than %d bytes (%d)", section.Name, section.Size, n)
+			}
+			return fmt.Errorf("error writing section %s: %w", section.Name, err)
+		}
+		written = alignUp(written, fileAlignment) + uint64(section.Size)
+	}
+	return pad(w, alignUp(written, fileAlignment)-written)
+}
+
+func alignUp(n uint64, alignment uint32) uint64 {
+	a := uint64(alignment)
+	return (n + a - 1) / a * a
+}
+
+func pad(w io.Writer, n uint64) error {
+	_, err := w.Write(make([]byte, n))
+	return err
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package uki_test
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"io"
+	"strings"
+
+	. "github.com/ironcore-dev/ironcore-image/uki"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// stub returns a minimal PE32+ EFI application with a single .text section.
+func stub(machine uint16, sizeOfHeaders uint32) []byte {
+	var buf bytes.Buffer
+	dos := make([]byte, 0x40)
+	copy(dos, "MZ")
+	binary.LittleEndian.PutUint32(dos[0x3c:], 0x40)
+	buf.Write(dos)
+	buf.WriteString("PE\x00\x00")
+
+	write := func(v any) { Expect(binary.Write(&buf, binary.LittleEndian, v)).To(Succeed()) }
+	write(pe.FileHeader{
+		Machine:              machine,
+		NumberOfSections:     1,
+		SizeOfOptionalHeader: uint16(binary.Size(pe.OptionalHeader64{})),
+		Characteristics:      pe.IMAGE_FILE_EXECUTABLE_IMAGE,
+	})
+	write(pe.OptionalHeader64{
+		Magic:               0x20b,
+		SizeOfCode:          0x200,
+		AddressOfEntryPoint: 0x1000,
+		BaseOfCode:          0x1000,
+		SectionAlignment:    0x1000,
+		FileAlignment:       0x200,
+		SizeOfImage:         0x2000,
+		SizeOfHeaders:       sizeOfHeaders,
+		Subsystem:           pe.IMAGE_SUBSYSTEM_EFI_APPLICATION,
+		NumberOfRvaAndSizes: 16,
+		DataDirectory: [16]pe.DataDirectory{
+			pe.IMAGE_DIRECTORY_ENTRY_SECURITY: {VirtualAddress: 0x600, Size: 8},
+		},
+	})
+	write(pe.SectionHeader32{
+		Name:             [8]uint8{'.', 't', 'e', 'x', 't'},
+		VirtualSize:      0x100,
+		VirtualAddress:   0x1000,
+		SizeOfRawData:    0x200,
+		PointerToRawData: sizeOfHeaders,
+		Characteristics:  pe.IMAGE_SCN_CNT_CODE | pe.IMAGE_SCN_MEM_EXECUTE | pe.IMAGE_SCN_MEM_READ,
+	})
+	buf.Write(make([]byte, int(sizeOfHeaders)-buf.Len()))
+	buf.Write(bytes.Repeat([]byte{0xc3}, 0x200))
+	// Signature appended to the stub.
+	buf.WriteString("signatur")
+	return buf.Bytes()
+}
+
+func section(name, content string) Section {
+	return Section{Name: name, Content: strings.NewReader(content), Size: int64(len(content))}
+}
+
+var _ = Describe("UKI", func() {
+	It("should append the sections to the stub", func() {
+		linux := strings.Repeat("k", 0x1234)
+		var buf bytes.Buffer
+		Expect(Assemble(&buf, stub(pe.IMAGE_FILE_MACHINE_AMD64, 0x400),
+			section(SectionOSRel, "ID=test\n"),
+			section(SectionCMDLine, "console=ttyS0"),
+			section(SectionInitRD, "initrd"),
+			section(SectionLinux, linux),
+		)).To(Succeed())
+		Expect(buf.Len() % 0x200).To(BeZero())
+
+		f, err := pe.NewFile(bytes.NewReader(buf.Bytes()))
+		Expect(err).NotTo(HaveOccurred())
+		var names []string
+		for _, s := range f.Sections {
+			names = append(names, s.Name)
+		}
+		Expect(names).To(Equal([]string{".text", SectionOSRel, SectionCMDLine, SectionInitRD, SectionLinux}))
+
+		contents := map[string]string{SectionOSRel: "ID=test\n", SectionCMDLine: "console=ttyS0", SectionInitRD: "initrd", SectionLinux: linux}
+		for _, s := range f.Sections[1:] {
+			Expect(s.VirtualAddress % 0x1000).To(BeZero())
+			Expect(s.Offset % 0x200).To(BeZero())
+			data, err := io.ReadAll(io.NewSectionReader(s, 0, int64(s.VirtualSize)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(contents[s.Name]))
+		}
+		text, err := f.Sections[0].Data()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(text).To(Equal(bytes.Repeat([]byte{0xc3}, 0x200)))
+
+		oh := f.OptionalHeader.(*pe.OptionalHeader64)
+		Expect(oh.SizeOfImage).To(BeEquivalentTo(0x7000))
+		Expect(oh.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_SECURITY]).To(BeZero())
+	})
+
+	It("should fail if there is no room for the section headers", func() {
+		Expect(Assemble(io.Discard, stub(pe.IMAGE_FILE_MACHINE_AMD64, 0x200),
+			section(SectionOSRel, "1"), section(SectionCMDLine, "2"), section(SectionInitRD, "3"), section(SectionLinux, "4"),
+		)).To(MatchError(ErrNoRoom))
+	})
+
+	It("should fail for sections already contained in the stub", func() {
+		Expect(Assemble(io.Discard, stub(pe.IMAGE_FILE_MACHINE_AMD64, 0x400), section(".text", "text"))).
+			To(MatchError(ContainSubstring("already contains")))
+	})
+
+	It("should check the architecture of the stub", func() {
+		Expect(CheckArch(stub(pe.IMAGE_FILE_MACHINE_AMD64, 0x400), "amd64")).To(Succeed())
+		Expect(CheckArch(stub(pe.IMAGE_FILE_MACHINE_AMD64, 0x400), "arm64")).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package fsutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Clone makes the file at src available at dst without copying its data.
+// A reflink (copy-on-write clone) is attempted first, falling back to a hardlink.
+// If neither is possible, an error is returned and the caller has to copy the data.
+//
+// dst must not exist.
+func Clone(src, dst string) error {
+	reflinkErr := Reflink(src, dst)
+	if reflinkErr == nil {
+		return nil
+	}
+
+	if err := os.Link(src, dst); err != nil {
+		return fmt.Errorf("error cloning %s to %s: %w", src, dst, errors.Join(reflinkErr, err))
+	}
+	return nil
+}
+
+// WriteFileAtomic writes data to the file at path with the permissions perm, regardless of the umask.
+// The data is written to a temporary file that is renamed to path, so readers either see the old or
+// the new content.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
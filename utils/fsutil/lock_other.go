@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !unix
+
+package fsutil
+
+// Lock is not supported on this platform. It does not lock anything and always succeeds.
+func Lock(path string) (unlock func() error, err error) {
+	return func() error { return nil }, nil
+}
+
+// RLock is not supported on this platform. It does not lock anything and always succeeds.
+func RLock(path string) (unlock func() error, err error) {
+	return func() error { return nil }, nil
+}
@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unix
+
+package fsutil
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// Lock takes an exclusive advisory lock on the file at path, creating it if it does not exist.
+// It blocks until the lock is acquired. The lock is held across processes until unlock is called.
+func Lock(path string) (unlock func() error, err error) {
+	return flock(path, unix.LOCK_EX)
+}
+
+// RLock takes a shared advisory lock on the file at path like Lock. Shared locks can be held
+// concurrently, but not together with an exclusive lock.
+func RLock(path string) (unlock func() error, err error) {
+	return flock(path, unix.LOCK_SH)
+}
+
+func flock(path string, how int) (func() error, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		err = unix.Flock(int(f.Fd()), how)
+		if err != unix.EINTR {
+			break
+		}
+	}
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("error locking %s: %w", path, err)
+	}
+	// Closing the file releases the lock.
+	return f.Close, nil
+}
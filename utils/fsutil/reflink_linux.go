@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package fsutil
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// Reflink creates a copy-on-write clone of src at dst.
+// It only succeeds on file systems supporting FICLONE (e.g. btrfs, xfs).
+func Reflink(src, dst string) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = srcFile.Close() }()
+
+	dstFile, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+
+	if err := unix.IoctlFileClone(int(dstFile.Fd()), int(srcFile.Fd())); err != nil {
+		_ = dstFile.Close()
+		_ = os.Remove(dst)
+		return fmt.Errorf("error reflinking %s: %w", src, err)
+	}
+	return dstFile.Close()
+}
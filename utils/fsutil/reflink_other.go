@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package fsutil
+
+import (
+	"errors"
+)
+
+// Reflink is not supported on this platform and always returns errors.ErrUnsupported.
+func Reflink(src, dst string) error {
+	return errors.ErrUnsupported
+}